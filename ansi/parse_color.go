@@ -0,0 +1,126 @@
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColorNames are the names of the standard colors.
+var ColorNames = map[Color]string{
+	ColorBlack:  "black",
+	ColorRed:    "red",
+	ColorGreen:  "green",
+	ColorYellow: "yellow",
+	ColorBlue:   "blue",
+	ColorPurple: "purple",
+	ColorCyan:   "cyan",
+	ColorWhite:  "white",
+
+	ColorLightBlack:  "light-black",
+	ColorLightRed:    "light-red",
+	ColorLightGreen:  "light-green",
+	ColorLightYellow: "light-yellow",
+	ColorLightBlue:   "light-blue",
+	ColorLightPurple: "light-purple",
+	ColorLightCyan:   "light-cyan",
+	ColorLightWhite:  "light-white",
+
+	ColorBackgroundBlack:  "background-black",
+	ColorBackgroundRed:    "background-red",
+	ColorBackgroundGreen:  "background-green",
+	ColorBackgroundYellow: "background-yellow",
+	ColorBackgroundBlue:   "background-blue",
+	ColorBackgroundPurple: "background-purple",
+	ColorBackgroundCyan:   "background-cyan",
+	ColorBackgroundWhite:  "background-white",
+
+	ColorBackgroundBrightBlack:  "background-bright-black",
+	ColorBackgroundBrightRed:    "background-bright-red",
+	ColorBackgroundBrightGreen:  "background-bright-green",
+	ColorBackgroundBrightYellow: "background-bright-yellow",
+	ColorBackgroundBrightBlue:   "background-bright-blue",
+	ColorBackgroundBrightPurple: "background-bright-purple",
+	ColorBackgroundBrightCyan:   "background-bright-cyan",
+	ColorBackgroundBrightWhite:  "background-bright-white",
+}
+
+// colorsByName is the reverse of `ColorNames` keyed by normalized name, including aliases.
+var colorsByName = func() map[string]Color {
+	output := map[string]Color{
+		"magenta":      ColorPurple,
+		"gray":         ColorLightBlack,
+		"grey":         ColorLightBlack,
+		"lightmagenta": ColorLightPurple,
+	}
+	for color, name := range ColorNames {
+		output[normalizeColorName(name)] = color
+	}
+	return output
+}()
+
+// Color256 returns a color from the 256 color palette.
+func Color256(index uint8) Color {
+	return Color(fmt.Sprintf("38;5;%dm", index))
+}
+
+// ColorRGB returns a 24 bit (true color) color.
+func ColorRGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("38;2;%d;%d;%dm", r, g, b))
+}
+
+// ParseColor parses a color from a name in `ColorNames`, or from `256:<index>` or `rgb:<r>,<g>,<b>`.
+// Names are matched regardless of case or separators; it returns false if the name isn't recognized.
+func ParseColor(name string) (Color, bool) {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "256:"):
+		index, err := strconv.ParseUint(strings.TrimSpace(name[len("256:"):]), 10, 8)
+		if err != nil {
+			return "", false
+		}
+		return Color256(uint8(index)), true
+	case strings.HasPrefix(lower, "rgb:"):
+		components := strings.Split(name[len("rgb:"):], ",")
+		if len(components) != 3 {
+			return "", false
+		}
+		var values [3]uint8
+		for index, component := range components {
+			value, err := strconv.ParseUint(strings.TrimSpace(component), 10, 8)
+			if err != nil {
+				return "", false
+			}
+			values[index] = uint8(value)
+		}
+		return ColorRGB(values[0], values[1], values[2]), true
+	default:
+		color, ok := colorsByName[normalizeColorName(name)]
+		return color, ok
+	}
+}
+
+// ColorName returns the name of a color as accepted by `ParseColor`.
+// It returns an empty string if the color isn't a standard or extended color.
+func ColorName(c Color) string {
+	if name, ok := ColorNames[c]; ok {
+		return name
+	}
+	code := strings.TrimSuffix(string(c), "m")
+	if code == string(c) {
+		return ""
+	}
+	parts := strings.Split(code, ";")
+	if len(parts) == 3 && parts[0] == "38" && parts[1] == "5" {
+		return "256:" + parts[2]
+	}
+	if len(parts) == 5 && parts[0] == "38" && parts[1] == "2" {
+		return "rgb:" + strings.Join(parts[2:], ",")
+	}
+	return ""
+}
+
+func normalizeColorName(name string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+}
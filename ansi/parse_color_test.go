@@ -0,0 +1,58 @@
+package ansi
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestParseColorStandard(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Len(ColorNames, 32)
+	for color, name := range ColorNames {
+		parsed, ok := ParseColor(name)
+		assert.True(ok, name)
+		assert.Equal(color, parsed, name)
+		assert.Equal(name, ColorName(parsed))
+	}
+
+	parsed, ok := ParseColor("LightRed")
+	assert.True(ok)
+	assert.Equal(ColorLightRed, parsed)
+
+	parsed, ok = ParseColor(" light_red ")
+	assert.True(ok)
+	assert.Equal(ColorLightRed, parsed)
+
+	parsed, ok = ParseColor("magenta")
+	assert.True(ok)
+	assert.Equal(ColorPurple, parsed)
+}
+
+func TestParseColorExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	parsed, ok := ParseColor("256:208")
+	assert.True(ok)
+	assert.Equal(Color("38;5;208m"), parsed)
+	assert.Equal("256:208", ColorName(parsed))
+
+	parsed, ok = ParseColor("RGB:255, 128,0")
+	assert.True(ok)
+	assert.Equal(ColorRGB(255, 128, 0), parsed)
+	assert.Equal("rgb:255,128,0", ColorName(parsed))
+	assert.Equal("\033[0;38;2;255;128;0mtest"+ColorReset, parsed.Apply("test"))
+}
+
+func TestParseColorInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, name := range []string{"", "chartreuse", "256:", "256:256", "256:-1", "rgb:1,2", "rgb:1,2,300", "rgb:a,b,c"} {
+		parsed, ok := ParseColor(name)
+		assert.False(ok, name)
+		assert.Empty(parsed, name)
+	}
+	assert.Empty(ColorName(Color("1m")))
+	assert.Empty(ColorName(Color("")))
+}
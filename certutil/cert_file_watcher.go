@@ -3,6 +3,7 @@ package certutil
 import (
 	"crypto/tls"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/blend/go-sdk/async"
@@ -60,11 +61,13 @@ func OptCertFileWatcherPollInterval(d time.Duration) CertFileWatcherOption {
 	}
 }
 
-// CertFileWatcher reloads a cert key pair when there is a change, e.g. cert renewal
+// CertFileWatcher reloads a cert key pair when there is a change, e.g. cert renewal.
+// If a reload fails the previous certificate is kept and the error is passed to `OnReload`.
 type CertFileWatcher struct {
 	*async.Latch
 
-	Certificate *tls.Certificate
+	Certificate   *tls.Certificate
+	certificateMu sync.RWMutex
 
 	CertPath     string
 	KeyPath      string
@@ -94,12 +97,16 @@ func (cw *CertFileWatcher) Reload() (err error) {
 		err = ex.New(loadErr)
 		return
 	}
+	cw.certificateMu.Lock()
 	cw.Certificate = &cert
+	cw.certificateMu.Unlock()
 	return
 }
 
 // GetCertificate gets the cached certificate, it blocks when the `cert` field is being updated
 func (cw *CertFileWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cw.certificateMu.RLock()
+	defer cw.certificateMu.RUnlock()
 	return cw.Certificate, nil
 }
 
@@ -120,12 +127,13 @@ func (cw *CertFileWatcher) Start() error {
 		case <-ticker:
 			certMod, keyMod, err = cw.keyPairLastModified()
 			if err != nil {
-				return err
+				// the files may be briefly missing while they're replaced.
+				continue
 			}
 			if keyMod.After(keyLastMod) || certMod.After(certLastMod) {
-				if err = cw.Reload(); err != nil {
-					return err
-				}
+				// a failed reload keeps the previous certificate and is
+				// reported to the reload handler; it is retried on the next change.
+				_ = cw.Reload()
 				keyLastMod = keyMod
 				certLastMod = certMod
 			}
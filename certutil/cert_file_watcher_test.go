@@ -1,9 +1,14 @@
 package certutil
 
 import (
+	"crypto/tls"
+	"encoding/pem"
 	"io/ioutil"
+	"net"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
 )
@@ -39,3 +44,67 @@ func TestCertFileWatcher(t *testing.T) {
 	assert.Nil(w.Reload())
 	assert.NotNil(w.Certificate)
 }
+
+func TestCertFileWatcherReloads(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "certutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	assert.Nil(ioutil.WriteFile(certPath, certLiteral, 0600))
+	assert.Nil(ioutil.WriteFile(keyPath, keyLiteral, 0600))
+
+	reloads := make(chan error, 8)
+	w, err := NewCertFileWatcher(certPath, keyPath,
+		OptCertFileWatcherPollInterval(10*time.Millisecond),
+		OptCertFileWatcherOnReload(func(_ *CertFileWatcher, err error) { reloads <- err }),
+	)
+	assert.Nil(err)
+	assert.Nil(<-reloads)
+	original := handshakeLeaf(assert, w)
+
+	go w.Start()
+	<-w.NotifyStarted()
+	defer w.Stop()
+
+	// a mismatched key pair should keep the previous certificate.
+	serverCert, err := ioutil.ReadFile("testdata/server.cert.pem")
+	assert.Nil(err)
+	assert.Nil(ioutil.WriteFile(certPath, serverCert, 0600))
+	touch(assert, certPath, time.Now().Add(time.Second))
+	assert.NotNil(<-reloads)
+	assert.Equal(original, handshakeLeaf(assert, w))
+
+	serverKey, err := ioutil.ReadFile("testdata/server.key.pem")
+	assert.Nil(err)
+	assert.Nil(ioutil.WriteFile(keyPath, serverKey, 0600))
+	touch(assert, keyPath, time.Now().Add(2*time.Second))
+	assert.Nil(<-reloads)
+
+	block, _ := pem.Decode(serverCert)
+	assert.Equal(block.Bytes, handshakeLeaf(assert, w), "new handshakes should use the reloaded certificate")
+}
+
+func touch(assert *assert.Assertions, path string, modTime time.Time) {
+	assert.Nil(os.Chtimes(path, modTime, modTime))
+}
+
+// handshakeLeaf performs a tls handshake with the watcher as the server
+// certificate source, and returns the raw leaf certificate the client saw.
+func handshakeLeaf(assert *assert.Assertions, w *CertFileWatcher) []byte {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := tls.Server(serverConn, &tls.Config{GetCertificate: w.GetCertificate})
+	go server.Handshake()
+
+	client := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true})
+	assert.Nil(client.Handshake())
+	peers := client.ConnectionState().PeerCertificates
+	assert.NotEmpty(peers)
+	return peers[0].Raw
+}
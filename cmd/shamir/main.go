@@ -98,6 +98,9 @@ func NewCombineCommand() *cobra.Command {
 			}
 
 			for _, part := range inputPartsEncoded {
+				if strings.TrimSpace(part) == "" {
+					continue
+				}
 				decoded, err := hex.DecodeString(strings.TrimSpace(part))
 				if err != nil {
 					fmt.Fprintln(os.Stderr, err)
@@ -146,6 +146,11 @@ func (e *Ex) Error() string {
 	return e.Class.Error()
 }
 
+// Unwrap returns the inner error, if any, for use with `errors.Is` and `errors.As`.
+func (e *Ex) Unwrap() error {
+	return e.Inner
+}
+
 // Decompose breaks the exception down to be marshalled into an intermediate format.
 func (e *Ex) Decompose() map[string]interface{} {
 	values := map[string]interface{}{}
@@ -214,3 +214,12 @@ func TestExceptionPrintsInner(t *testing.T) {
 	assert.Contains(output, "middle")
 	assert.Contains(output, "terminal")
 }
+
+func TestExceptionUnwrap(t *testing.T) {
+	assert := assert.New(t)
+
+	inner := errors.New("inner")
+	err := New("outer", OptInnerClass(inner))
+	assert.Equal(inner, err.(*Ex).Unwrap())
+	assert.Nil(New("terminal").(*Ex).Unwrap())
+}
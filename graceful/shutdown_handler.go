@@ -0,0 +1,216 @@
+package graceful
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// ErrGracePeriodExpired is returned if the hosted processes did not stop within the grace period.
+	ErrGracePeriodExpired ex.Class = "graceful; shutdown grace period expired"
+	// ErrForcedExit is returned if a second signal was received while draining and the exit handler returned.
+	ErrForcedExit ex.Class = "graceful; forced exit"
+)
+
+// Logger is the subset of a logger used to report shutdown progress.
+// It is satisfied by `logger.Log`.
+type Logger interface {
+	Infof(format string, args ...interface{})
+}
+
+// NewShutdownHandler returns a new shutdown handler.
+// By default it listens for SIGINT and SIGTERM, and exits the process
+// if a second signal is received while draining.
+func NewShutdownHandler(options ...ShutdownHandlerOption) *ShutdownHandler {
+	sh := &ShutdownHandler{
+		Exit: os.Exit,
+	}
+	for _, option := range options {
+		option(sh)
+	}
+	return sh
+}
+
+// ShutdownHandlerOption mutates a shutdown handler.
+type ShutdownHandlerOption func(*ShutdownHandler)
+
+// OptShutdownSignal sets the channel the handler receives signals on.
+// If unset, the handler is notified of SIGINT and SIGTERM from the os.
+func OptShutdownSignal(signals chan os.Signal) ShutdownHandlerOption {
+	return func(sh *ShutdownHandler) { sh.Signal = signals }
+}
+
+// OptShutdownGracePeriod sets the maximum time to wait for the hosted processes to stop.
+func OptShutdownGracePeriod(gracePeriod time.Duration) ShutdownHandlerOption {
+	return func(sh *ShutdownHandler) { sh.GracePeriod = gracePeriod }
+}
+
+// OptShutdownPreStop sets a hook that is called before the hosted processes are stopped.
+func OptShutdownPreStop(preStop func() error) ShutdownHandlerOption {
+	return func(sh *ShutdownHandler) { sh.PreStop = preStop }
+}
+
+// OptShutdownPreStopDelay sets a delay between calling the pre-stop hook and stopping the hosted processes.
+func OptShutdownPreStopDelay(delay time.Duration) ShutdownHandlerOption {
+	return func(sh *ShutdownHandler) { sh.PreStopDelay = delay }
+}
+
+// OptShutdownLog sets the logger.
+func OptShutdownLog(log Logger) ShutdownHandlerOption {
+	return func(sh *ShutdownHandler) { sh.Log = log }
+}
+
+// OptShutdownExit sets the function called to force an exit if a second signal is received.
+func OptShutdownExit(exit func(int)) ShutdownHandlerOption {
+	return func(sh *ShutdownHandler) { sh.Exit = exit }
+}
+
+// ShutdownHandler calls `PreStop`, waits `PreStopDelay`, then stops the hosted processes within `GracePeriod`.
+// A second signal received while draining forces an immediate exit.
+type ShutdownHandler struct {
+	Signal       chan os.Signal
+	GracePeriod  time.Duration
+	PreStop      func() error
+	PreStopDelay time.Duration
+	Log          Logger
+	Exit         func(int)
+}
+
+// Shutdown starts the hosted processes and stops them when a signal is received.
+// A "Graceful" processes *must* block on start.
+func (sh *ShutdownHandler) Shutdown(hosted ...Graceful) error {
+	shouldShutdown := sh.Signal
+	if shouldShutdown == nil {
+		shouldShutdown = make(chan os.Signal, 2)
+		signal.Notify(shouldShutdown, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(shouldShutdown)
+	}
+
+	shutdown := make(chan struct{})
+	abortWaitShutdown := make(chan struct{})
+	serverExited := make(chan struct{})
+
+	waitShutdownComplete := sync.WaitGroup{}
+	waitShutdownComplete.Add(len(hosted))
+
+	waitServerExited := sync.WaitGroup{}
+	waitServerExited.Add(len(hosted))
+
+	errors := make(chan error, 2*len(hosted)+1)
+
+	for _, hostedInstance := range hosted {
+		// start the hosted instance
+		go func(instance Graceful) {
+			defer func() {
+				safely(func() { close(serverExited) }) // close the emergency crash channel, but do so safely
+				waitServerExited.Done()                // signal the normal exit process is done
+			}()
+
+			// `hosted.Start()` should block here.
+			if err := instance.Start(); err != nil {
+				errors <- err
+			}
+			return
+		}(hostedInstance)
+
+		go func(instance Graceful) {
+			defer waitShutdownComplete.Done()
+
+			select {
+			case <-shutdown:
+				// tell the hosted process to stop "gracefully"
+				if err := instance.Stop(); err != nil {
+					errors <- err
+				}
+				return
+			case <-abortWaitShutdown: // a server has exited on its own
+				return // clean up this goroutine
+			}
+		}(hostedInstance)
+	}
+
+	select {
+	case <-shouldShutdown: // if we've issued a shutdown, wait for the server to exit
+		if err := sh.preStop(); err != nil {
+			errors <- err
+		}
+		close(shutdown)
+
+		drained := make(chan struct{})
+		go func() {
+			waitShutdownComplete.Wait()
+			waitServerExited.Wait()
+			close(drained)
+		}()
+		if err := sh.drain(shouldShutdown, drained); err != nil {
+			return err
+		}
+	case <-serverExited: // if any of the servers exited on their own
+		close(abortWaitShutdown) // quit the signal listener
+		waitShutdownComplete.Wait()
+	}
+	if len(errors) > 0 {
+		return <-errors
+	}
+	return nil
+}
+
+// drain is called once the hosted processes have been told to stop and waits for them to finish.
+func (sh *ShutdownHandler) drain(shouldShutdown chan os.Signal, drained <-chan struct{}) error {
+	sh.infof("graceful; draining")
+
+	var gracePeriodExpired <-chan time.Time
+	if sh.GracePeriod > 0 {
+		timer := time.NewTimer(sh.GracePeriod)
+		defer timer.Stop()
+		gracePeriodExpired = timer.C
+	}
+
+	for {
+		select {
+		case <-drained:
+			sh.infof("graceful; drain complete")
+			return nil
+		case _, ok := <-shouldShutdown:
+			if !ok {
+				// the signal channel was closed, stop listening on it.
+				shouldShutdown = nil
+				continue
+			}
+			if sh.Exit != nil {
+				sh.infof("graceful; second signal received, forcing exit")
+				sh.Exit(1)
+				return ex.New(ErrForcedExit)
+			}
+		case <-gracePeriodExpired:
+			sh.infof("graceful; grace period expired")
+			return ex.New(ErrGracePeriodExpired, ex.OptMessagef("grace period: %v", sh.GracePeriod))
+		}
+	}
+}
+
+// preStop is called before the hosted processes are told to stop.
+func (sh *ShutdownHandler) preStop() error {
+	sh.infof("graceful; shutdown signal received")
+	var err error
+	if sh.PreStop != nil {
+		sh.infof("graceful; calling pre-stop hook")
+		err = sh.PreStop()
+	}
+	if sh.PreStopDelay > 0 {
+		sh.infof("graceful; waiting %v before stopping", sh.PreStopDelay)
+		time.Sleep(sh.PreStopDelay)
+	}
+	return err
+}
+
+func (sh *ShutdownHandler) infof(format string, args ...interface{}) {
+	if sh.Log != nil {
+		sh.Log.Infof(format, args...)
+	}
+}
@@ -0,0 +1,175 @@
+package graceful
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func newBlockingHosted() *blockingHosted {
+	return &blockingHosted{
+		started:  make(chan struct{}),
+		stopping: make(chan struct{}),
+		stopped:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+}
+
+// blockingHosted blocks on start until it is stopped,
+// and blocks on stop until it is released.
+type blockingHosted struct {
+	started  chan struct{}
+	stopping chan struct{}
+	stopped  chan struct{}
+	release  chan struct{}
+}
+
+func (bh *blockingHosted) Start() error {
+	close(bh.started)
+	<-bh.stopped
+	return nil
+}
+
+func (bh *blockingHosted) Stop() error {
+	close(bh.stopping)
+	<-bh.release
+	close(bh.stopped)
+	return nil
+}
+
+func (bh *blockingHosted) NotifyStarted() <-chan struct{} { return bh.started }
+func (bh *blockingHosted) NotifyStopped() <-chan struct{} { return bh.stopped }
+
+type mockLog struct {
+	sync.Mutex
+	messages []string
+}
+
+func (ml *mockLog) Infof(format string, args ...interface{}) {
+	ml.Lock()
+	defer ml.Unlock()
+	ml.messages = append(ml.messages, fmt.Sprintf(format, args...))
+}
+
+func TestShutdownHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	hosted := newBlockingHosted()
+	close(hosted.release)
+
+	var preStopCalled bool
+	log := new(mockLog)
+	signals := make(chan os.Signal, 1)
+	sh := NewShutdownHandler(
+		OptShutdownSignal(signals),
+		OptShutdownLog(log),
+		OptShutdownPreStop(func() error {
+			preStopCalled = true
+			return nil
+		}),
+	)
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = sh.Shutdown(hosted)
+		close(done)
+	}()
+	<-hosted.NotifyStarted()
+	signals <- os.Interrupt
+	<-done
+
+	assert.Nil(err)
+	assert.True(preStopCalled)
+	assert.Equal([]string{
+		"graceful; shutdown signal received",
+		"graceful; calling pre-stop hook",
+		"graceful; draining",
+		"graceful; drain complete",
+	}, log.messages)
+}
+
+func TestShutdownHandlerPreStopError(t *testing.T) {
+	assert := assert.New(t)
+
+	hosted := newBlockingHosted()
+	close(hosted.release)
+
+	signals := make(chan os.Signal, 1)
+	sh := NewShutdownHandler(
+		OptShutdownSignal(signals),
+		OptShutdownPreStop(func() error { return fmt.Errorf("this is only a test") }),
+	)
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = sh.Shutdown(hosted)
+		close(done)
+	}()
+	<-hosted.NotifyStarted()
+	signals <- os.Interrupt
+	<-done
+
+	assert.NotNil(err)
+	assert.Equal("this is only a test", err.Error())
+}
+
+func TestShutdownHandlerGracePeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	hosted := newBlockingHosted()
+	defer close(hosted.release)
+
+	signals := make(chan os.Signal, 1)
+	sh := NewShutdownHandler(
+		OptShutdownSignal(signals),
+		OptShutdownGracePeriod(time.Millisecond),
+	)
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = sh.Shutdown(hosted)
+		close(done)
+	}()
+	<-hosted.NotifyStarted()
+	signals <- os.Interrupt
+	<-done
+
+	assert.True(ex.Is(err, ErrGracePeriodExpired))
+}
+
+func TestShutdownHandlerSecondSignal(t *testing.T) {
+	assert := assert.New(t)
+
+	hosted := newBlockingHosted()
+	defer close(hosted.release)
+
+	exitCode := make(chan int, 1)
+	signals := make(chan os.Signal, 1)
+	sh := NewShutdownHandler(
+		OptShutdownSignal(signals),
+		OptShutdownExit(func(code int) { exitCode <- code }),
+	)
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = sh.Shutdown(hosted)
+		close(done)
+	}()
+	<-hosted.NotifyStarted()
+	signals <- os.Interrupt
+	<-hosted.stopping
+	signals <- os.Interrupt
+	<-done
+
+	assert.Equal(1, <-exitCode)
+	assert.True(ex.Is(err, ErrForcedExit))
+}
@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"encoding/json"
+)
+
+// MissingEventInterfaces returns the names of the interfaces the built-in events implement
+// that a given event doesn't, i.e. `TextWritable` and `json.Marshaler`.
+// Events missing them are still written, but only as a `fmt.Stringer` in text form, or with
+// their exported fields in json form.
+func MissingEventInterfaces(e Event) (missing []string) {
+	if _, ok := e.(TextWritable); !ok {
+		missing = append(missing, "logger.TextWritable")
+	}
+	if _, ok := e.(json.Marshaler); !ok {
+		missing = append(missing, "json.Marshaler")
+	}
+	return
+}
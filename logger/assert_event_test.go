@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type textOnlyTestEvent struct {
+	*EventMeta
+}
+
+func (e textOnlyTestEvent) String() string { return "text only" }
+
+func TestMissingEventInterfaces(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, e := range []Event{
+		NewAuditEvent("bailey", "pooped"),
+		NewMessageEvent(Info, "this is a test"),
+		NewErrorEvent(Error, fmt.Errorf("this is a test")),
+		NewHTTPResponseEvent(nil),
+	} {
+		assert.Empty(MissingEventInterfaces(e))
+	}
+	assert.Equal([]string{"logger.TextWritable", "json.Marshaler"}, MissingEventInterfaces(textOnlyTestEvent{EventMeta: NewEventMeta(Info)}))
+}
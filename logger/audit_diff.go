@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AuditDiffTag is the struct tag used to rename or ignore fields in an audit diff, and to map fields with `OptAuditStruct`.
+// Fields tagged with `audit:"-"` are ignored, e.g. for sensitive values like passwords.
+const AuditDiffTag = "audit"
+
+// AuditDiffSeparator separates the old and new values in an audit diff.
+const AuditDiffSeparator = " -> "
+
+// OptAuditDiff adds the fields that changed between an old and new value to the event extra values.
+// See `AuditDiff` for how the changed fields are computed.
+func OptAuditDiff(oldValue, newValue interface{}) AuditEventOption {
+	return func(ae *AuditEvent) {
+		diff := AuditDiff(oldValue, newValue)
+		if len(diff) == 0 {
+			return
+		}
+		if ae.Extra == nil {
+			ae.Extra = make(map[string]string)
+		}
+		for key, value := range diff {
+			ae.Extra[key] = value
+		}
+	}
+}
+
+// AuditDiff returns the fields that changed between an old and new value, as `field: "old -> new"`.
+// Nested fields are flattened to dotted names; fields can be renamed with the `audit` tag, or ignored with `audit:"-"`.
+func AuditDiff(oldValue, newValue interface{}) map[string]string {
+	oldFields := make(map[string]string)
+	flattenAuditDiff(oldFields, "", reflect.ValueOf(oldValue))
+	newFields := make(map[string]string)
+	flattenAuditDiff(newFields, "", reflect.ValueOf(newValue))
+
+	output := make(map[string]string)
+	for key, oldFieldValue := range oldFields {
+		if newFieldValue := newFields[key]; newFieldValue != oldFieldValue {
+			output[key] = oldFieldValue + AuditDiffSeparator + newFieldValue
+		}
+	}
+	for key, newFieldValue := range newFields {
+		if _, ok := oldFields[key]; !ok && newFieldValue != "" {
+			output[key] = AuditDiffSeparator + newFieldValue
+		}
+	}
+	return output
+}
+
+// flattenAuditDiff writes the string forms of the fields of a value, with nested structs and maps as dotted keys.
+// Pointers and maps that refer back to a value that contains them are skipped.
+func flattenAuditDiff(output map[string]string, prefix string, value reflect.Value) {
+	flattenAuditDiffValue(output, prefix, value, make(map[auditDiffVisit]bool))
+}
+
+// auditDiffVisit is a pointer or map being flattened, by address and type.
+type auditDiffVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func flattenAuditDiffValue(output map[string]string, prefix string, value reflect.Value, visiting map[auditDiffVisit]bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			if prefix != "" {
+				output[prefix] = ""
+			}
+			return
+		}
+		if value.Kind() == reflect.Ptr {
+			visit := auditDiffVisit{ptr: value.Pointer(), typ: value.Type()}
+			if visiting[visit] {
+				return
+			}
+			visiting[visit] = true
+			defer delete(visiting, visit)
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if _, ok := value.Interface().(fmt.Stringer); ok || !hasExportedFields(value.Type()) {
+			break
+		}
+		valueType := value.Type()
+		for index := 0; index < valueType.NumField(); index++ {
+			field := valueType.Field(index)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag := strings.Split(field.Tag.Get(AuditDiffTag), ",")[0]; tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			flattenAuditDiffValue(output, joinAuditDiffKey(prefix, name), value.Field(index), visiting)
+		}
+		return
+	case reflect.Map:
+		if value.IsNil() {
+			return
+		}
+		visit := auditDiffVisit{ptr: value.Pointer(), typ: value.Type()}
+		if visiting[visit] {
+			return
+		}
+		visiting[visit] = true
+		defer delete(visiting, visit)
+
+		iter := value.MapRange()
+		for iter.Next() {
+			flattenAuditDiffValue(output, joinAuditDiffKey(prefix, fmt.Sprint(iter.Key().Interface())), iter.Value(), visiting)
+		}
+		return
+	}
+	if prefix != "" {
+		output[prefix] = fmt.Sprint(value.Interface())
+	}
+}
+
+func joinAuditDiffKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for index := 0; index < t.NumField(); index++ {
+		if t.Field(index).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
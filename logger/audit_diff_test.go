@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type auditDiffAddress struct {
+	City  string
+	State string
+}
+
+type auditDiffUser struct {
+	Email     string `audit:"email"`
+	Password  string `audit:"-"`
+	Age       int
+	Address   auditDiffAddress
+	Manager   *auditDiffAddress
+	Labels    map[string]string
+	UpdatedAt time.Time
+	internal  string
+}
+
+func TestAuditDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	updated := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	oldUser := auditDiffUser{
+		Email:     "bailey@example.com",
+		Password:  "hunter2",
+		Age:       5,
+		Address:   auditDiffAddress{City: "Bend", State: "OR"},
+		Labels:    map[string]string{"team": "dogs", "role": "good"},
+		UpdatedAt: updated,
+		internal:  "foo",
+	}
+	newUser := oldUser
+	newUser.Email = "bailey@example.org"
+	newUser.Password = "correct horse"
+	newUser.Address.City = "Portland"
+	newUser.Manager = &auditDiffAddress{City: "Salem"}
+	newUser.Labels = map[string]string{"team": "dogs", "size": "small"}
+	newUser.internal = "bar"
+
+	diff := AuditDiff(oldUser, &newUser)
+	assert.Equal(map[string]string{
+		"email":        "bailey@example.com -> bailey@example.org",
+		"Address.City": "Bend -> Portland",
+		"Manager.City": " -> Salem",
+		"Labels.role":  "good -> ",
+		"Labels.size":  " -> small",
+	}, diff)
+
+	assert.Empty(AuditDiff(oldUser, oldUser))
+	assert.Equal(map[string]string{"foo": "1 -> 2"}, AuditDiff(map[string]int{"foo": 1, "bar": 3}, map[string]int{"foo": 2, "bar": 3}))
+}
+
+func TestOptAuditDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	ae := NewCRUDAuditEvent("bailey", VerbUpdate, "address", "home",
+		OptAuditExtra(map[string]string{"reason": "moved"}),
+		OptAuditDiff(auditDiffAddress{City: "Bend", State: "OR"}, auditDiffAddress{City: "Portland", State: "OR"}),
+	)
+	assert.Equal(map[string]string{"reason": "moved", "City": "Bend -> Portland"}, ae.Extra)
+
+	ae = NewAuditEvent("bailey", "update", OptAuditDiff(auditDiffAddress{}, auditDiffAddress{}))
+	assert.Nil(ae.Extra)
+}
+
+type auditDiffNode struct {
+	Name     string
+	Parent   *auditDiffNode
+	Children map[string]*auditDiffNode
+}
+
+func TestAuditDiffCycles(t *testing.T) {
+	assert := assert.New(t)
+
+	newTree := func(name string) *auditDiffNode {
+		root := &auditDiffNode{Name: "root", Children: make(map[string]*auditDiffNode)}
+		root.Parent = root
+		root.Children["child"] = &auditDiffNode{Name: name, Parent: root}
+		return root
+	}
+
+	assert.Equal(map[string]string{
+		"Children.child.Name": "bailey -> buster",
+	}, AuditDiff(newTree("bailey"), newTree("buster")))
+
+	ae := NewAuditEventFromStruct(struct {
+		Tree *auditDiffNode `audit:"tree"`
+	}{Tree: newTree("bailey")})
+	assert.Equal("bailey", ae.Extra["tree.Children.child.Name"])
+	assert.Equal("root", ae.Extra["tree.Name"])
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/blend/go-sdk/ansi"
@@ -30,6 +31,26 @@ func NewAuditEvent(principal, verb string, options ...AuditEventOption) *AuditEv
 	return ae
 }
 
+// Verb is a canonical audit event verb.
+type Verb string
+
+// Verbs for create, read, update and delete operations.
+const (
+	VerbCreate Verb = "create"
+	VerbRead   Verb = "read"
+	VerbUpdate Verb = "update"
+	VerbDelete Verb = "delete"
+)
+
+// NewCRUDAuditEvent returns a new audit event for a create, read, update or delete operation.
+// It is a shortcut for `NewAuditEvent` that sets the noun and subject, and requires a canonical verb.
+func NewCRUDAuditEvent(principal string, verb Verb, noun, subject string, options ...AuditEventOption) *AuditEvent {
+	return NewAuditEvent(principal, string(verb), append([]AuditEventOption{
+		OptAuditNoun(noun),
+		OptAuditSubject(subject),
+	}, options...)...)
+}
+
 // NewAuditEventListener returns a new audit event listener.
 func NewAuditEventListener(listener func(context.Context, *AuditEvent)) Listener {
 	return func(ctx context.Context, e Event) {
@@ -154,9 +175,15 @@ func (e AuditEvent) WriteText(formatter TextFormatter, wr io.Writer) {
 		io.WriteString(wr, Space)
 	}
 	if len(e.Extra) > 0 {
+		var keys []string
+		for key := range e.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
 		var values []string
-		for key, value := range e.Extra {
-			values = append(values, fmt.Sprintf("%s%s", formatter.Colorize(key+":", ansi.ColorLightBlack), value))
+		for _, key := range keys {
+			values = append(values, fmt.Sprintf("%s%s", formatter.Colorize(key+":", ansi.ColorLightBlack), e.Extra[key]))
 		}
 		io.WriteString(wr, strings.Join(values, " "))
 	}
@@ -86,6 +86,25 @@ func TestAuditEventWriteText(t *testing.T) {
 	assert.Equal("Context:event context Principal:not bailey Verb:not pooped Noun:audit noun Subject:audit subject Property:audit property Remote Addr:remote address UA:user agent foo:bar", buf.String())
 }
 
+func TestAuditEventWriteTextExtraSorted(t *testing.T) {
+	assert := assert.New(t)
+
+	ae := NewAuditEvent(
+		"bailey",
+		"pooped",
+		OptAuditExtra(map[string]string{"zed": "1", "alpha": "2", "mike": "3", "bravo": "4", "yankee": "5"}),
+	)
+
+	noColor := TextOutputFormatter{
+		NoColor: true,
+	}
+	for x := 0; x < 10; x++ {
+		buf := new(bytes.Buffer)
+		ae.WriteText(noColor, buf)
+		assert.Equal("Principal:bailey Verb:pooped alpha:2 bravo:4 mike:3 yankee:5 zed:1", buf.String())
+	}
+}
+
 func TestAuditEventListener(t *testing.T) {
 	assert := assert.New(t)
 
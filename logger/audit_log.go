@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// these are compile time assertions
+var (
+	_ io.Closer = (*AuditLog)(nil)
+)
+
+// NewAuditLog returns a new audit log that appends events to the file at the given path.
+func NewAuditLog(path string, options ...AuditLogOption) *AuditLog {
+	al := &AuditLog{
+		Path:      path,
+		FileMode:  DefaultAuditLogFileMode,
+		Formatter: NewJSONOutputFormatter(),
+	}
+	for _, option := range options {
+		option(al)
+	}
+	return al
+}
+
+// AuditLogOption is an option for audit logs.
+type AuditLogOption func(*AuditLog)
+
+// OptAuditLogFileMode sets the file mode for the created file.
+func OptAuditLogFileMode(mode os.FileMode) AuditLogOption {
+	return func(al *AuditLog) { al.FileMode = mode }
+}
+
+// OptAuditLogFormatter sets the formatter used to write events, which defaults to json.
+func OptAuditLogFormatter(formatter WriteFormatter) AuditLogOption {
+	return func(al *AuditLog) { al.Formatter = formatter }
+}
+
+// AuditLog is a durable sink for audit events, separate from the operational log output.
+// `Write` blocks until the event is synced to disk, and never drops events.
+type AuditLog struct {
+	sync.Mutex
+
+	Path      string
+	FileMode  os.FileMode
+	Formatter WriteFormatter
+
+	file   *os.File
+	buffer *bufio.Writer
+}
+
+// Tap writes the audit events triggered on a logger to the audit log synchronously, and returns a function that removes the tap.
+// Events that fail to write are written to the logger's output as errors; use `Write` directly to handle errors.
+func (al *AuditLog) Tap(log *Logger) (untap func()) {
+	return log.TapContext(Audit, func(ctx context.Context, e Event) {
+		typed, ok := e.(*AuditEvent)
+		if !ok {
+			return
+		}
+		if err := al.Write(ctx, typed); err != nil {
+			log.Write(ctx, NewErrorEvent(Error, ex.New(err, ex.OptMessagef("audit log %q write failed", al.Path)), OptErrorEventMetaOptions(OptEventMetaTimestamp(log.now()))))
+		}
+	})
+}
+
+// Write writes an audit event to the file and syncs it to disk before returning.
+func (al *AuditLog) Write(ctx context.Context, ae *AuditEvent) error {
+	al.Lock()
+	defer al.Unlock()
+
+	if err := al.ensureOpen(); err != nil {
+		return err
+	}
+	if err := al.Formatter.WriteFormat(ctx, al.buffer, ae); err != nil {
+		return ex.New(err)
+	}
+	return al.flush()
+}
+
+// Flush writes any buffered output to the file and syncs it to disk.
+func (al *AuditLog) Flush() error {
+	al.Lock()
+	defer al.Unlock()
+	return al.flush()
+}
+
+// Close flushes any buffered output and closes the file.
+func (al *AuditLog) Close() error {
+	al.Lock()
+	defer al.Unlock()
+
+	if al.file == nil {
+		return nil
+	}
+	flushErr := al.flush()
+	closeErr := al.file.Close()
+	al.file = nil
+	al.buffer = nil
+	if flushErr != nil {
+		return flushErr
+	}
+	if closeErr != nil {
+		return ex.New(closeErr)
+	}
+	return nil
+}
+
+//
+// internal helpers
+//
+
+func (al *AuditLog) ensureOpen() error {
+	if al.file != nil {
+		return nil
+	}
+	file, err := os.OpenFile(al.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, al.FileMode)
+	if err != nil {
+		return ex.New(err)
+	}
+	al.file = file
+	al.buffer = bufio.NewWriter(file)
+	return nil
+}
+
+func (al *AuditLog) flush() error {
+	if al.file == nil {
+		return nil
+	}
+	if err := al.buffer.Flush(); err != nil {
+		return ex.New(err)
+	}
+	if err := al.file.Sync(); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
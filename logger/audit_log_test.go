@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "audit_log")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "audit.log")
+	al := NewAuditLog(path)
+	assert.Nil(al.Write(context.Background(), NewAuditEvent("bailey", "read", OptAuditNoun("document"))))
+
+	// the event should be on disk before close.
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &decoded))
+	assert.Equal("bailey", decoded["principal"])
+	assert.Equal("document", decoded["noun"])
+
+	assert.Nil(al.Close())
+	assert.Nil(al.Close())
+
+	info, err := os.Stat(path)
+	assert.Nil(err)
+	assert.Equal(DefaultAuditLogFileMode, info.Mode().Perm())
+}
+
+func TestAuditLogTap(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "audit_log")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "audit.log")
+	al := NewAuditLog(path)
+	defer al.Close()
+
+	log := MustNew(OptAll(), OptOutput(nil))
+	defer log.Close()
+	untap := al.Tap(log)
+
+	const workers, eventsPerWorker = 8, 64
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for index := 0; index < eventsPerWorker; index++ {
+				log.Trigger(context.Background(), NewAuditEvent(fmt.Sprintf("%d-%d", worker, index), "write"))
+			}
+		}(worker)
+	}
+	wg.Wait()
+	untap()
+	log.Trigger(context.Background(), NewAuditEvent("untapped", "write"))
+
+	// every event should be on disk once the triggers return, without closing the logger or the audit log.
+	file, err := os.Open(path)
+	assert.Nil(err)
+	defer file.Close()
+
+	principals := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var decoded map[string]interface{}
+		assert.Nil(json.Unmarshal(scanner.Bytes(), &decoded))
+		principals[decoded["principal"].(string)] = true
+	}
+	assert.Nil(scanner.Err())
+	assert.Len(principals, workers*eventsPerWorker, "every audit event should be written")
+	assert.False(principals["untapped"])
+}
+
+func TestAuditLogTapWriteError(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "audit_log")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	al := NewAuditLog(filepath.Join(tempDir, "missing", "audit.log"))
+	output := new(bytes.Buffer)
+	log := MustNew(OptAll(), OptOutput(output), OptText(OptTextNoColor(), OptTextHideTimestamp()))
+	defer log.Close()
+	defer al.Tap(log)()
+
+	log.Trigger(context.Background(), NewAuditEvent("bailey", "write"))
+	assert.Contains(output.String(), "[error]")
+	assert.Contains(output.String(), "write failed")
+}
+
+func TestLoggerListenBlockingNeverDrops(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(
+		OptAll(),
+		OptOutput(nil),
+		OptListenerSaturationPolicy(SaturationPolicyDrop),
+		OptListenerQueueDepth(1),
+	)
+	var mu sync.Mutex
+	principals := make(map[string]bool)
+	log.ListenBlocking(Audit, "audit", NewAuditEventListener(func(_ context.Context, ae *AuditEvent) {
+		mu.Lock()
+		principals[ae.Principal] = true
+		mu.Unlock()
+	}))
+
+	const workers, eventsPerWorker = 8, 64
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for index := 0; index < eventsPerWorker; index++ {
+				log.Trigger(context.Background(), NewAuditEvent(fmt.Sprintf("%d-%d", worker, index), "write"))
+			}
+		}(worker)
+	}
+	wg.Wait()
+	assert.Nil(log.Close())
+	assert.Zero(log.Dropped())
+	assert.Len(principals, workers*eventsPerWorker, "every event should be delivered")
+}
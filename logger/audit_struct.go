@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NewAuditEventFromStruct returns a new audit event with fields read from the `audit` tags of a struct.
+// See `OptAuditStruct` for how the tags are mapped; options are applied after the struct, e.g. to set the principal and verb.
+func NewAuditEventFromStruct(value interface{}, options ...AuditEventOption) *AuditEvent {
+	return NewAuditEvent("", "", append([]AuditEventOption{OptAuditStruct(value)}, options...)...)
+}
+
+// OptAuditStruct sets the fields of an audit event from the `audit` tags of a struct (or a pointer to one).
+// Tags that don't name an audit event field, e.g. `audit:"title"`, are added to the extra values.
+func OptAuditStruct(value interface{}) AuditEventOption {
+	return func(ae *AuditEvent) {
+		structValue := reflect.ValueOf(value)
+		for structValue.Kind() == reflect.Ptr || structValue.Kind() == reflect.Interface {
+			if structValue.IsNil() {
+				return
+			}
+			structValue = structValue.Elem()
+		}
+		if structValue.Kind() != reflect.Struct {
+			return
+		}
+
+		structType := structValue.Type()
+		for index := 0; index < structType.NumField(); index++ {
+			field := structType.Field(index)
+			if field.PkgPath != "" {
+				continue
+			}
+			tag := strings.Split(field.Tag.Get(AuditDiffTag), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			if target := ae.auditStructField(tag); target != nil {
+				*target = auditStructValue(structValue.Field(index))
+				continue
+			}
+			if ae.Extra == nil {
+				ae.Extra = make(map[string]string)
+			}
+			flattenAuditDiff(ae.Extra, tag, structValue.Field(index))
+		}
+	}
+}
+
+// auditStructField returns the event field for an `audit` tag name, or nil if it's not an event field.
+func (e *AuditEvent) auditStructField(tag string) *string {
+	switch tag {
+	case "context":
+		return &e.Context
+	case "principal":
+		return &e.Principal
+	case "verb":
+		return &e.Verb
+	case "noun":
+		return &e.Noun
+	case "subject":
+		return &e.Subject
+	case "property":
+		return &e.Property
+	case "remote_address":
+		return &e.RemoteAddress
+	case "user_agent":
+		return &e.UserAgent
+	}
+	return nil
+}
+
+func auditStructValue(value reflect.Value) string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type auditStructDocument struct {
+	ID       int               `audit:"subject"`
+	Kind     string            `audit:"noun"`
+	Owner    *string           `audit:"principal"`
+	Title    string            `audit:"title"`
+	Address  auditDiffAddress  `audit:"address"`
+	Labels   map[string]string `audit:"labels"`
+	Contents string            `audit:"-"`
+	Size     int
+	internal string `audit:"internal"`
+}
+
+func TestNewAuditEventFromStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	owner := "bailey"
+	document := auditStructDocument{
+		ID:       1234,
+		Kind:     "document",
+		Owner:    &owner,
+		Title:    "Quarterly Report",
+		Address:  auditDiffAddress{City: "Bend", State: "OR"},
+		Labels:   map[string]string{"team": "dogs"},
+		Contents: "secret",
+		Size:     5,
+		internal: "foo",
+	}
+
+	ae := NewAuditEventFromStruct(&document, OptAuditVerb(string(VerbUpdate)))
+	assert.Equal(Audit, ae.GetFlag())
+	assert.Equal("1234", ae.Subject)
+	assert.Equal("document", ae.Noun)
+	assert.Equal("bailey", ae.Principal)
+	assert.Equal("update", ae.Verb)
+	assert.Equal(map[string]string{
+		"title":         "Quarterly Report",
+		"address.City":  "Bend",
+		"address.State": "OR",
+		"labels.team":   "dogs",
+	}, ae.Extra)
+
+	// options are applied after the struct.
+	ae = NewAuditEventFromStruct(document, OptAuditPrincipal("admin"))
+	assert.Equal("admin", ae.Principal)
+}
+
+func TestNewAuditEventFromStructEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	ae := NewAuditEventFromStruct(auditStructDocument{})
+	assert.Equal("0", ae.Subject)
+	assert.Empty(ae.Principal, "nil pointers should be empty")
+
+	ae = NewAuditEventFromStruct((*auditStructDocument)(nil))
+	assert.Empty(ae.Subject)
+	assert.Empty(ae.Extra)
+
+	ae = NewAuditEventFromStruct("not a struct")
+	assert.Empty(ae.Extra)
+}
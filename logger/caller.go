@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// callerDepth is the number of stack frames between the logger's internal
+// trigger and the function that called `Trigger`, `SyncTrigger` or one of
+// the builtin flag handlers (`Infof`, `Error` etc.).
+const callerDepth = 3
+
+// GetCaller returns the caller `skip` frames above the function calling GetCaller.
+func GetCaller(skip int) (caller Caller, ok bool) {
+	_, caller.File, caller.Line, ok = runtime.Caller(skip + 1)
+	return
+}
+
+// Caller is a source location an event was emitted from.
+type Caller struct {
+	File string
+	Line int
+}
+
+// IsZero returns if the caller is unset.
+func (c Caller) IsZero() bool {
+	return c.File == "" && c.Line == 0
+}
+
+// String returns the caller as `file:line`.
+func (c Caller) String() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// Short returns the caller as `file:line` with only the base name of the file.
+func (c Caller) Short() string {
+	return fmt.Sprintf("%s:%d", filepath.Base(c.File), c.Line)
+}
+
+// CallerSetter is a type that a caller can be set on; it is satisfied by `*EventMeta`.
+type CallerSetter interface {
+	SetCaller(Caller)
+}
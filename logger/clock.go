@@ -0,0 +1,33 @@
+package logger
+
+import "time"
+
+// these are compile time assertions
+var (
+	_ Clock = (*SystemClock)(nil)
+	_ Clock = (ClockFunc)(nil)
+)
+
+// Clock is a type that returns the current time.
+// It is used to stamp events and can be swapped out in tests to
+// make timestamps (and as a result output) deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default clock, and returns `time.Now()`.
+type SystemClock struct{}
+
+// Now returns the current time.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// ClockFunc is a function that implements clock.
+type ClockFunc func() time.Time
+
+// Now returns the current time.
+func (cf ClockFunc) Now() time.Time { return cf() }
+
+// StaticClock returns a clock that always returns a given time.
+func StaticClock(ts time.Time) Clock {
+	return ClockFunc(func() time.Time { return ts })
+}
@@ -42,6 +42,10 @@ func (c Config) Formatter() WriteFormatter {
 		return NewJSONOutputFormatter(OptJSONConfig(c.JSON))
 	case FormatText:
 		return NewTextOutputFormatter(OptTextConfig(c.Text))
+	case FormatLogfmt:
+		return NewLogfmtOutputFormatter()
+	case FormatECS:
+		return NewECSOutputFormatter()
 	default:
 		return NewTextOutputFormatter(OptTextConfig(c.Text))
 	}
@@ -68,6 +72,14 @@ type JSONConfig struct {
 	Pretty       bool   `json:"pretty,omitempty" yaml:"pretty,omitempty" env:"LOG_JSON_PRETTY"`
 	PrettyPrefix string `json:"prettyPrefix,omitempty" yaml:"prettyPrefix,omitempty" env:"LOG_JSON_PRETTY_PREFIX"`
 	PrettyIndent string `json:"prettyIndent,omitempty" yaml:"prettyIndent,omitempty" env:"LOG_JSON_PRETTY_INDENT"`
+	// FieldNames maps default field names to the names they should be written as.
+	FieldNames map[string]string `json:"fieldNames,omitempty" yaml:"fieldNames,omitempty"`
+	// Flatten flattens nested objects and arrays into dotted keys.
+	Flatten bool `json:"flatten,omitempty" yaml:"flatten,omitempty" env:"LOG_JSON_FLATTEN"`
+	// FloatPrecision rounds float fields to a number of decimal places if it is positive.
+	FloatPrecision int `json:"floatPrecision,omitempty" yaml:"floatPrecision,omitempty" env:"LOG_JSON_FLOAT_PRECISION"`
+	// TimeFormat is a go time layout, `unixMillis` or `unixNanos` that timestamps are written with.
+	TimeFormat string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty" env:"LOG_JSON_TIME_FORMAT"`
 }
 
 // PrettyPrefixOrDefault returns the pretty prefix or a default.
@@ -1,6 +1,9 @@
 package logger
 
-import "time"
+import (
+	"os"
+	"time"
+)
 
 // Flags
 const (
@@ -16,12 +19,17 @@ const (
 	Audit        = "audit"
 	Query        = "db.query"
 	RPC          = "rpc"
+	Timing       = "timing"
+	Metric       = "metric"
+	Deprecation  = "deprecation"
 )
 
 // Output Formats
 const (
-	FormatJSON = "json"
-	FormatText = "text"
+	FormatJSON   = "json"
+	FormatText   = "text"
+	FormatLogfmt = "logfmt"
+	FormatECS    = "ecs"
 )
 
 // Default flags
@@ -54,10 +62,57 @@ const (
 	DefaultTextWriterShowTimestamp = true
 )
 
+// SaturationPolicy determines what happens to events triggered for a listener whose queue is full.
+type SaturationPolicy string
+
+// Saturation policies
+const (
+	// SaturationPolicyBlock blocks the triggering call until there is space in the listener's queue.
+	// It is the default.
+	SaturationPolicyBlock SaturationPolicy = ""
+	// SaturationPolicyDrop drops the event for the listener, and counts it as dropped.
+	SaturationPolicyDrop SaturationPolicy = "drop"
+)
+
 const (
 	// DefaultWorkerQueueDepth is the default depth per listener to queue work.
 	// It's currently set to 256k entries.
 	DefaultWorkerQueueDepth = 1 << 10
+	// DefaultWorkerMaxAbandoned is the default number of timed out listener invocations that can still be running
+	// before a worker skips events.
+	DefaultWorkerMaxAbandoned = 16
+)
+
+const (
+	// DefaultRotatingFileWriterFileMode is the default file mode for files created by rotating file writers.
+	DefaultRotatingFileWriterFileMode os.FileMode = 0644
+	// DefaultAuditLogFileMode is the default file mode for files created by audit logs.
+	DefaultAuditLogFileMode os.FileMode = 0600
+)
+
+const (
+	// DefaultHTTPWriterBatchSize is the default number of lines sent per request by http writers.
+	DefaultHTTPWriterBatchSize = 100
+	// DefaultHTTPWriterFlushInterval is the default interval partial batches are sent on by http writers.
+	DefaultHTTPWriterFlushInterval = time.Second
+	// DefaultHTTPWriterQueueDepth is the default number of lines http writers buffer before dropping lines.
+	DefaultHTTPWriterQueueDepth = 1 << 12
+	// DefaultHTTPWriterMaxRetries is the default number of times http writers retry a failed request.
+	DefaultHTTPWriterMaxRetries = 3
+	// DefaultHTTPWriterRetryDelay is the default delay before the first retry, which doubles for each retry.
+	DefaultHTTPWriterRetryDelay = 100 * time.Millisecond
+)
+
+const (
+	// DefaultTimingAggregatorReservoirSize is the default number of samples retained per operation by a timing aggregator.
+	DefaultTimingAggregatorReservoirSize = 1024
+	// DefaultRateAggregatorWindow is the default time over which a rate aggregator computes rates.
+	DefaultRateAggregatorWindow = time.Minute
+)
+
+var (
+	// DefaultTimingAggregatorQuantiles are the default quantiles computed by a timing aggregator.
+	DefaultTimingAggregatorQuantiles = []float64{0.5, 0.9, 0.95, 0.99}
 )
 
 // String constants
@@ -72,6 +127,8 @@ const (
 	FieldTimestamp = "_timestamp"
 	FieldMessage   = "message"
 	FieldFields    = "fields"
+	FieldCaller    = "caller"
+	FieldSchema    = "_schema"
 )
 
 // JSON Formatter defaults
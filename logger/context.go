@@ -75,12 +75,19 @@ func (sc Context) WithFields(fields Fields, options ...ContextOption) Context {
 
 // Trigger triggers an event in the subcontext.
 func (sc Context) Trigger(ctx context.Context, event Event) {
-	sc.Logger.trigger(WithSubContextMeta(ctx, sc.Path, sc.Fields), event, false)
+	sc.trigger(ctx, event, false)
 }
 
 // SyncTrigger triggers an event in the subcontext synchronously..
 func (sc Context) SyncTrigger(ctx context.Context, event Event) {
-	sc.Logger.trigger(WithSubContextMeta(ctx, sc.Path, sc.Fields), event, true)
+	sc.trigger(ctx, event, true)
+}
+
+// trigger triggers an event on the logger with the subcontext metadata.
+// The builtin flag handlers call this directly (rather than `Trigger`) so that
+// the caller of any exported trigger method is the same number of frames away.
+func (sc Context) trigger(ctx context.Context, event Event, sync bool) {
+	sc.Logger.trigger(WithSubContextMeta(ctx, sc.Path, sc.Fields), event, sync)
 }
 
 // --------------------------------------------------------------------------------
@@ -89,77 +96,87 @@ func (sc Context) SyncTrigger(ctx context.Context, event Event) {
 
 // Info logs an informational message to the output stream.
 func (sc Context) Info(args ...interface{}) {
-	sc.Trigger(context.Background(), NewMessageEvent(Info, fmt.Sprint(args...)))
+	sc.trigger(context.Background(), sc.messageEvent(Info, fmt.Sprint(args...)), false)
 }
 
 // Infof logs an informational message to the output stream.
 func (sc Context) Infof(format string, args ...interface{}) {
-	sc.Trigger(context.Background(), NewMessageEvent(Info, fmt.Sprintf(format, args...)))
+	sc.trigger(context.Background(), sc.messageEvent(Info, fmt.Sprintf(format, args...)), false)
 }
 
 // Debug logs a debug message to the output stream.
 func (sc Context) Debug(args ...interface{}) {
-	sc.Trigger(context.Background(), NewMessageEvent(Debug, fmt.Sprint(args...)))
+	sc.trigger(context.Background(), sc.messageEvent(Debug, fmt.Sprint(args...)), false)
 }
 
 // Debugf logs a debug message to the output stream.
 func (sc Context) Debugf(format string, args ...interface{}) {
-	sc.Trigger(context.Background(), NewMessageEvent(Debug, fmt.Sprintf(format, args...)))
+	sc.trigger(context.Background(), sc.messageEvent(Debug, fmt.Sprintf(format, args...)), false)
 }
 
 // Warningf logs a warning message to the output stream.
 func (sc Context) Warningf(format string, args ...interface{}) {
-	sc.Trigger(context.Background(), NewErrorEvent(Warning, fmt.Errorf(format, args...)))
+	sc.trigger(context.Background(), sc.errorEvent(Warning, fmt.Errorf(format, args...)), false)
 }
 
 // Errorf writes an event to the log and triggers event listeners.
 func (sc Context) Errorf(format string, args ...interface{}) {
-	sc.Trigger(context.Background(), NewErrorEvent(Error, fmt.Errorf(format, args...)))
+	sc.trigger(context.Background(), sc.errorEvent(Error, fmt.Errorf(format, args...)), false)
 }
 
 // Fatalf writes an event to the log and triggers event listeners.
 func (sc Context) Fatalf(format string, args ...interface{}) {
-	sc.Trigger(context.Background(), NewErrorEvent(Fatal, fmt.Errorf(format, args...)))
+	sc.trigger(context.Background(), sc.errorEvent(Fatal, fmt.Errorf(format, args...)), false)
 }
 
 // Warning logs a warning error to std err.
 func (sc Context) Warning(err error) error {
-	sc.Trigger(context.Background(), NewErrorEvent(Warning, err))
+	sc.trigger(context.Background(), sc.errorEvent(Warning, err), false)
 	return err
 }
 
 // WarningWithReq logs a warning error to std err with a request.
 func (sc Context) WarningWithReq(err error, req *http.Request) error {
-	ee := NewErrorEvent(Warning, err)
+	ee := sc.errorEvent(Warning, err)
 	ee.State = req
-	sc.Trigger(context.Background(), ee)
+	sc.trigger(context.Background(), ee, false)
 	return err
 }
 
 // Error logs an error to std err.
 func (sc Context) Error(err error) error {
-	sc.Trigger(context.Background(), NewErrorEvent(Error, err))
+	sc.trigger(context.Background(), sc.errorEvent(Error, err), false)
 	return err
 }
 
 // ErrorWithReq logs an error to std err with a request.
 func (sc Context) ErrorWithReq(err error, req *http.Request) error {
-	ee := NewErrorEvent(Error, err)
+	ee := sc.errorEvent(Error, err)
 	ee.State = req
-	sc.Trigger(context.Background(), ee)
+	sc.trigger(context.Background(), ee, false)
 	return err
 }
 
 // Fatal logs an error as fatal.
 func (sc Context) Fatal(err error) error {
-	sc.Trigger(context.Background(), NewErrorEvent(Fatal, err))
+	sc.trigger(context.Background(), sc.errorEvent(Fatal, err), false)
 	return err
 }
 
 // FatalWithReq logs an error as fatal with a request as state.
 func (sc Context) FatalWithReq(err error, req *http.Request) error {
-	ee := NewErrorEvent(Fatal, err)
+	ee := sc.errorEvent(Fatal, err)
 	ee.State = req
-	sc.Trigger(context.Background(), ee)
+	sc.trigger(context.Background(), ee, false)
 	return err
 }
+
+// messageEvent returns a new message event stamped with the logger clock.
+func (sc Context) messageEvent(flag, message string) *MessageEvent {
+	return NewMessageEvent(flag, message, OptMessageMeta(OptEventMetaTimestamp(sc.Logger.now())))
+}
+
+// errorEvent returns a new error event stamped with the logger clock.
+func (sc Context) errorEvent(flag string, err error) *ErrorEvent {
+	return NewErrorEvent(flag, err, OptErrorEventMetaOptions(OptEventMetaTimestamp(sc.Logger.now())))
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/ansi"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// these are compile time assertions
+var (
+	_ Event          = (*DeprecationEvent)(nil)
+	_ TextWritable   = (*DeprecationEvent)(nil)
+	_ json.Marshaler = (*DeprecationEvent)(nil)
+)
+
+// NewDeprecationEvent returns a new deprecation event for a request to a deprecated endpoint.
+func NewDeprecationEvent(req *http.Request, message string, options ...DeprecationEventOption) *DeprecationEvent {
+	de := DeprecationEvent{
+		EventMeta: NewEventMeta(Deprecation),
+		Request:   req,
+		Message:   message,
+	}
+	for _, opt := range options {
+		opt(&de)
+	}
+	return &de
+}
+
+// NewDeprecationEventListener returns a new deprecation event listener.
+func NewDeprecationEventListener(listener func(context.Context, *DeprecationEvent)) Listener {
+	return func(ctx context.Context, e Event) {
+		if typed, isTyped := e.(*DeprecationEvent); isTyped {
+			listener(ctx, typed)
+		}
+	}
+}
+
+// DeprecationEventOption is a mutator for deprecation events.
+type DeprecationEventOption func(*DeprecationEvent)
+
+// OptDeprecationMeta sets meta options.
+func OptDeprecationMeta(options ...EventMetaOption) DeprecationEventOption {
+	return func(e *DeprecationEvent) {
+		for _, opt := range options {
+			opt(e.EventMeta)
+		}
+	}
+}
+
+// OptDeprecationRequest sets a field on the event.
+func OptDeprecationRequest(value *http.Request) DeprecationEventOption {
+	return func(e *DeprecationEvent) { e.Request = value }
+}
+
+// OptDeprecationRoute sets a field on the event.
+func OptDeprecationRoute(value string) DeprecationEventOption {
+	return func(e *DeprecationEvent) { e.Route = value }
+}
+
+// OptDeprecationMessage sets a field on the event.
+func OptDeprecationMessage(value string) DeprecationEventOption {
+	return func(e *DeprecationEvent) { e.Message = value }
+}
+
+// OptDeprecationClientIP sets a field on the event.
+func OptDeprecationClientIP(value string) DeprecationEventOption {
+	return func(e *DeprecationEvent) { e.ClientIP = value }
+}
+
+// OptDeprecationSunset sets a field on the event.
+func OptDeprecationSunset(value time.Time) DeprecationEventOption {
+	return func(e *DeprecationEvent) { e.Sunset = value }
+}
+
+// DeprecationEvent is an event logged when a client calls a deprecated endpoint.
+// It has its own flag so calls to deprecated endpoints can be counted by route and client
+// ahead of their sunset.
+type DeprecationEvent struct {
+	*EventMeta
+	Request *http.Request
+	Route   string
+	Message string
+	// Sunset is when the endpoint will stop responding, if it's known.
+	Sunset time.Time
+	// ClientIP is the client ip written in the event.
+	// If unset, it is read from the request with `webutil.GetRemoteAddr`.
+	ClientIP string
+}
+
+// WriteText implements TextWritable.
+func (e *DeprecationEvent) WriteText(tf TextFormatter, wr io.Writer) {
+	if e.Request != nil {
+		writeHTTPRequest(tf, wr, e.clientIP(), e.Request)
+		io.WriteString(wr, Space)
+	}
+	io.WriteString(wr, e.Message)
+	if !e.Sunset.IsZero() {
+		io.WriteString(wr, Space)
+		io.WriteString(wr, tf.Colorize("sunset", ansi.ColorYellow))
+		io.WriteString(wr, Space)
+		io.WriteString(wr, e.Sunset.UTC().Format(time.RFC3339))
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *DeprecationEvent) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{
+		FieldMessage:           e.Message,
+		HTTPResponseFieldRoute: e.Route,
+	}
+	if !e.Sunset.IsZero() {
+		fields["sunset"] = e.Sunset.UTC()
+	}
+	if e.Request != nil {
+		fields[HTTPResponseFieldVerb] = e.Request.Method
+		if e.Request.URL != nil {
+			fields[HTTPResponseFieldPath] = e.Request.URL.Path
+		}
+		fields[HTTPResponseFieldIP] = e.clientIP()
+		fields[HTTPResponseFieldUserAgent] = webutil.GetUserAgent(e.Request)
+	}
+	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), fields))
+}
+
+func (e *DeprecationEvent) clientIP() string {
+	if e.ClientIP != "" {
+		return e.ClientIP
+	}
+	return webutil.GetRemoteAddr(e.Request)
+}
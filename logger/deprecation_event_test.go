@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestDeprecationEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Method: "GET", RemoteAddr: "127.0.0.1:8080", URL: &url.URL{Path: "/v1/users"}, Header: http.Header{"User-Agent": []string{"go-sdk"}}}
+	sunset := time.Date(2021, 01, 02, 03, 04, 05, 0, time.UTC)
+	de := NewDeprecationEvent(req, "use /v2/users",
+		OptDeprecationRoute("/v1/users"),
+		OptDeprecationSunset(sunset),
+	)
+	assert.Equal(Deprecation, de.GetFlag())
+	assert.Equal("use /v2/users", de.Message)
+	assert.Equal("/v1/users", de.Route)
+
+	buf := new(bytes.Buffer)
+	noColor := TextOutputFormatter{
+		NoColor: true,
+	}
+	de.WriteText(noColor, buf)
+	assert.Equal("127.0.0.1 GET /v1/users use /v2/users sunset 2021-01-02T03:04:05Z", buf.String())
+
+	contents, err := json.Marshal(de)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"route":"/v1/users"`)
+	assert.Contains(string(contents), `"ip":"127.0.0.1"`)
+	assert.Contains(string(contents), `"userAgent":"go-sdk"`)
+	assert.Contains(string(contents), `"sunset":"2021-01-02T03:04:05Z"`)
+
+	OptDeprecationClientIP("10.1.2.3")(de)
+	buf.Reset()
+	de.WriteText(noColor, buf)
+	assert.Equal("10.1.2.3 GET /v1/users use /v2/users sunset 2021-01-02T03:04:05Z", buf.String())
+	contents, err = json.Marshal(de)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"ip":"10.1.2.3"`)
+}
+
+func TestDeprecationEventListener(t *testing.T) {
+	assert := assert.New(t)
+
+	var didCall bool
+	listener := NewDeprecationEventListener(func(_ context.Context, _ *DeprecationEvent) {
+		didCall = true
+	})
+	listener(context.Background(), NewMessageEvent(Info, "not deprecation"))
+	assert.False(didCall)
+	listener(context.Background(), NewDeprecationEvent(nil, "deprecated"))
+	assert.True(didCall)
+}
@@ -0,0 +1,27 @@
+package logger
+
+import "io"
+
+// these are compile time assertions
+var (
+	_ io.Writer = (*NopWriter)(nil)
+)
+
+// NopWriter is a writer that discards everything written to it.
+// Events are still formatted, so it measures the cost of logging without the cost of i/o.
+type NopWriter struct{}
+
+// Write implements io.Writer.
+func (NopWriter) Write(contents []byte) (int, error) {
+	return len(contents), nil
+}
+
+// DiscardLogger returns a logger with all flags enabled that drops events without formatting them.
+// Listeners still fire, but nothing is written.
+func DiscardLogger(options ...Option) *Logger {
+	return MustNew(append([]Option{
+		OptAll(),
+		OptOutput(nil),
+		OptFormatter(nil),
+	}, options...)...)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestNopWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	written, err := NopWriter{}.Write([]byte("hello"))
+	assert.Nil(err)
+	assert.Equal(5, written)
+}
+
+func TestDiscardLogger(t *testing.T) {
+	assert := assert.New(t)
+
+	log := DiscardLogger()
+	defer log.Close()
+	assert.True(log.IsEnabled(Info))
+	assert.Nil(log.Output)
+	assert.Nil(log.Formatter)
+
+	messages := make(chan string, 1)
+	log.Listen(Info, "test", NewMessageEventListener(func(_ context.Context, me *MessageEvent) {
+		messages <- me.Message
+	}))
+	log.Info("hello")
+	assert.Equal("hello", <-messages, "listeners should still fire")
+}
+
+func BenchmarkLoggerNopWriter(b *testing.B) {
+	log := MustNew(OptAll(), OptOutput(NopWriter{}), OptJSON())
+	defer log.Close()
+	for i := 0; i < b.N; i++ {
+		log.Infof("benchmark %d", i)
+	}
+}
+
+func BenchmarkDiscardLogger(b *testing.B) {
+	log := DiscardLogger()
+	defer log.Close()
+	for i := 0; i < b.N; i++ {
+		log.Infof("benchmark %d", i)
+	}
+}
@@ -0,0 +1,247 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/bufferutil"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ WriteFormatter = (*ECSOutputFormatter)(nil)
+)
+
+// ECSVersion is the version of the Elastic Common Schema written by the ecs output formatter.
+const ECSVersion = "1.6.0"
+
+// NewECSOutputFormatter returns a new ecs event formatter.
+func NewECSOutputFormatter(options ...ECSOutputFormatterOption) *ECSOutputFormatter {
+	ef := &ECSOutputFormatter{
+		BufferPool: bufferutil.NewPool(DefaultBufferPoolSize),
+	}
+	for _, option := range options {
+		option(ef)
+	}
+	return ef
+}
+
+// ECSOutputFormatterOption is an option for ecs formatters.
+type ECSOutputFormatterOption func(*ECSOutputFormatter)
+
+// ECSOutputFormatter is an output formatter that writes events as Elastic Common Schema (ECS) json.
+// Unmapped fields are written as strings under `labels`, with dots in their keys replaced by `_`.
+type ECSOutputFormatter struct {
+	BufferPool *bufferutil.Pool
+}
+
+// WriteFormat implements write formatter.
+func (ef ECSOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
+	document, err := ef.Document(ctx, e)
+	if err != nil {
+		return err
+	}
+	buffer := ef.BufferPool.Get()
+	defer ef.BufferPool.Put(buffer)
+	if err = json.NewEncoder(buffer).Encode(document); err != nil {
+		return err
+	}
+	_, err = io.Copy(output, buffer)
+	return err
+}
+
+// Document returns the nested ecs document for an event.
+func (ef ECSOutputFormatter) Document(ctx context.Context, e Event) (map[string]interface{}, error) {
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err = decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	fields, ok := decoded.(map[string]interface{})
+	if !ok {
+		fields = map[string]interface{}{FieldMessage: decoded}
+	}
+
+	document := make(map[string]interface{})
+	setECS(document, "@timestamp", e.GetTimestamp().UTC().Format(time.RFC3339Nano))
+	setECS(document, "log.level", e.GetFlag())
+	setECS(document, "ecs.version", ECSVersion)
+	delete(fields, FieldTimestamp)
+	delete(fields, FieldFlag)
+
+	ecsErrorFields(document, fields, e)
+
+	mapping := ecsFieldNames(e)
+	labels := make(map[string]interface{})
+	for key, value := range fields {
+		if key == FieldMessage {
+			setECS(document, "message", value)
+			continue
+		}
+		if name, ok := mapping[key]; ok {
+			if name == "event.duration" {
+				value = ecsDuration(value)
+			}
+			setECS(document, name, value)
+			continue
+		}
+		flattenJSON(key, value, func(key string, value interface{}) {
+			labels[key] = value
+		})
+	}
+
+	if typed, ok := e.(interface{ GetLabels() Labels }); ok {
+		for key, value := range typed.GetLabels() {
+			labels[key] = value
+		}
+	}
+	path, subContextFields := GetSubContextMeta(ctx)
+	if len(path) > 0 {
+		setECS(document, "log.logger", strings.Join(path, "."))
+	}
+	for key, value := range subContextFields {
+		labels[key] = value
+	}
+
+	ecsLabels := make(map[string]string, len(labels))
+	for key, value := range labels {
+		if value == nil {
+			continue
+		}
+		if formatted := fmt.Sprint(value); formatted != "" {
+			ecsLabels[strings.Replace(key, ".", "_", -1)] = formatted
+		}
+	}
+	if len(ecsLabels) > 0 {
+		document["labels"] = ecsLabels
+	}
+	return document, nil
+}
+
+// ecsFieldNames returns the mapping from the json field names of an event to their ecs names.
+func ecsFieldNames(e Event) map[string]string {
+	switch e.(type) {
+	case *AuditEvent:
+		return map[string]string{
+			"verb":       "event.action",
+			"principal":  "user.name",
+			"remoteAddr": "client.ip",
+			"ua":         "user_agent.original",
+		}
+	case *HTTPRequestEvent, *HTTPResponseEvent:
+		return map[string]string{
+			HTTPResponseFieldIP:            "client.ip",
+			HTTPResponseFieldUserAgent:     "user_agent.original",
+			HTTPResponseFieldReferer:       "http.request.referrer",
+			HTTPResponseFieldVerb:          "http.request.method",
+			HTTPResponseFieldPath:          "url.path",
+			HTTPResponseFieldQuery:         "url.query",
+			HTTPResponseFieldHost:          "url.domain",
+			HTTPResponseFieldStatusCode:    "http.response.status_code",
+			HTTPResponseFieldContentLength: "http.response.body.bytes",
+			HTTPResponseFieldContentType:   "http.response.mime_type",
+			HTTPResponseFieldElapsed:       "event.duration",
+		}
+	case *DeprecationEvent:
+		return map[string]string{
+			HTTPResponseFieldIP:        "client.ip",
+			HTTPResponseFieldUserAgent: "user_agent.original",
+			HTTPResponseFieldVerb:      "http.request.method",
+			HTTPResponseFieldPath:      "url.path",
+		}
+	case *TimingEvent:
+		return map[string]string{
+			"operation": "event.action",
+			"elapsed":   "event.duration",
+		}
+	case *QueryEvent:
+		return map[string]string{
+			"engine":   "db.type",
+			"database": "db.instance",
+			"username": "db.user.name",
+			"body":     "db.statement",
+			"elapsed":  "event.duration",
+		}
+	case *RPCEvent:
+		return map[string]string{
+			"method":    "event.action",
+			"peer":      "client.address",
+			"userAgent": "user_agent.original",
+			"elapsed":   "event.duration",
+		}
+	}
+	return nil
+}
+
+// ecsErrorFields sets the `error.*` fields for the error of an event, removing the json error field.
+func ecsErrorFields(document, fields map[string]interface{}, e Event) {
+	var err error
+	switch typed := e.(type) {
+	case *ErrorEvent:
+		err = typed.Err
+		delete(fields, "err")
+	case *QueryEvent:
+		err = typed.Err
+		delete(fields, "err")
+	case *RPCEvent:
+		err = typed.Err
+		delete(fields, "err")
+	}
+	if err == nil {
+		return
+	}
+	setECS(document, "error.type", ex.ErrClass(err).Error())
+	if message := ex.ErrMessage(err); message != "" {
+		setECS(document, "error.message", message)
+	} else {
+		setECS(document, "error.message", err.Error())
+	}
+	if stackTrace := ex.ErrStackTrace(err); stackTrace != nil {
+		setECS(document, "error.stack_trace", strings.Join(stackTrace.Strings(), "\n"))
+	}
+}
+
+// ecsDuration converts an elapsed time in milliseconds to nanoseconds, as ecs durations are in nanoseconds.
+func ecsDuration(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	milliseconds, err := number.Float64()
+	if err != nil {
+		return value
+	}
+	return int64(milliseconds * float64(time.Millisecond))
+}
+
+// setECS sets a value on a nested ecs document by its dotted name, e.g. `client.ip`.
+// Empty strings are skipped.
+func setECS(document map[string]interface{}, name string, value interface{}) {
+	if typed, ok := value.(string); ok && typed == "" {
+		return
+	}
+	if value == nil {
+		return
+	}
+	parts := strings.Split(name, ".")
+	current := document
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func ecsTestDocument(t *testing.T, ctx context.Context, e Event) map[string]interface{} {
+	buffer := new(bytes.Buffer)
+	if err := NewECSOutputFormatter().WriteFormat(ctx, buffer, e); err != nil {
+		t.Fatal(err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+func TestECSOutputFormatterAuditEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	ae := NewAuditEvent("bailey", "update",
+		OptAuditNoun("document"),
+		OptAuditSubject("1234"),
+		OptAuditRemoteAddress("10.0.0.1"),
+		OptAuditUserAgent("curl/7.64.1"),
+		OptAuditExtra(map[string]string{"title": "Quarterly Report"}),
+		OptAuditMetaOptions(OptEventMetaTimestamp(time.Date(2016, 01, 02, 03, 04, 05, 0, time.UTC))),
+	)
+	ae.Labels = Labels{"env": "prod", "k8s.pod": "api-1"}
+	ctx := WithSubContextMeta(context.Background(), []string{"api", "documents"}, Fields{"request": "abc"})
+
+	document := ecsTestDocument(t, ctx, ae)
+	assert.Equal("2016-01-02T03:04:05Z", document["@timestamp"])
+	assert.Equal(map[string]interface{}{"level": Audit, "logger": "api.documents"}, document["log"])
+	assert.Equal(map[string]interface{}{"action": "update"}, document["event"])
+	assert.Equal(map[string]interface{}{"ip": "10.0.0.1"}, document["client"])
+	assert.Equal(map[string]interface{}{"name": "bailey"}, document["user"])
+	assert.Equal(map[string]interface{}{"original": "curl/7.64.1"}, document["user_agent"])
+	assert.Equal(map[string]interface{}{"version": ECSVersion}, document["ecs"])
+	assert.Equal(map[string]interface{}{
+		"noun":        "document",
+		"subject":     "1234",
+		"extra_title": "Quarterly Report",
+		"env":         "prod",
+		"k8s_pod":     "api-1",
+		"request":     "abc",
+	}, document["labels"])
+	assert.Nil(document["verb"])
+	assert.Nil(document["remoteAddr"])
+}
+
+func TestECSOutputFormatterHTTPResponseEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{
+		Method:     "GET",
+		Host:       "example.com",
+		URL:        &url.URL{Path: "/foo", RawQuery: "bar=baz"},
+		RemoteAddr: "10.0.0.2:1234",
+		Header:     http.Header{"User-Agent": {"go-sdk"}},
+	}
+	e := NewHTTPResponseEvent(req,
+		OptHTTPResponseStatusCode(http.StatusOK),
+		OptHTTPResponseElapsed(1500*time.Microsecond),
+		OptHTTPResponseRoute("/:id"),
+	)
+	document := ecsTestDocument(t, context.Background(), e)
+	assert.Equal(map[string]interface{}{"ip": "10.0.0.2"}, document["client"])
+	assert.Equal(map[string]interface{}{"path": "/foo", "query": "bar=baz", "domain": "example.com"}, document["url"])
+	assert.Equal(float64(1500000), document["event"].(map[string]interface{})["duration"])
+	assert.Equal(float64(http.StatusOK), document["http"].(map[string]interface{})["response"].(map[string]interface{})["status_code"])
+	assert.Equal("GET", document["http"].(map[string]interface{})["request"].(map[string]interface{})["method"])
+	assert.Equal("/:id", document["labels"].(map[string]interface{})["route"])
+}
+
+func TestECSOutputFormatterErrorEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	ee := NewErrorEvent(Error, ex.New("connection refused", ex.OptMessage("dial tcp 10.0.0.1:5432")))
+	document := ecsTestDocument(t, context.Background(), ee)
+	errorFields := document["error"].(map[string]interface{})
+	assert.Equal("connection refused", errorFields["type"])
+	assert.Equal("dial tcp 10.0.0.1:5432", errorFields["message"])
+	assert.NotEmpty(errorFields["stack_trace"])
+	assert.Nil(document["labels"])
+
+	document = ecsTestDocument(t, context.Background(), NewMessageEvent(Info, "this is a test"))
+	assert.Equal("this is a test", document["message"])
+}
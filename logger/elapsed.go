@@ -0,0 +1,17 @@
+package logger
+
+import "time"
+
+// ElapsedSince returns the time elapsed since a given start time.
+// If the wall clock moved backwards and there is no monotonic reading, the result is clamped to zero.
+func ElapsedSince(start time.Time) time.Duration {
+	return ElapsedBetween(start, time.Now())
+}
+
+// ElapsedBetween returns the time elapsed between a start and an end time, which is never negative.
+func ElapsedBetween(start, end time.Time) time.Duration {
+	if elapsed := end.Sub(start); elapsed > 0 {
+		return elapsed
+	}
+	return 0
+}
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestElapsedSince(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	time.Sleep(time.Millisecond)
+	assert.True(ElapsedSince(start) >= time.Millisecond)
+
+	assert.Zero(ElapsedSince(time.Now().Add(time.Hour)))
+}
+
+func TestElapsedBetween(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2019, 01, 02, 03, 04, 05, 0, time.UTC)
+	assert.Equal(time.Second, ElapsedBetween(start, start.Add(time.Second)))
+	assert.Zero(ElapsedBetween(start, start.Add(-time.Second)))
+}
@@ -3,8 +3,12 @@ package logger
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 
+	"github.com/blend/go-sdk/ansi"
 	"github.com/blend/go-sdk/ex"
 )
 
@@ -58,15 +62,35 @@ func OptErrorEventState(state interface{}) ErrorEventOption {
 	return func(e *ErrorEvent) { e.State = state }
 }
 
+// OptErrorEventFields sets structured fields on the error event, e.g. the fields of a recovered panic value.
+func OptErrorEventFields(fields map[string]interface{}) ErrorEventOption {
+	return func(e *ErrorEvent) { e.Fields = fields }
+}
+
 // ErrorEvent is an event that wraps an error.
 type ErrorEvent struct {
 	*EventMeta
-	Err   error
-	State interface{}
+	Err    error
+	State  interface{}
+	Fields map[string]interface{}
 }
 
 // WriteText writes the text version of an error.
 func (e ErrorEvent) WriteText(formatter TextFormatter, output io.Writer) {
+	if len(e.Fields) > 0 {
+		var keys []string
+		for key := range e.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var values []string
+		for _, key := range keys {
+			values = append(values, fmt.Sprintf("%s%v", formatter.Colorize(key+":", ansi.ColorLightBlack), e.Fields[key]))
+		}
+		io.WriteString(output, strings.Join(values, " "))
+		io.WriteString(output, Space)
+	}
 	if e.Err != nil {
 		if typed, ok := e.Err.(*ex.Ex); ok {
 			io.WriteString(output, typed.String())
@@ -78,14 +102,16 @@ func (e ErrorEvent) WriteText(formatter TextFormatter, output io.Writer) {
 
 // MarshalJSON implements json.Marshaler.
 func (e ErrorEvent) MarshalJSON() ([]byte, error) {
+	values := map[string]interface{}{
+		"state": e.State,
+	}
 	if _, ok := e.Err.(json.Marshaler); ok {
-		return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
-			"err":   e.Err,
-			"state": e.State,
-		}))
+		values["err"] = e.Err
+	} else {
+		values["err"] = e.Err.Error()
 	}
-	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
-		"err":   e.Err.Error(),
-		"state": e.State,
-	}))
+	if len(e.Fields) > 0 {
+		values["fields"] = e.Fields
+	}
+	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), values))
 }
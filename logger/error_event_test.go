@@ -47,3 +47,19 @@ func TestErrorEventListener(t *testing.T) {
 	ml(context.Background(), ee)
 	assert.True(didCall)
 }
+
+func TestErrorEventFields(t *testing.T) {
+	assert := assert.New(t)
+
+	ee := NewErrorEvent(Fatal, fmt.Errorf("only a test"), OptErrorEventFields(map[string]interface{}{"code": 12, "reason": "bad"}))
+
+	buf := new(bytes.Buffer)
+	ee.WriteText(TextOutputFormatter{NoColor: true}, buf)
+	assert.Equal("code:12 reason:bad only a test", buf.String())
+
+	contents, err := json.Marshal(ee)
+	assert.Nil(err)
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &decoded))
+	assert.Equal(map[string]interface{}{"code": 12.0, "reason": "bad"}, decoded["fields"])
+}
@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ErrorSuppressionOption mutates an error suppression.
+type ErrorSuppressionOption func(*ErrorSuppression)
+
+// OptErrorSuppressionNormalizer sets a function that normalizes error fingerprints, e.g. `NormalizeAddresses`,
+// so errors that only differ by variable substrings are coalesced.
+func OptErrorSuppressionNormalizer(normalizer func(string) string) ErrorSuppressionOption {
+	return func(es *ErrorSuppression) { es.Normalizer = normalizer }
+}
+
+// ErrorSuppression suppresses duplicate error events within a time to live.
+// When the TTL expires, a summary with the count of suppressed events is triggered.
+type ErrorSuppression struct {
+	sync.Mutex
+
+	TTL        time.Duration
+	Normalizer func(string) string
+
+	emit   func(context.Context, *ErrorEvent)
+	errors map[string]*suppressedError
+}
+
+type suppressedError struct {
+	ctx   context.Context
+	event *ErrorEvent
+	count int
+	timer *time.Timer
+}
+
+// Fingerprint returns the fingerprint error events are coalesced by.
+func (es *ErrorSuppression) Fingerprint(e *ErrorEvent) string {
+	parts := []string{e.GetFlag()}
+	for err := e.Err; err != nil; err = ex.ErrInner(err) {
+		parts = append(parts, ex.ErrClass(err).Error(), ex.ErrMessage(err))
+	}
+	fingerprint := strings.Join(parts, "|")
+	if es.Normalizer != nil {
+		return es.Normalizer(fingerprint)
+	}
+	return fingerprint
+}
+
+// Flush stops the pending TTLs and triggers the summaries of any suppressed errors.
+func (es *ErrorSuppression) Flush() {
+	es.Lock()
+	pending := es.errors
+	es.errors = nil
+	es.Unlock()
+
+	for _, suppressed := range pending {
+		suppressed.timer.Stop()
+		es.summarize(suppressed)
+	}
+}
+
+// suppress returns if an error event is a duplicate that shouldn't be triggered.
+func (es *ErrorSuppression) suppress(ctx context.Context, e *ErrorEvent) bool {
+	fingerprint := es.Fingerprint(e)
+
+	es.Lock()
+	defer es.Unlock()
+	if suppressed, ok := es.errors[fingerprint]; ok {
+		suppressed.count++
+		return true
+	}
+	if es.errors == nil {
+		es.errors = make(map[string]*suppressedError)
+	}
+	es.errors[fingerprint] = &suppressedError{
+		ctx:   ctx,
+		event: e,
+		timer: time.AfterFunc(es.TTL, func() { es.expire(fingerprint) }),
+	}
+	return false
+}
+
+func (es *ErrorSuppression) expire(fingerprint string) {
+	es.Lock()
+	suppressed, ok := es.errors[fingerprint]
+	delete(es.errors, fingerprint)
+	es.Unlock()
+
+	if ok {
+		es.summarize(suppressed)
+	}
+}
+
+func (es *ErrorSuppression) summarize(suppressed *suppressedError) {
+	if suppressed.count == 0 || es.emit == nil {
+		return
+	}
+	fields := map[string]interface{}{
+		"suppressed": suppressed.count,
+		"ttl":        es.TTL.String(),
+	}
+	for key, value := range suppressed.event.Fields {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	// the summary is triggered with the context of the first event so it has the same sub-context path and fields.
+	es.emit(withSkipErrorSuppression(suppressed.ctx), NewErrorEvent(suppressed.event.GetFlag(), suppressed.event.Err,
+		OptErrorEventState(suppressed.event.State),
+		OptErrorEventFields(fields),
+	))
+}
+
+var (
+	normalizeIPAddresses  = regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b|\[[0-9a-fA-F:]+\](:\d+)?`)
+	normalizeHexAddresses = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`)
+)
+
+// NormalizeAddresses replaces ip addresses (with optional ports) and hex addresses in a fingerprint with placeholders.
+// It's meant to be used with `OptErrorSuppressionNormalizer(...)`.
+func NormalizeAddresses(fingerprint string) string {
+	fingerprint = normalizeIPAddresses.ReplaceAllString(fingerprint, "<addr>")
+	return normalizeHexAddresses.ReplaceAllString(fingerprint, "<hex>")
+}
+
+type skipErrorSuppressionKey struct{}
+
+func withSkipErrorSuppression(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipErrorSuppressionKey{}, true)
+}
+
+func isSkipErrorSuppression(ctx context.Context) bool {
+	return ctx.Value(skipErrorSuppressionKey{}) != nil
+}
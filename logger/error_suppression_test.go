@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestErrorSuppression(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := MustNew(
+		OptAll(),
+		OptOutput(buffer),
+		OptText(OptTextNoColor(), OptTextHideTimestamp()),
+		OptErrorSuppression(time.Hour),
+	)
+	var received int
+	log.Tap(Error, func(_ Event) { received++ })
+
+	for x := 0; x < 3; x++ {
+		log.Error(ex.New("connection refused", ex.OptInner(fmt.Errorf("dial tcp"))))
+	}
+	log.Error(ex.New("connection refused", ex.OptInner(fmt.Errorf("dial udp"))))
+	log.Warning(ex.New("connection refused", ex.OptInner(fmt.Errorf("dial tcp"))))
+	log.Infof("not an error")
+	log.Infof("not an error")
+	assert.Equal(2, received, "duplicates should be suppressed for listeners")
+	assert.Equal(2, strings.Count(buffer.String(), "["+Error+"]"), "duplicates should not be written")
+	assert.Equal(1, strings.Count(buffer.String(), "["+Warning+"]"))
+	assert.Equal(2, strings.Count(buffer.String(), "not an error"), "other events should not be suppressed")
+	assert.NotContains(buffer.String(), "suppressed")
+
+	log.ErrorSuppression.Flush()
+	assert.Equal(3, received, "the summary should be triggered when flushed")
+	assert.Equal(3, strings.Count(buffer.String(), "["+Error+"]"))
+	assert.Contains(buffer.String(), "suppressed:2")
+
+	// pending summaries are written on close.
+	log.Error(fmt.Errorf("only a test"))
+	log.Error(fmt.Errorf("only a test"))
+	assert.Nil(log.Close())
+	assert.Contains(buffer.String(), "suppressed:1")
+}
+
+func TestErrorSuppressionTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := MustNew(
+		OptAll(),
+		OptOutput(buffer),
+		OptText(OptTextNoColor(), OptTextHideTimestamp()),
+		OptErrorSuppression(10*time.Millisecond),
+	)
+	defer log.Close()
+	summaries := make(chan *ErrorEvent, 1)
+	log.Listen(Error, "test", NewErrorEventListener(func(_ context.Context, e *ErrorEvent) {
+		if e.Fields["suppressed"] != nil {
+			summaries <- e
+		}
+	}))
+
+	log.Error(fmt.Errorf("only a test"))
+	log.Error(fmt.Errorf("only a test"))
+	select {
+	case summary := <-summaries:
+		assert.Equal(1, summary.Fields["suppressed"])
+		assert.Equal("only a test", summary.Err.Error())
+	case <-time.After(time.Second):
+		assert.FailNow("the summary should be triggered when the ttl expires")
+	}
+
+	// the ttl has expired, so the next error is triggered.
+	log.Error(fmt.Errorf("only a test"))
+	assert.Equal(3, strings.Count(buffer.String(), "only a test"))
+}
+
+func TestErrorSuppressionNormalizer(t *testing.T) {
+	assert := assert.New(t)
+
+	es := &ErrorSuppression{Normalizer: NormalizeAddresses}
+	first := NewErrorEvent(Error, ex.New("dial failed", ex.OptMessage("dial tcp 10.0.0.1:5432: connection refused, conn 0xc000123")))
+	second := NewErrorEvent(Error, ex.New("dial failed", ex.OptMessage("dial tcp 10.0.0.2:5433: connection refused, conn 0xc000456")))
+	assert.Equal(es.Fingerprint(first), es.Fingerprint(second))
+	assert.Contains(es.Fingerprint(first), "dial tcp <addr>: connection refused, conn <hex>")
+
+	es.Normalizer = nil
+	assert.NotEqual(es.Fingerprint(first), es.Fingerprint(second))
+}
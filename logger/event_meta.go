@@ -15,7 +15,7 @@ var (
 func NewEventMeta(flag string, options ...EventMetaOption) *EventMeta {
 	em := &EventMeta{
 		Flag:      flag,
-		Timestamp: time.Now().UTC(),
+		Timestamp: SystemClock{}.Now(),
 	}
 	for _, option := range options {
 		option(em)
@@ -47,9 +47,18 @@ type EventMeta struct {
 	Labels
 	Annotations
 
-	Flag      string
+	Flag string
+	// Timestamp is the time the event was created.
+	// It retains the monotonic clock reading by default; it is converted
+	// to UTC only when the event is serialized.
 	Timestamp time.Time
 	FlagColor ansi.Color
+	// Caller is the source location the event was triggered from.
+	// It is only set if the logger was configured with `OptIncludeCaller`.
+	Caller Caller
+	// SchemaVersion is the version of the log schema the event was written with.
+	// It is set by loggers configured with `OptSchemaVersion`.
+	SchemaVersion string
 }
 
 // GetLabels returns the labels.
@@ -64,6 +73,23 @@ func (em EventMeta) GetFlag() string { return em.Flag }
 // GetTimestamp returns the event timestamp.
 func (em EventMeta) GetTimestamp() time.Time { return em.Timestamp }
 
+// GetCaller returns the event caller.
+func (em EventMeta) GetCaller() Caller { return em.Caller }
+
+// SetCaller sets the event caller.
+func (em *EventMeta) SetCaller(caller Caller) { em.Caller = caller }
+
+// SchemaVersionSetter is a type that a schema version can be set on; it is satisfied by `*EventMeta`.
+type SchemaVersionSetter interface {
+	SetSchemaVersion(string)
+}
+
+// GetSchemaVersion returns the event schema version.
+func (em EventMeta) GetSchemaVersion() string { return em.SchemaVersion }
+
+// SetSchemaVersion sets the event schema version.
+func (em *EventMeta) SetSchemaVersion(version string) { em.SchemaVersion = version }
+
 // GetFlagColor returns the event flag color
 func (em EventMeta) GetFlagColor() ansi.Color { return em.FlagColor }
 
@@ -71,7 +97,13 @@ func (em EventMeta) GetFlagColor() ansi.Color { return em.FlagColor }
 func (em EventMeta) Decompose() map[string]interface{} {
 	output := map[string]interface{}{
 		FieldFlag:      em.Flag,
-		FieldTimestamp: em.Timestamp.Format(time.RFC3339Nano),
+		FieldTimestamp: em.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+	if !em.Caller.IsZero() {
+		output[FieldCaller] = em.Caller.String()
+	}
+	if em.SchemaVersion != "" {
+		output[FieldSchema] = em.SchemaVersion
 	}
 	return output
 }
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"strings"
+	"sync"
 )
 
 // NewFlags returns a new flag set from an array of flag values.
@@ -41,7 +42,9 @@ func FlagsAll() *Flags { return &Flags{all: true, flags: make(map[string]bool)}
 func FlagsNone() *Flags { return &Flags{none: true, flags: make(map[string]bool)} }
 
 // Flags is a set of event flags.
+// It is safe to enable and disable flags concurrently with checking if they're enabled.
 type Flags struct {
+	lock  sync.RWMutex
 	flags map[string]bool
 	all   bool
 	none  bool
@@ -49,6 +52,8 @@ type Flags struct {
 
 // Enable enables an event flag.
 func (efs *Flags) Enable(flags ...string) {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
 	efs.none = false
 	for _, flag := range flags {
 		efs.flags[strings.ToLower(strings.TrimSpace(flag))] = true
@@ -57,6 +62,8 @@ func (efs *Flags) Enable(flags ...string) {
 
 // Disable disables a flag.
 func (efs *Flags) Disable(flags ...string) {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
 	for _, flag := range flags {
 		efs.flags[strings.ToLower(strings.TrimSpace(flag))] = false
 	}
@@ -65,18 +72,24 @@ func (efs *Flags) Disable(flags ...string) {
 // SetAll flips the `all` bit on the flag set to true.
 // Note: flags that are explicitly disabled will remain disabled.
 func (efs *Flags) SetAll() {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
 	efs.all = true
 	efs.none = false
 }
 
 // All returns if the all bit is flipped to true.
 func (efs *Flags) All() bool {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
 	return efs.all
 }
 
 // SetNone flips the `none` bit on the flag set to true.
 // It also disables the `all` bit.
 func (efs *Flags) SetNone() {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
 	efs.all = false
 	efs.flags = make(map[string]bool)
 	efs.none = true
@@ -84,11 +97,15 @@ func (efs *Flags) SetNone() {
 
 // None returns if the none bit is flipped to true.
 func (efs *Flags) None() bool {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
 	return efs.none
 }
 
 // IsEnabled checks to see if an event is enabled.
-func (efs Flags) IsEnabled(flag string) bool {
+func (efs *Flags) IsEnabled(flag string) bool {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
 	if efs.all {
 		if efs.flags != nil {
 			if enabled, hasEvent := efs.flags[flag]; hasEvent && !enabled {
@@ -107,12 +124,14 @@ func (efs Flags) IsEnabled(flag string) bool {
 }
 
 // String returns a string representation of the flags.
-func (efs Flags) String() string {
+func (efs *Flags) String() string {
 	return strings.Join(efs.Flags(), ", ")
 }
 
 // Flags returns an array of flags.
-func (efs Flags) Flags() []string {
+func (efs *Flags) Flags() []string {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
 	if efs.none {
 		return []string{FlagNone}
 	}
@@ -136,14 +155,30 @@ func (efs Flags) Flags() []string {
 }
 
 // MergeWith sets the set from another, with the other taking precedence.
-func (efs Flags) MergeWith(other *Flags) {
-	if other.all {
+func (efs *Flags) MergeWith(other *Flags) {
+	if other == nil || other == efs {
+		return
+	}
+
+	// copy the other set first so both locks are never held at once,
+	// which would deadlock merging two sets into each other concurrently.
+	other.lock.RLock()
+	all, none := other.all, other.none
+	flags := make(map[string]bool, len(other.flags))
+	for key, value := range other.flags {
+		flags[key] = value
+	}
+	other.lock.RUnlock()
+
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+	if all {
 		efs.all = true
 	}
-	if other.none {
+	if none {
 		efs.none = true
 	}
-	for key, value := range other.flags {
+	for key, value := range flags {
 		efs.flags[key] = value
 	}
 }
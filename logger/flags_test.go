@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
@@ -115,6 +116,21 @@ func TestEventSetCoalesceWith(t *testing.T) {
 	assert.False(second.IsEnabled(Info))
 }
 
+func TestFlagsMergeWithEachOther(t *testing.T) {
+	assert := assert.New(t)
+
+	first, second := NewFlags(Info), NewFlags(Warning)
+	var wg sync.WaitGroup
+	for x := 0; x < 1000; x++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); first.MergeWith(second) }()
+		go func() { defer wg.Done(); second.MergeWith(first) }()
+	}
+	wg.Wait()
+	assert.True(first.IsEnabled(Warning))
+	assert.True(second.IsEnabled(Info))
+}
+
 func TestFlagSetNone(t *testing.T) {
 	assert := assert.New(t)
 	assert.True(FlagsNone().None())
@@ -17,7 +17,7 @@ func HTTPLogged(log Triggerable) webutil.Middleware {
 				responseEvent := NewHTTPResponseEvent(req,
 					OptHTTPResponseStatusCode(w.StatusCode()),
 					OptHTTPResponseContentLength(w.ContentLength()),
-					OptHTTPResponseElapsed(time.Since(start)),
+					OptHTTPResponseElapsed(ElapsedSince(start)),
 				)
 				if w.Header() != nil {
 					responseEvent.ContentType = w.Header().Get(webutil.HeaderContentType)
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/timeutil"
 	"github.com/blend/go-sdk/webutil"
 )
@@ -17,6 +18,72 @@ var (
 	_ json.Marshaler = (*HTTPResponseEvent)(nil)
 )
 
+// ErrHTTPResponseFieldUnknown is returned when selecting a field that isn't one of the `HTTPResponseFields`.
+const ErrHTTPResponseFieldUnknown ex.Class = "http response event; unknown field"
+
+// HTTP response event field names.
+const (
+	HTTPResponseFieldIP              = "ip"
+	HTTPResponseFieldUserAgent       = "userAgent"
+	HTTPResponseFieldReferer         = "referer"
+	HTTPResponseFieldVerb            = "verb"
+	HTTPResponseFieldPath            = "path"
+	HTTPResponseFieldRoute           = "route"
+	HTTPResponseFieldQuery           = "query"
+	HTTPResponseFieldHost            = "host"
+	HTTPResponseFieldContentLength   = "contentLength"
+	HTTPResponseFieldContentType     = "contentType"
+	HTTPResponseFieldContentEncoding = "contentEncoding"
+	HTTPResponseFieldStatusCode      = "statusCode"
+	HTTPResponseFieldElapsed         = "elapsed"
+	HTTPResponseFieldSpans           = "spans"
+)
+
+// HTTPResponseFields are all the standard fields that can be selected with `OptHTTPResponseFields`.
+var HTTPResponseFields = []string{
+	HTTPResponseFieldIP,
+	HTTPResponseFieldUserAgent,
+	HTTPResponseFieldReferer,
+	HTTPResponseFieldVerb,
+	HTTPResponseFieldPath,
+	HTTPResponseFieldRoute,
+	HTTPResponseFieldQuery,
+	HTTPResponseFieldHost,
+	HTTPResponseFieldContentLength,
+	HTTPResponseFieldContentType,
+	HTTPResponseFieldContentEncoding,
+	HTTPResponseFieldStatusCode,
+	HTTPResponseFieldElapsed,
+	HTTPResponseFieldSpans,
+}
+
+// DefaultHTTPResponseFields are the standard fields written if none are selected.
+var DefaultHTTPResponseFields = []string{
+	HTTPResponseFieldIP,
+	HTTPResponseFieldUserAgent,
+	HTTPResponseFieldVerb,
+	HTTPResponseFieldPath,
+	HTTPResponseFieldRoute,
+	HTTPResponseFieldQuery,
+	HTTPResponseFieldHost,
+	HTTPResponseFieldContentLength,
+	HTTPResponseFieldContentType,
+	HTTPResponseFieldContentEncoding,
+	HTTPResponseFieldStatusCode,
+	HTTPResponseFieldElapsed,
+	HTTPResponseFieldSpans,
+}
+
+// ValidateHTTPResponseFields returns an error if any of the fields aren't one of the `HTTPResponseFields`.
+func ValidateHTTPResponseFields(fields ...string) error {
+	for _, field := range fields {
+		if !isHTTPResponseField(field) {
+			return ex.New(ErrHTTPResponseFieldUnknown, ex.OptMessagef("field: %q", field))
+		}
+	}
+	return nil
+}
+
 // NewHTTPResponseEvent is an event representing a response to an http request.
 func NewHTTPResponseEvent(req *http.Request, options ...HTTPResponseEventOption) *HTTPResponseEvent {
 	hre := &HTTPResponseEvent{
@@ -55,6 +122,11 @@ func OptHTTPResponseRequest(req *http.Request) HTTPResponseEventOption {
 	return func(hre *HTTPResponseEvent) { hre.Request = req }
 }
 
+// OptHTTPResponseClientIP sets a field.
+func OptHTTPResponseClientIP(clientIP string) HTTPResponseEventOption {
+	return func(hre *HTTPResponseEvent) { hre.ClientIP = clientIP }
+}
+
 // OptHTTPResponseRoute sets a field.
 func OptHTTPResponseRoute(route string) HTTPResponseEventOption {
 	return func(hre *HTTPResponseEvent) { hre.Route = route }
@@ -85,6 +157,11 @@ func OptHTTPResponseElapsed(elapsed time.Duration) HTTPResponseEventOption {
 	return func(hre *HTTPResponseEvent) { hre.Elapsed = elapsed }
 }
 
+// OptHTTPResponseSpans sets a field.
+func OptHTTPResponseSpans(spans map[string]time.Duration) HTTPResponseEventOption {
+	return func(hre *HTTPResponseEvent) { hre.Spans = spans }
+}
+
 // OptHTTPResponseHeader sets a field.
 func OptHTTPResponseHeader(header http.Header) HTTPResponseEventOption {
 	return func(hre *HTTPResponseEvent) { hre.Header = header }
@@ -95,6 +172,17 @@ func OptHTTPResponseState(state interface{}) HTTPResponseEventOption {
 	return func(hre *HTTPResponseEvent) { hre.State = state }
 }
 
+// OptHTTPResponseFields sets the standard fields written in the json form of the event.
+// The fields should be validated with `ValidateHTTPResponseFields`; unknown fields are ignored.
+func OptHTTPResponseFields(fields ...string) HTTPResponseEventOption {
+	return func(hre *HTTPResponseEvent) { hre.Fields = fields }
+}
+
+// OptHTTPResponseExtra sets custom fields written in the json form of the event.
+func OptHTTPResponseExtra(extra map[string]interface{}) HTTPResponseEventOption {
+	return func(hre *HTTPResponseEvent) { hre.Extra = extra }
+}
+
 // HTTPResponseEvent is an event type for responses.
 type HTTPResponseEvent struct {
 	*EventMeta
@@ -105,29 +193,88 @@ type HTTPResponseEvent struct {
 	ContentEncoding string
 	StatusCode      int
 	Elapsed         time.Duration
+	Spans           map[string]time.Duration
 	Header          http.Header
 	State           interface{}
+	// ClientIP is the client ip written in the event.
+	// If unset, it is read from the request with `webutil.GetRemoteAddr`.
+	ClientIP string
+	// Fields are the standard fields written in the json form of the event.
+	// If unset, `DefaultHTTPResponseFields` are written.
+	Fields []string
+	// Extra are custom fields written in the json form of the event.
+	Extra map[string]interface{}
 }
 
 // WriteText implements TextWritable.
 func (e HTTPResponseEvent) WriteText(formatter TextFormatter, wr io.Writer) {
-	WriteHTTPResponse(formatter, wr, e.Request, e.StatusCode, e.ContentLength, e.ContentType, e.Elapsed)
+	ip := e.ClientIP
+	if ip == "" {
+		ip = webutil.GetRemoteAddr(e.Request)
+	}
+	writeHTTPResponse(formatter, wr, ip, e.Request, e.StatusCode, e.ContentLength, e.ContentType, e.Elapsed)
 }
 
 // MarshalJSON implements json.Marshaler.
 func (e HTTPResponseEvent) MarshalJSON() ([]byte, error) {
-	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
-		"ip":              webutil.GetRemoteAddr(e.Request),
-		"userAgent":       webutil.GetUserAgent(e.Request),
-		"verb":            e.Request.Method,
-		"path":            e.Request.URL.Path,
-		"route":           e.Route,
-		"query":           e.Request.URL.RawQuery,
-		"host":            e.Request.Host,
-		"contentLength":   e.ContentLength,
-		"contentType":     e.ContentType,
-		"contentEncoding": e.ContentEncoding,
-		"statusCode":      e.StatusCode,
-		"elapsed":         timeutil.Milliseconds(e.Elapsed),
-	}))
+	selected := e.Fields
+	if selected == nil {
+		selected = DefaultHTTPResponseFields
+	}
+	fields := make(map[string]interface{}, len(selected)+len(e.Extra))
+	for key, value := range e.Extra {
+		fields[key] = value
+	}
+	for _, field := range selected {
+		switch field {
+		case HTTPResponseFieldIP:
+			if e.ClientIP != "" {
+				fields[field] = e.ClientIP
+			} else {
+				fields[field] = webutil.GetRemoteAddr(e.Request)
+			}
+		case HTTPResponseFieldUserAgent:
+			fields[field] = webutil.GetUserAgent(e.Request)
+		case HTTPResponseFieldReferer:
+			fields[field] = e.Request.Referer()
+		case HTTPResponseFieldVerb:
+			fields[field] = e.Request.Method
+		case HTTPResponseFieldPath:
+			fields[field] = e.Request.URL.Path
+		case HTTPResponseFieldRoute:
+			fields[field] = e.Route
+		case HTTPResponseFieldQuery:
+			fields[field] = e.Request.URL.RawQuery
+		case HTTPResponseFieldHost:
+			fields[field] = e.Request.Host
+		case HTTPResponseFieldContentLength:
+			fields[field] = e.ContentLength
+		case HTTPResponseFieldContentType:
+			fields[field] = e.ContentType
+		case HTTPResponseFieldContentEncoding:
+			fields[field] = e.ContentEncoding
+		case HTTPResponseFieldStatusCode:
+			fields[field] = e.StatusCode
+		case HTTPResponseFieldElapsed:
+			fields[field] = timeutil.Milliseconds(e.Elapsed)
+		case HTTPResponseFieldSpans:
+			if len(e.Spans) > 0 {
+				spans := make(map[string]float64, len(e.Spans))
+				for name, elapsed := range e.Spans {
+					spans[name] = timeutil.Milliseconds(elapsed)
+				}
+				fields[field] = spans
+			}
+		}
+	}
+	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), fields))
+}
+
+func isHTTPResponseField(field string) bool {
+	for _, known := range HTTPResponseFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
 }
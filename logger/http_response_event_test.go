@@ -54,6 +54,12 @@ func TestNewHTTPResponseEvent(t *testing.T) {
 
 	assert.NotContains(string(contents), "X-Bad", "response headers should not be written to json output")
 	assert.NotContains(string(contents), "definitely nope", "response headers should not be written to json output")
+	assert.NotContains(string(contents), "spans", "spans should be omitted if there are none")
+
+	hre.Spans = map[string]time.Duration{"db": 1500 * time.Microsecond}
+	contents, err = json.Marshal(hre)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"spans":{"db":1.5}`)
 }
 
 func TestHTTPResponseEventListener(t *testing.T) {
@@ -68,3 +74,28 @@ func TestHTTPResponseEventListener(t *testing.T) {
 	listener(context.Background(), NewHTTPResponseEvent(nil))
 	assert.True(didCall)
 }
+
+func TestHTTPResponseEventFields(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Method: "GET", URL: &url.URL{Path: "/foo"}, Header: http.Header{"Referer": {"https://example.com/"}, "User-Agent": {"test"}}}
+	hre := NewHTTPResponseEvent(req,
+		OptHTTPResponseStatusCode(http.StatusOK),
+		OptHTTPResponseFields(HTTPResponseFieldPath, HTTPResponseFieldReferer),
+		OptHTTPResponseExtra(map[string]interface{}{"tenant": "blend"}),
+	)
+	contents, err := json.Marshal(hre)
+	assert.Nil(err)
+
+	var fields map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &fields))
+	assert.Equal("/foo", fields[HTTPResponseFieldPath])
+	assert.Equal("https://example.com/", fields[HTTPResponseFieldReferer])
+	assert.Equal("blend", fields["tenant"])
+	assert.Nil(fields[HTTPResponseFieldUserAgent])
+	assert.Nil(fields[HTTPResponseFieldStatusCode])
+	assert.Equal(HTTPResponse, fields[FieldFlag])
+
+	assert.Nil(ValidateHTTPResponseFields(HTTPResponseFields...))
+	assert.NotNil(ValidateHTTPResponseFields("userAgent", "user_agent"))
+}
@@ -0,0 +1,319 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// these are compile time assertions
+var (
+	_ io.Writer = (*HTTPWriter)(nil)
+	_ io.Closer = (*HTTPWriter)(nil)
+)
+
+const (
+	// ErrHTTPWriterClosed is returned when writing to a closed http writer.
+	ErrHTTPWriterClosed ex.Class = "http writer; writer is closed"
+	// ErrHTTPWriterStatus is returned when the collector responds with a non-2xx status code.
+	ErrHTTPWriterStatus ex.Class = "http writer; collector returned a non-2xx status code"
+)
+
+// Content types sent by http writers.
+const (
+	HTTPWriterContentTypeNDJSON = "application/x-ndjson"
+	HTTPWriterContentTypeJSON   = "application/json"
+)
+
+// NewHTTPWriter returns a new http writer that sends lines to a given endpoint.
+// The writer starts sending immediately, and must be closed with `Close()` or `CloseContext(ctx)` to flush any remaining lines.
+func NewHTTPWriter(endpoint string, options ...HTTPWriterOption) *HTTPWriter {
+	hw := &HTTPWriter{
+		Endpoint:      endpoint,
+		Client:        http.DefaultClient,
+		BatchSize:     DefaultHTTPWriterBatchSize,
+		FlushInterval: DefaultHTTPWriterFlushInterval,
+		QueueDepth:    DefaultHTTPWriterQueueDepth,
+		MaxRetries:    DefaultHTTPWriterMaxRetries,
+		RetryDelay:    DefaultHTTPWriterRetryDelay,
+	}
+	for _, option := range options {
+		option(hw)
+	}
+	hw.queue = make(chan []byte, hw.queueDepthOrDefault())
+	hw.stop = make(chan struct{})
+	hw.done = make(chan struct{})
+	hw.ctx, hw.cancel = context.WithCancel(context.Background())
+	go hw.run()
+	return hw
+}
+
+// HTTPWriterOption is an option for http writers.
+type HTTPWriterOption func(*HTTPWriter)
+
+// OptHTTPWriterClient sets the http client used to send requests.
+func OptHTTPWriterClient(client *http.Client) HTTPWriterOption {
+	return func(hw *HTTPWriter) { hw.Client = client }
+}
+
+// OptHTTPWriterHeader sets a header sent with each request, e.g. an api key.
+func OptHTTPWriterHeader(key, value string) HTTPWriterOption {
+	return func(hw *HTTPWriter) {
+		if hw.Header == nil {
+			hw.Header = http.Header{}
+		}
+		hw.Header.Set(key, value)
+	}
+}
+
+// OptHTTPWriterBatchSize sets the maximum number of lines sent per request.
+func OptHTTPWriterBatchSize(batchSize int) HTTPWriterOption {
+	return func(hw *HTTPWriter) { hw.BatchSize = batchSize }
+}
+
+// OptHTTPWriterFlushInterval sets the interval partial batches are sent on.
+func OptHTTPWriterFlushInterval(interval time.Duration) HTTPWriterOption {
+	return func(hw *HTTPWriter) { hw.FlushInterval = interval }
+}
+
+// OptHTTPWriterQueueDepth sets the number of lines buffered before lines are dropped.
+func OptHTTPWriterQueueDepth(depth int) HTTPWriterOption {
+	return func(hw *HTTPWriter) { hw.QueueDepth = depth }
+}
+
+// OptHTTPWriterRetries sets the number of retries for a failed request, and the delay before the first retry.
+func OptHTTPWriterRetries(maxRetries int, delay time.Duration) HTTPWriterOption {
+	return func(hw *HTTPWriter) {
+		hw.MaxRetries = maxRetries
+		hw.RetryDelay = delay
+	}
+}
+
+// OptHTTPWriterErrors sets a channel that errors sending batches are sent to if it has room.
+func OptHTTPWriterErrors(errors chan error) HTTPWriterOption {
+	return func(hw *HTTPWriter) { hw.Errors = errors }
+}
+
+// OptHTTPWriterJSONArray sets the writer to send batches as a json array rather than newline delimited.
+func OptHTTPWriterJSONArray() HTTPWriterOption {
+	return func(hw *HTTPWriter) { hw.JSONArray = true }
+}
+
+// HTTPWriter is a writer that batches lines, typically json formatted events, and posts them to an http collector.
+// `Write` never blocks; lines that can't be queued or sent are dropped and counted by `Dropped()`.
+type HTTPWriter struct {
+	Endpoint      string
+	Client        *http.Client
+	Header        http.Header
+	BatchSize     int
+	FlushInterval time.Duration
+	QueueDepth    int
+	MaxRetries    int
+	RetryDelay    time.Duration
+	JSONArray     bool
+	Errors        chan error
+
+	queue     chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	closed    int32
+	dropped   int64
+}
+
+// Write queues a line to be sent to the collector.
+// It drops the line if the queue is full.
+func (hw *HTTPWriter) Write(contents []byte) (int, error) {
+	if atomic.LoadInt32(&hw.closed) == 1 {
+		return 0, ex.New(ErrHTTPWriterClosed)
+	}
+	line := make([]byte, len(contents))
+	copy(line, contents)
+	select {
+	case hw.queue <- line:
+	default:
+		atomic.AddInt64(&hw.dropped, 1)
+	}
+	return len(contents), nil
+}
+
+// Dropped returns the number of lines that were dropped, either because
+// the queue was full or because they could not be sent.
+func (hw *HTTPWriter) Dropped() int64 {
+	return atomic.LoadInt64(&hw.dropped)
+}
+
+// Close stops accepting lines and waits for any queued lines to be flushed to the collector.
+func (hw *HTTPWriter) Close() error {
+	return hw.CloseContext(context.Background())
+}
+
+// CloseContext stops accepting lines and flushes any queued lines to the collector.
+// If the context is done before the flush completes, in flight requests are
+// cancelled and the context error is returned.
+func (hw *HTTPWriter) CloseContext(ctx context.Context) error {
+	hw.closeOnce.Do(func() {
+		atomic.StoreInt32(&hw.closed, 1)
+		close(hw.stop)
+	})
+	select {
+	case <-hw.done:
+		return nil
+	case <-ctx.Done():
+		hw.cancel()
+		<-hw.done
+		return ex.New(ctx.Err())
+	}
+}
+
+//
+// internal helpers
+//
+
+func (hw *HTTPWriter) run() {
+	defer close(hw.done)
+	defer hw.cancel()
+
+	ticker := time.NewTicker(hw.flushIntervalOrDefault())
+	defer ticker.Stop()
+
+	var batch [][]byte
+	for {
+		select {
+		case line := <-hw.queue:
+			if batch = append(batch, line); len(batch) >= hw.batchSizeOrDefault() {
+				hw.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				hw.send(batch)
+				batch = nil
+			}
+		case <-hw.stop:
+			for {
+				select {
+				case line := <-hw.queue:
+					if batch = append(batch, line); len(batch) >= hw.batchSizeOrDefault() {
+						hw.send(batch)
+						batch = nil
+					}
+				default:
+					if len(batch) > 0 {
+						hw.send(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// send posts a batch, retrying on failure, and counts the batch as dropped if it can't be sent.
+func (hw *HTTPWriter) send(batch [][]byte) {
+	body := hw.encode(batch)
+	err := hw.post(body)
+	delay := hw.RetryDelay
+	for retry := 0; err != nil && retry < hw.MaxRetries; retry++ {
+		select {
+		case <-time.After(delay):
+		case <-hw.ctx.Done():
+			hw.drop(batch, err)
+			return
+		}
+		delay = delay * 2
+		err = hw.post(body)
+	}
+	if err != nil {
+		hw.drop(batch, err)
+	}
+}
+
+// drop counts a batch as dropped and reports the error that caused it.
+func (hw *HTTPWriter) drop(batch [][]byte, err error) {
+	atomic.AddInt64(&hw.dropped, int64(len(batch)))
+	if hw.Errors != nil {
+		select {
+		case hw.Errors <- err:
+		default:
+		}
+	}
+}
+
+func (hw *HTTPWriter) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, hw.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return ex.New(err)
+	}
+	req = req.WithContext(hw.ctx)
+	for key, values := range hw.Header {
+		req.Header[key] = values
+	}
+	if hw.JSONArray {
+		req.Header.Set("Content-Type", HTTPWriterContentTypeJSON)
+	} else {
+		req.Header.Set("Content-Type", HTTPWriterContentTypeNDJSON)
+	}
+
+	res, err := hw.Client.Do(req)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return ex.New(ErrHTTPWriterStatus, ex.OptMessagef("status code: %d", res.StatusCode))
+	}
+	return nil
+}
+
+// encode joins a batch of lines as newline delimited lines or a json array.
+func (hw *HTTPWriter) encode(batch [][]byte) []byte {
+	buffer := new(bytes.Buffer)
+	if hw.JSONArray {
+		buffer.WriteString("[")
+		for index, line := range batch {
+			if index > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.Write(bytes.TrimSpace(line))
+		}
+		buffer.WriteString("]")
+		return buffer.Bytes()
+	}
+	for _, line := range batch {
+		buffer.Write(bytes.TrimRight(line, "\r\n"))
+		buffer.WriteString(Newline)
+	}
+	return buffer.Bytes()
+}
+
+func (hw *HTTPWriter) batchSizeOrDefault() int {
+	if hw.BatchSize > 0 {
+		return hw.BatchSize
+	}
+	return DefaultHTTPWriterBatchSize
+}
+
+func (hw *HTTPWriter) flushIntervalOrDefault() time.Duration {
+	if hw.FlushInterval > 0 {
+		return hw.FlushInterval
+	}
+	return DefaultHTTPWriterFlushInterval
+}
+
+func (hw *HTTPWriter) queueDepthOrDefault() int {
+	if hw.QueueDepth > 0 {
+		return hw.QueueDepth
+	}
+	return DefaultHTTPWriterQueueDepth
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+type httpWriterTestCollector struct {
+	sync.Mutex
+	Bodies       []string
+	ContentTypes []string
+}
+
+func (c *httpWriterTestCollector) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	c.Lock()
+	c.Bodies = append(c.Bodies, string(body))
+	c.ContentTypes = append(c.ContentTypes, req.Header.Get("Content-Type"))
+	c.Unlock()
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+func TestHTTPWriterBatches(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := new(httpWriterTestCollector)
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	hw := NewHTTPWriter(server.URL, OptHTTPWriterBatchSize(2), OptHTTPWriterFlushInterval(time.Hour))
+	for _, line := range []string{`{"a":1}` + "\n", `{"b":2}` + "\n", `{"c":3}` + "\n"} {
+		_, err := hw.Write([]byte(line))
+		assert.Nil(err)
+	}
+	assert.Nil(hw.Close())
+
+	assert.Equal([]string{"{\"a\":1}\n{\"b\":2}\n", "{\"c\":3}\n"}, collector.Bodies)
+	assert.Equal(HTTPWriterContentTypeNDJSON, collector.ContentTypes[0])
+	assert.Zero(hw.Dropped())
+
+	_, err := hw.Write([]byte("late"))
+	assert.True(ex.Is(err, ErrHTTPWriterClosed))
+}
+
+func TestHTTPWriterFlushInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := new(httpWriterTestCollector)
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	hw := NewHTTPWriter(server.URL, OptHTTPWriterFlushInterval(5*time.Millisecond), OptHTTPWriterJSONArray())
+	defer hw.Close()
+
+	hw.Write([]byte(`{"a":1}` + "\n"))
+	hw.Write([]byte(`{"b":2}` + "\n"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		collector.Lock()
+		count := len(collector.Bodies)
+		collector.Unlock()
+		if count > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	collector.Lock()
+	defer collector.Unlock()
+	assert.NotEmpty(collector.Bodies)
+	var decoded []map[string]int
+	assert.Nil(json.Unmarshal([]byte(strings.Join(collector.Bodies, "")), &decoded), collector.Bodies)
+	assert.Len(decoded, 2)
+	assert.Equal(HTTPWriterContentTypeJSON, collector.ContentTypes[0])
+}
+
+func TestHTTPWriterRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var attempts int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		if attempts == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		contents, _ := ioutil.ReadAll(req.Body)
+		body = string(contents)
+	}))
+	defer server.Close()
+
+	hw := NewHTTPWriter(server.URL, OptHTTPWriterRetries(2, time.Millisecond))
+	hw.Write([]byte("line\n"))
+	assert.Nil(hw.Close())
+
+	assert.Equal(2, attempts)
+	assert.Equal("line\n", body)
+	assert.Zero(hw.Dropped())
+}
+
+func TestHTTPWriterDrops(t *testing.T) {
+	assert := assert.New(t)
+
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+	}))
+	defer server.Close()
+
+	errors := make(chan error, 8)
+	hw := NewHTTPWriter(server.URL, OptHTTPWriterBatchSize(1), OptHTTPWriterQueueDepth(2), OptHTTPWriterRetries(0, 0), OptHTTPWriterErrors(errors))
+
+	// the first line is taken off the queue and blocks in flight.
+	hw.Write([]byte("line\n"))
+	<-received
+
+	for x := 0; x < 5; x++ {
+		count, err := hw.Write([]byte("line\n"))
+		assert.Nil(err, "writes should not block or fail when the queue is full")
+		assert.Equal(5, count)
+	}
+	assert.Equal(3, hw.Dropped())
+
+	// the collector never responds, so closing should give up when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := hw.CloseContext(ctx)
+	close(release)
+	assert.True(ex.Is(err, context.DeadlineExceeded))
+	assert.Equal(6, hw.Dropped())
+	assert.NotEmpty(errors)
+}
@@ -1,9 +1,14 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/blend/go-sdk/bufferutil"
 )
@@ -12,6 +17,14 @@ var (
 	_ WriteFormatter = (*JSONOutputFormatter)(nil)
 )
 
+// JSON time formats that aren't go time layouts.
+const (
+	// JSONTimeFormatUnixMillis writes timestamps as the integer number of milliseconds since the unix epoch.
+	JSONTimeFormatUnixMillis = "unixMillis"
+	// JSONTimeFormatUnixNanos writes timestamps as the integer number of nanoseconds since the unix epoch.
+	JSONTimeFormatUnixNanos = "unixNanos"
+)
+
 // NewJSONOutputFormatter returns a new json event formatter.
 func NewJSONOutputFormatter(options ...JSONOutputFormatterOption) *JSONOutputFormatter {
 	jf := &JSONOutputFormatter{
@@ -33,6 +46,10 @@ func OptJSONConfig(cfg JSONConfig) JSONOutputFormatterOption {
 		jf.Pretty = cfg.Pretty
 		jf.PrettyIndent = cfg.PrettyIndentOrDefault()
 		jf.PrettyPrefix = cfg.PrettyPrefixOrDefault()
+		jf.FieldNames = cfg.FieldNames
+		jf.Flatten = cfg.Flatten
+		jf.FloatPrecision = cfg.FloatPrecision
+		jf.TimeFormat = cfg.TimeFormat
 	}
 }
 
@@ -41,12 +58,46 @@ func OptJSONPretty() JSONOutputFormatterOption {
 	return func(jso *JSONOutputFormatter) { jso.Pretty = true }
 }
 
+// OptJSONFieldNames sets a mapping of default field names to the names they should be written as,
+// e.g. `{"principal": "actor", "verb": "action"}`.
+// Only top level fields are renamed; unmapped fields keep their default names.
+func OptJSONFieldNames(fieldNames map[string]string) JSONOutputFormatterOption {
+	return func(jso *JSONOutputFormatter) { jso.FieldNames = fieldNames }
+}
+
+// OptJSONFlatten sets the json output formatter to flatten nested objects into dotted keys,
+// e.g. `{"extra":{"user":{"id":"1234"}}}` is written as `{"extra.user.id":"1234"}`.
+// Arrays are flattened by index, e.g. `extra.tags.0`.
+func OptJSONFlatten() JSONOutputFormatterOption {
+	return func(jso *JSONOutputFormatter) { jso.Flatten = true }
+}
+
+// OptJSONFloatPrecision sets the json output formatter to round float fields to a number of decimal places,
+// e.g. an elapsed time of `0.30000000000000004` is written as `0.3` with a precision of 3.
+// Trailing zeros are trimmed, and integer fields are not affected. A precision of zero or less disables rounding.
+func OptJSONFloatPrecision(places int) JSONOutputFormatterOption {
+	return func(jso *JSONOutputFormatter) { jso.FloatPrecision = places }
+}
+
+// OptJSONTimeFormat sets how the json output formatter writes event timestamps.
+// It can be a go time layout, e.g. `time.RFC3339`, or `JSONTimeFormatUnixMillis` or `JSONTimeFormatUnixNanos`
+// to write timestamps as integers. Timestamps are always written in UTC. If unset, timestamps are written as `time.RFC3339Nano`.
+func OptJSONTimeFormat(format string) JSONOutputFormatterOption {
+	return func(jso *JSONOutputFormatter) { jso.TimeFormat = format }
+}
+
 // JSONOutputFormatter is a json output formatter.
 type JSONOutputFormatter struct {
 	BufferPool   *bufferutil.Pool
 	Pretty       bool
 	PrettyPrefix string
 	PrettyIndent string
+	FieldNames   map[string]string
+	Flatten      bool
+	// FloatPrecision rounds float fields to a number of decimal places if it is positive.
+	FloatPrecision int
+	// TimeFormat is a go time layout or one of the `JSONTimeFormat...` constants.
+	TimeFormat string
 }
 
 // PrettyPrefixOrDefault returns the pretty prefix or a default.
@@ -74,9 +125,150 @@ func (jw JSONOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 	if jw.Pretty {
 		encoder.SetIndent(jw.PrettyPrefixOrDefault(), jw.PrettyIndentOrDefault())
 	}
-	if err := encoder.Encode(e); err != nil {
+	var value interface{} = e
+	if jw.TimeFormat != "" || len(jw.FieldNames) > 0 || jw.Flatten || jw.FloatPrecision > 0 {
+		transformed, err := jw.transform(e)
+		if err != nil {
+			return err
+		}
+		value = transformed
+	}
+	if err := encoder.Encode(value); err != nil {
 		return err
 	}
 	_, err := io.Copy(output, buffer)
 	return err
 }
+
+// transform decodes the event once and applies the time format, field names, flattening and float precision to it.
+// Events that don't marshal to an object only have their floats rounded.
+func (jw JSONOutputFormatter) transform(e Event) (interface{}, error) {
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return json.RawMessage(contents), nil
+	}
+
+	if fields, ok := decoded.(map[string]interface{}); ok {
+		if jw.TimeFormat != "" {
+			if _, ok := fields[FieldTimestamp]; ok {
+				fields[FieldTimestamp] = formatJSONTime(e.GetTimestamp(), jw.TimeFormat)
+			}
+		}
+		if len(jw.FieldNames) > 0 {
+			fields = jw.renameFields(fields)
+		}
+		if jw.Flatten {
+			fields = flattenFields(fields)
+		}
+		decoded = fields
+	}
+	if jw.FloatPrecision > 0 {
+		decoded = roundJSON(decoded, jw.FloatPrecision)
+	}
+	return decoded, nil
+}
+
+// formatJSONTime returns the json value of a time for a time format.
+func formatJSONTime(t time.Time, format string) interface{} {
+	switch format {
+	case JSONTimeFormatUnixMillis:
+		return t.UnixNano() / int64(time.Millisecond)
+	case JSONTimeFormatUnixNanos:
+		return t.UnixNano()
+	case "":
+		return t.UTC().Format(time.RFC3339Nano)
+	default:
+		return t.UTC().Format(format)
+	}
+}
+
+// renameFields renames the top level fields of a decoded event.
+func (jw JSONOutputFormatter) renameFields(fields map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if name, ok := jw.FieldNames[key]; ok && name != "" {
+			output[name] = value
+		} else {
+			output[key] = value
+		}
+	}
+	return output
+}
+
+// flattenFields flattens the nested objects and arrays of a decoded event into dotted keys.
+func flattenFields(fields map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		flattenJSON(key, value, func(key string, value interface{}) {
+			output[key] = value
+		})
+	}
+	return output
+}
+
+// flattenJSON calls a given function with the dotted key and value of each leaf of a decoded json value, in key order.
+// Nested objects are flattened by key and arrays by index, e.g. `extra.user.id` and `extra.tags.0`;
+// empty objects and arrays are leaves.
+func flattenJSON(key string, value interface{}, leaf func(string, interface{})) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if len(typed) == 0 {
+			leaf(key, typed)
+			return
+		}
+		keys := make([]string, 0, len(typed))
+		for subKey := range typed {
+			keys = append(keys, subKey)
+		}
+		sort.Strings(keys)
+		for _, subKey := range keys {
+			flattenJSON(key+"."+subKey, typed[subKey], leaf)
+		}
+	case []interface{}:
+		if len(typed) == 0 {
+			leaf(key, typed)
+			return
+		}
+		for index, element := range typed {
+			flattenJSON(key+"."+strconv.Itoa(index), element, leaf)
+		}
+	default:
+		leaf(key, value)
+	}
+}
+
+// roundJSON rounds the float numbers within a decoded json value to a number of decimal places.
+// Numbers without a fraction or exponent are integers, and are returned as is.
+func roundJSON(value interface{}, places int) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, subValue := range typed {
+			typed[key] = roundJSON(subValue, places)
+		}
+	case []interface{}:
+		for index, element := range typed {
+			typed[index] = roundJSON(element, places)
+		}
+	case json.Number:
+		if !strings.ContainsAny(string(typed), ".eE") {
+			return typed
+		}
+		parsed, err := typed.Float64()
+		if err != nil {
+			return typed
+		}
+		formatted := strconv.FormatFloat(parsed, 'f', places, 64)
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+		if formatted == "-0" {
+			formatted = "0"
+		}
+		return json.Number(formatted)
+	}
+	return value
+}
@@ -3,7 +3,9 @@ package logger
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
 )
@@ -24,3 +26,166 @@ func TestJSONOutputFormatter(t *testing.T) {
 
 	assert.Contains(buf.String(), "\"message\":\"this is a test\"")
 }
+
+func TestJSONOutputFormatterFieldNames(t *testing.T) {
+	assert := assert.New(t)
+
+	jf := NewJSONOutputFormatter(OptJSONFieldNames(map[string]string{
+		"principal": "actor",
+		"verb":      "action",
+	}))
+
+	ae := NewAuditEvent("bailey", "pooped", OptAuditNoun("lawn"))
+	buf := new(bytes.Buffer)
+	assert.Nil(jf.WriteFormat(context.Background(), buf, ae))
+
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal("bailey", decoded["actor"])
+	assert.Equal("pooped", decoded["action"])
+	assert.Equal("lawn", decoded["noun"])
+	assert.Equal(Audit, decoded[FieldFlag])
+	_, hasPrincipal := decoded["principal"]
+	assert.False(hasPrincipal)
+	_, hasVerb := decoded["verb"]
+	assert.False(hasVerb)
+
+	jf = NewJSONOutputFormatter(OptJSONConfig(JSONConfig{FieldNames: map[string]string{FieldMessage: "msg"}}))
+	buf.Reset()
+	assert.Nil(jf.WriteFormat(context.Background(), buf, NewMessageEvent(Info, "this is a test")))
+	assert.Contains(buf.String(), "\"msg\":\"this is a test\"")
+}
+
+type nestedTestEvent struct {
+	*EventMeta
+	Extra map[string]interface{} `json:"extra"`
+}
+
+func TestJSONOutputFormatterFlatten(t *testing.T) {
+	assert := assert.New(t)
+
+	jf := NewJSONOutputFormatter(OptJSONConfig(JSONConfig{Flatten: true}))
+	assert.True(jf.Flatten)
+
+	e := nestedTestEvent{
+		EventMeta: NewEventMeta(Info),
+		Extra: map[string]interface{}{
+			"user": map[string]interface{}{"id": "1234", "age": 7},
+			"tags": []string{"a", "b"},
+		},
+	}
+	buf := new(bytes.Buffer)
+	assert.Nil(jf.WriteFormat(context.Background(), buf, e))
+
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal("1234", decoded["extra.user.id"])
+	assert.Equal(7.0, decoded["extra.user.age"])
+	assert.Equal("a", decoded["extra.tags.0"])
+	assert.Equal("b", decoded["extra.tags.1"])
+	assert.Nil(decoded["extra"])
+}
+
+func TestJSONOutputFormatterFlattenFieldNames(t *testing.T) {
+	assert := assert.New(t)
+
+	jf := NewJSONOutputFormatter(OptJSONFlatten(), OptJSONFieldNames(map[string]string{"extra": "ctx"}))
+	ae := NewAuditEvent("bailey", "pooped", OptAuditExtra(map[string]string{"lawn": "front"}))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(jf.WriteFormat(context.Background(), buf, ae))
+
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal("front", decoded["ctx.lawn"])
+	assert.Equal("bailey", decoded["principal"])
+}
+
+func TestJSONOutputFormatterFloatPrecision(t *testing.T) {
+	assert := assert.New(t)
+
+	jf := NewJSONOutputFormatter(OptJSONFloatPrecision(3))
+	tenth, fifth := 0.1, 0.2
+	e := nestedTestEvent{
+		EventMeta: NewEventMeta(Info),
+		Extra: map[string]interface{}{
+			"elapsed": tenth + fifth,
+			"ratio":   2.0 / 3.0,
+			"whole":   1.5000001,
+			"count":   int64(1234567890123),
+			"samples": []float64{1.23456, -0.0001},
+		},
+	}
+	buf := new(bytes.Buffer)
+	assert.Nil(jf.WriteFormat(context.Background(), buf, e))
+
+	assert.Contains(buf.String(), `"elapsed":0.3,`)
+	assert.Contains(buf.String(), `"ratio":0.667`)
+	assert.Contains(buf.String(), `"whole":1.5}`)
+	assert.Contains(buf.String(), `"count":1234567890123`, "integers should not be affected")
+	assert.Contains(buf.String(), `"samples":[1.235,0]`)
+
+	jf = NewJSONOutputFormatter(OptJSONConfig(JSONConfig{FloatPrecision: 1, Flatten: true}))
+	buf.Reset()
+	assert.Nil(jf.WriteFormat(context.Background(), buf, e))
+	assert.Contains(buf.String(), `"extra.elapsed":0.3`)
+	assert.Contains(buf.String(), `"extra.samples.0":1.2`)
+
+	jf = NewJSONOutputFormatter()
+	buf.Reset()
+	assert.Nil(jf.WriteFormat(context.Background(), buf, e))
+	assert.Contains(buf.String(), `"elapsed":0.30000000000000004`, "floats should not be rounded by default")
+}
+
+func TestJSONOutputFormatterTimeFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2020, 01, 02, 03, 04, 05, 678900000, time.UTC)
+	me := NewMessageEvent(Info, "this is a test", OptMessageMeta(OptEventMetaTimestamp(ts)))
+
+	jf := NewJSONOutputFormatter(OptJSONTimeFormat(time.RFC3339))
+	buf := new(bytes.Buffer)
+	assert.Nil(jf.WriteFormat(context.Background(), buf, me))
+	assert.Contains(buf.String(), `"_timestamp":"2020-01-02T03:04:05Z"`)
+
+	jf = NewJSONOutputFormatter(OptJSONTimeFormat(JSONTimeFormatUnixMillis))
+	buf.Reset()
+	assert.Nil(jf.WriteFormat(context.Background(), buf, me))
+	assert.Contains(buf.String(), `"_timestamp":1577934245678`)
+
+	jf = NewJSONOutputFormatter(OptJSONConfig(JSONConfig{
+		TimeFormat: JSONTimeFormatUnixNanos,
+		FieldNames: map[string]string{FieldTimestamp: "ts"},
+	}))
+	buf.Reset()
+	assert.Nil(jf.WriteFormat(context.Background(), buf, NewAuditEvent("bailey", "pooped", OptAuditMetaOptions(OptEventMetaTimestamp(ts)))))
+	assert.Contains(buf.String(), `"ts":1577934245678900000`)
+}
+
+func TestJSONOutputFormatterAllOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	jf := NewJSONOutputFormatter(OptJSONConfig(JSONConfig{
+		TimeFormat:     JSONTimeFormatUnixMillis,
+		FieldNames:     map[string]string{"extra": "ctx"},
+		Flatten:        true,
+		FloatPrecision: 2,
+	}))
+	e := nestedTestEvent{
+		EventMeta: NewEventMeta(Info),
+		Extra: map[string]interface{}{
+			"elapsed": 1.0 / 3.0,
+			"empty":   map[string]interface{}{},
+		},
+	}
+	buf := new(bytes.Buffer)
+	assert.Nil(jf.WriteFormat(context.Background(), buf, e))
+
+	assert.Contains(buf.String(), `"ctx.elapsed":0.33`)
+	assert.Contains(buf.String(), `"ctx.empty":{}`)
+
+	ts := time.Date(2020, 01, 02, 03, 04, 05, 678900000, time.UTC)
+	buf.Reset()
+	assert.Nil(jf.WriteFormat(context.Background(), buf, NewMessageEvent(Info, "this is a test", OptMessageMeta(OptEventMetaTimestamp(ts)))))
+	assert.Contains(buf.String(), `"_timestamp":1577934245678`)
+}
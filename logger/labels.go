@@ -1,5 +1,10 @@
 package logger
 
+import (
+	"net/http"
+	"strings"
+)
+
 // Labels are a collection of labels for an event.
 type Labels map[string]string
 
@@ -30,3 +35,23 @@ func (l Labels) Decompose() map[string]interface{} {
 	}
 	return output
 }
+
+// HeadersToLabels returns labels for a given set of header keys, e.g. `X-Request-Id` as `x-request-id`.
+// If no keys are given, all the headers are included, so take care they don't include sensitive values.
+func HeadersToLabels(header http.Header, keys ...string) Labels {
+	labels := make(Labels)
+	if len(keys) == 0 {
+		for key := range header {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		canonical := http.CanonicalHeaderKey(key)
+		values, ok := header[canonical]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		labels[strings.ToLower(canonical)] = strings.Join(values, ", ")
+	}
+	return labels
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
@@ -38,3 +39,25 @@ func TestLabels(t *testing.T) {
 	decomposed := labels.Decompose()
 	assert.Len(decomposed, 2)
 }
+
+func TestHeadersToLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	header := http.Header{}
+	header.Set("X-Request-Id", "request-1234")
+	header.Set("User-Agent", "go-sdk")
+	header.Add("Accept", "text/html")
+	header.Add("Accept", "application/json")
+
+	labels := HeadersToLabels(header, "x-request-id", "ACCEPT", "X-Missing")
+	assert.Equal(Labels{
+		"x-request-id": "request-1234",
+		"accept":       "text/html, application/json",
+	}, labels)
+
+	labels = HeadersToLabels(header)
+	assert.Len(labels, 3)
+	assert.Equal("go-sdk", labels["user-agent"])
+
+	assert.Empty(HeadersToLabels(nil, "X-Request-Id"))
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/blend/go-sdk/bufferutil"
+)
+
+var (
+	_ WriteFormatter = (*LogfmtOutputFormatter)(nil)
+)
+
+// NewLogfmtOutputFormatter returns a new logfmt event formatter.
+func NewLogfmtOutputFormatter(options ...LogfmtOutputFormatterOption) *LogfmtOutputFormatter {
+	lf := &LogfmtOutputFormatter{
+		BufferPool: bufferutil.NewPool(DefaultBufferPoolSize),
+	}
+	for _, option := range options {
+		option(lf)
+	}
+	return lf
+}
+
+// LogfmtOutputFormatterOption is an option for logfmt formatters.
+type LogfmtOutputFormatterOption func(*LogfmtOutputFormatter)
+
+// LogfmtOutputFormatter is an output formatter that writes events as logfmt, i.e. `key=value` pairs.
+// Nested fields are flattened into dotted keys, e.g. `extra.user.id=1234`.
+type LogfmtOutputFormatter struct {
+	BufferPool *bufferutil.Pool
+}
+
+// WriteFormat implements write formatter.
+func (lf LogfmtOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
+	pairs, err := lf.Pairs(e)
+	if err != nil {
+		return err
+	}
+	if typed, ok := e.(interface{ GetLabels() Labels }); ok {
+		labels := typed.GetLabels()
+		for _, key := range sortedLogfmtKeys(labels) {
+			pairs = append(pairs, [2]string{"labels." + key, labels[key]})
+		}
+	}
+	_, fields := GetSubContextMeta(ctx)
+	for _, key := range sortedLogfmtKeys(fields) {
+		pairs = append(pairs, [2]string{"fields." + key, fields[key]})
+	}
+
+	buffer := lf.BufferPool.Get()
+	defer lf.BufferPool.Put(buffer)
+	for index, pair := range pairs {
+		if index > 0 {
+			buffer.WriteString(Space)
+		}
+		buffer.WriteString(pair[0])
+		buffer.WriteString("=")
+		buffer.WriteString(QuoteLogfmt(pair[1]))
+	}
+	buffer.WriteString(Newline)
+	_, err = io.Copy(output, buffer)
+	return err
+}
+
+// Pairs returns the flattened key value pairs for an event, with the timestamp and flag first.
+func (lf LogfmtOutputFormatter) Pairs(e Event) ([][2]string, error) {
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err = decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	fields, ok := decoded.(map[string]interface{})
+	if !ok {
+		fields = map[string]interface{}{
+			FieldFlag: e.GetFlag(),
+			"message": decoded,
+		}
+	}
+
+	var pairs [][2]string
+	for _, key := range []string{FieldTimestamp, FieldFlag} {
+		if value, ok := fields[key]; ok {
+			pairs = flattenLogfmt(pairs, key, value)
+			delete(fields, key)
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pairs = flattenLogfmt(pairs, key, fields[key])
+	}
+	return pairs, nil
+}
+
+// QuoteLogfmt quotes a logfmt value if it is empty or contains spaces, quotes, `=` or control characters.
+func QuoteLogfmt(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if strings.IndexFunc(value, func(r rune) bool {
+		return r == '=' || r == '"' || unicode.IsSpace(r) || unicode.IsControl(r)
+	}) >= 0 {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// flattenLogfmt appends the pairs for a decoded json value, flattening objects and arrays into dotted keys.
+// Empty objects and arrays are written as json.
+func flattenLogfmt(pairs [][2]string, key string, value interface{}) [][2]string {
+	flattenJSON(key, value, func(key string, value interface{}) {
+		switch typed := value.(type) {
+		case nil:
+			pairs = append(pairs, [2]string{key, ""})
+		case string:
+			pairs = append(pairs, [2]string{key, typed})
+		case map[string]interface{}, []interface{}:
+			contents, _ := json.Marshal(typed)
+			pairs = append(pairs, [2]string{key, string(contents)})
+		default:
+			pairs = append(pairs, [2]string{key, fmt.Sprint(typed)})
+		}
+	})
+	return pairs
+}
+
+func sortedLogfmtKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestQuoteLogfmt(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("bailey", QuoteLogfmt("bailey"))
+	assert.Equal(`""`, QuoteLogfmt(""))
+	assert.Equal(`"foo bar"`, QuoteLogfmt("foo bar"))
+	assert.Equal(`"a=b"`, QuoteLogfmt("a=b"))
+	assert.Equal(`"say \"hi\""`, QuoteLogfmt(`say "hi"`))
+	assert.Equal(`"line\nbreak"`, QuoteLogfmt("line\nbreak"))
+}
+
+func TestLogfmtOutputFormatter(t *testing.T) {
+	assert := assert.New(t)
+
+	lf := NewLogfmtOutputFormatter()
+	me := NewMessageEvent(Info, "this is a test",
+		OptMessageMeta(OptEventMetaTimestamp(time.Date(2016, 01, 02, 03, 04, 05, 0, time.UTC))),
+	)
+	me.Labels = Labels{"env": "prod"}
+	ctx := WithSubContextMeta(context.Background(), nil, Fields{"request": "abc=123"})
+
+	buffer := new(bytes.Buffer)
+	assert.Nil(lf.WriteFormat(ctx, buffer, me))
+	assert.Equal(`_timestamp=2016-01-02T03:04:05Z flag=info message="this is a test" labels.env=prod fields.request="abc=123"`+"\n", buffer.String())
+}
+
+func TestLogfmtOutputFormatterFlattens(t *testing.T) {
+	assert := assert.New(t)
+
+	lf := NewLogfmtOutputFormatter()
+	ae := NewAuditEvent("bailey", "pooped",
+		OptAuditExtra(map[string]string{"lawn": "front"}),
+	)
+	pairs, err := lf.Pairs(ae)
+	assert.Nil(err)
+	assert.Equal(FieldTimestamp, pairs[0][0])
+	assert.Equal([2]string{FieldFlag, Audit}, pairs[1])
+	assert.Any(pairs, func(v interface{}) bool { return v.([2]string) == [2]string{"extra.lawn", "front"} })
+	assert.Any(pairs, func(v interface{}) bool { return v.([2]string) == [2]string{"principal", "bailey"} })
+
+	pairs = flattenLogfmt(nil, "extra", map[string]interface{}{
+		"user":  map[string]interface{}{"id": "1234", "admin": true},
+		"tags":  []interface{}{"a", "b"},
+		"none":  nil,
+		"empty": map[string]interface{}{},
+	})
+	assert.Equal([][2]string{
+		{"extra.empty", "{}"},
+		{"extra.none", ""},
+		{"extra.tags.0", "a"},
+		{"extra.tags.1", "b"},
+		{"extra.user.admin", "true"},
+		{"extra.user.id", "1234"},
+	}, pairs)
+}
+
+func TestConfigFormatterLogfmt(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := Config{Format: FormatLogfmt}.Formatter().(*LogfmtOutputFormatter)
+	assert.True(ok)
+}
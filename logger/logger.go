@@ -4,7 +4,12 @@ import (
 	"context"
 	"io"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
 )
 
 // New returns a new logger with a given set of enabled flags.
@@ -70,11 +75,63 @@ type Logger struct {
 	Context
 
 	RecoverPanics bool
+	IncludeCaller bool
+	CallerSkip    int
+	SchemaVersion string
+
+	// ListenerQueueDepth is the number of events that can be queued for each listener.
+	// If unset, `DefaultWorkerQueueDepth` is used.
+	ListenerQueueDepth int
+	// ListenerSaturationPolicy determines what happens when a listener's queue is full.
+	ListenerSaturationPolicy SaturationPolicy
+	// ListenerMaxPanics is the number of times a listener can panic before it is disabled.
+	// If unset, listeners are never disabled.
+	ListenerMaxPanics int
+	// ListenerTimeout is how long a listener can take to process an event before it's abandoned.
+	// If unset, listeners are never abandoned. See `Worker.Timeout` for the ordering implications.
+	ListenerTimeout time.Duration
+	// ErrorSuppression, if set, suppresses duplicate error events; see `OptErrorSuppression`.
+	ErrorSuppression *ErrorSuppression
+	// Clock, if set, stamps the events the logger creates, e.g. with `Info` or `Error`; otherwise `time.Now()` is used.
+	Clock Clock
 
 	Output    io.Writer
 	Formatter WriteFormatter
+	// ErrorOutput, if set, is written to instead of `Output` for error and fatal events.
+	ErrorOutput io.Writer
+	// ErrorFormatter, if set, formats the events written to the `ErrorOutput`; otherwise `Formatter` is used.
+	ErrorFormatter WriteFormatter
+
 	Errors    chan error
 	Listeners map[string]map[string]*Worker
+
+	dropped int64
+
+	taps    map[string]map[uint64]func(context.Context, Event)
+	nextTap uint64
+}
+
+// Dropped returns the number of events dropped for listeners because their queues were full.
+// Events are only dropped if the `ListenerSaturationPolicy` is `SaturationPolicyDrop`.
+func (l *Logger) Dropped() int64 {
+	return atomic.LoadInt64(&l.dropped)
+}
+
+// EnableFlag enables a flag at runtime.
+// It is safe to call concurrently with triggering events, and takes effect for subsequent events.
+func (l *Logger) EnableFlag(flag string) {
+	l.Flags.Enable(flag)
+}
+
+// DisableFlag disables a flag at runtime.
+// It is safe to call concurrently with triggering events, and takes effect for subsequent events.
+func (l *Logger) DisableFlag(flag string) {
+	l.Flags.Disable(flag)
+}
+
+// IsFlagEnabled returns if a flag is enabled.
+func (l *Logger) IsFlagEnabled(flag string) bool {
+	return l.Flags.IsEnabled(flag)
 }
 
 // HasListeners returns if there are registered listener for an event.
@@ -108,74 +165,117 @@ func (l *Logger) HasListener(flag, listenerName string) bool {
 	return ok
 }
 
-// Listen adds a listener for a given flag.
-func (l *Logger) Listen(flag, listenerName string, listener Listener) {
+// ListenerNames returns the names of the listeners registered for a flag, sorted alphabetically.
+func (l *Logger) ListenerNames(flag string) []string {
 	l.Lock()
 	defer l.Unlock()
 
 	if l.Listeners == nil {
-		l.Listeners = make(map[string]map[string]*Worker)
+		return nil
 	}
-
-	w := NewWorker(listener)
-	if listeners, ok := l.Listeners[flag]; ok {
-		listeners[listenerName] = w
-	} else {
-		l.Listeners[flag] = map[string]*Worker{
-			listenerName: w,
-		}
+	listeners, ok := l.Listeners[flag]
+	if !ok {
+		return nil
 	}
-	go w.Start()
-	<-w.NotifyStarted()
+	names := make([]string, 0, len(listeners))
+	for name := range listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
-// RemoveListeners clears *all* listeners for a Flag.
-func (l *Logger) RemoveListeners(flag string) {
-	l.Lock()
-	defer l.Unlock()
+// Listen adds a listener for a given flag.
+// If a listener is already registered for the flag with the same name, it is replaced and stopped.
+func (l *Logger) Listen(flag, listenerName string, listener Listener) {
+	l.listen(flag, listenerName, listener, l.ListenerSaturationPolicy, l.ListenerMaxPanics, l.ListenerTimeout)
+}
 
+// ListenWithTimeout adds a listener for a given flag that is abandoned, with a warning written to the output,
+// if it takes longer than a given timeout to process an event, overriding the logger's `ListenerTimeout`.
+// See `Worker.Timeout` for the ordering implications.
+func (l *Logger) ListenWithTimeout(flag, listenerName string, timeout time.Duration, listener Listener) {
+	l.listen(flag, listenerName, listener, l.ListenerSaturationPolicy, l.ListenerMaxPanics, timeout)
+}
+
+// ListenBlocking adds a listener for a given flag that never drops events; if its queue is full,
+// triggering the event blocks until there is space.
+func (l *Logger) ListenBlocking(flag, listenerName string, listener Listener) {
+	l.listen(flag, listenerName, listener, SaturationPolicyBlock, 0, 0)
+}
+
+func (l *Logger) listen(flag, listenerName string, listener Listener, policy SaturationPolicy, maxPanics int, timeout time.Duration) {
+	l.Lock()
 	if l.Listeners == nil {
-		return
+		l.Listeners = make(map[string]map[string]*Worker)
 	}
 
+	options := []WorkerOption{
+		OptWorkerSaturationPolicy(policy),
+		OptWorkerMaxPanics(maxPanics),
+		OptWorkerPanicHandler(func(err error) { l.writeListenerPanic(flag, listenerName, err) }),
+		OptWorkerTimeout(timeout),
+		OptWorkerTimeoutHandler(func(err error) { l.writeListenerTimeout(flag, listenerName, err) }),
+	}
+	if l.ListenerQueueDepth > 0 {
+		options = append(options, OptWorkerQueueDepth(l.ListenerQueueDepth))
+	}
+	w := NewWorker(listener, options...)
+	go w.Start()
+	<-w.NotifyStarted()
+
 	listeners, ok := l.Listeners[flag]
 	if !ok {
-		return
+		listeners = make(map[string]*Worker)
+		l.Listeners[flag] = listeners
 	}
+	replaced := listeners[listenerName]
+	listeners[listenerName] = w
+	l.Unlock()
 
-	for _, l := range listeners {
-		l.Stop()
+	if replaced != nil {
+		replaced.Stop()
 	}
+}
 
+// RemoveListeners clears *all* listeners for a Flag.
+// Events already queued for the listeners are processed before it returns;
+// events triggered after it returns are not delivered to the removed listeners.
+// It is safe to call while events are being triggered.
+func (l *Logger) RemoveListeners(flag string) {
+	l.Lock()
+	listeners := l.Listeners[flag]
 	delete(l.Listeners, flag)
+	l.Unlock()
+
+	for _, worker := range listeners {
+		worker.Stop()
+	}
 }
 
 // RemoveListener clears a specific listener for a Flag.
+// Events already queued for the listener are processed before it returns;
+// events triggered after it returns are not delivered to the removed listener.
+// It is safe to call while events are being triggered.
 func (l *Logger) RemoveListener(flag, listenerName string) {
 	l.Lock()
-	defer l.Unlock()
-
-	if l.Listeners == nil {
-		return
-	}
-
 	listeners, ok := l.Listeners[flag]
 	if !ok {
+		l.Unlock()
 		return
 	}
-
 	worker, ok := listeners[listenerName]
 	if !ok {
+		l.Unlock()
 		return
 	}
-
-	worker.Stop()
-	<-worker.NotifyStopped()
-
 	delete(listeners, listenerName)
 	if len(listeners) == 0 {
 		delete(l.Listeners, flag)
 	}
+	l.Unlock()
+
+	worker.Stop()
 }
 
 // Trigger fires the listeners for a given event asynchronously, and writes the event to the output.
@@ -192,21 +292,57 @@ func (l *Logger) trigger(ctx context.Context, e Event, sync bool) {
 		return
 	}
 
+	if l.ErrorSuppression != nil && !isSkipErrorSuppression(ctx) {
+		if typed, ok := e.(*ErrorEvent); ok && l.ErrorSuppression.suppress(ctx, typed) {
+			return
+		}
+	}
+
+	if l.IncludeCaller {
+		if typed, ok := e.(CallerSetter); ok {
+			if caller, ok := GetCaller(callerDepth + l.CallerSkip); ok {
+				typed.SetCaller(caller)
+			}
+		}
+	}
+
+	if l.SchemaVersion != "" {
+		if typed, ok := e.(SchemaVersionSetter); ok {
+			typed.SetSchemaVersion(l.SchemaVersion)
+		}
+	}
+
 	if !IsSkipTrigger(ctx) {
-		var listeners map[string]*Worker
+		// the listeners are copied while locked so they can be
+		// added or removed while the event is being delivered.
+		var listeners []*Worker
+		var taps []func(context.Context, Event)
 		l.Lock()
-		if l.Listeners != nil {
-			if flagListeners, ok := l.Listeners[flag]; ok {
-				listeners = flagListeners
+		if flagListeners, ok := l.Listeners[flag]; ok {
+			listeners = make([]*Worker, 0, len(flagListeners))
+			for _, listener := range flagListeners {
+				listeners = append(listeners, listener)
+			}
+		}
+		if flagTaps, ok := l.taps[flag]; ok {
+			taps = make([]func(context.Context, Event), 0, len(flagTaps))
+			for _, tap := range flagTaps {
+				taps = append(taps, tap)
 			}
 		}
 		l.Unlock()
 
+		for _, tap := range taps {
+			tap(ctx, e)
+		}
+
 		for _, listener := range listeners {
 			if sync {
 				listener.Process(EventWithContext{ctx, e})
 			} else {
-				listener.Work <- EventWithContext{ctx, e}
+				if !listener.Enqueue(EventWithContext{ctx, e}) {
+					atomic.AddInt64(&l.dropped, 1)
+				}
 			}
 		}
 	}
@@ -216,8 +352,16 @@ func (l *Logger) trigger(ctx context.Context, e Event, sync bool) {
 
 // Write writes an event synchronously to the writer either as a normal even or as an error.
 func (l *Logger) Write(ctx context.Context, e Event) {
+	output, formatter := l.Output, l.Formatter
+	if l.ErrorOutput != nil && isErrorOutputFlag(e.GetFlag()) {
+		output = l.ErrorOutput
+		if l.ErrorFormatter != nil {
+			formatter = l.ErrorFormatter
+		}
+	}
+
 	// if a formater or the output are unset, bail.
-	if l.Formatter == nil || l.Output == nil {
+	if formatter == nil || output == nil {
 		return
 	}
 
@@ -225,18 +369,48 @@ func (l *Logger) Write(ctx context.Context, e Event) {
 		return
 	}
 
-	err := l.Formatter.WriteFormat(ctx, l.Output, e)
+	err := formatter.WriteFormat(ctx, output, e)
 	if err != nil && l.Errors != nil {
 		l.Errors <- err
 	}
 }
 
+// isErrorOutputFlag returns if events with a flag are written to the error output.
+func isErrorOutputFlag(flag string) bool {
+	return flag == Error || flag == Fatal
+}
+
+// writeListenerPanic writes an error event for a listener that panicked.
+// The event is only written to the output, and is not triggered, so a listener
+// that panics on error events can't cause a loop.
+func (l *Logger) writeListenerPanic(flag, listenerName string, err error) {
+	l.Write(context.Background(), NewErrorEvent(Error, ex.New(err, ex.OptMessagef("listener %q for %q panicked", listenerName, flag)), OptErrorEventMetaOptions(OptEventMetaTimestamp(l.now()))))
+}
+
+// writeListenerTimeout writes a warning event for a listener that was abandoned because it took too long.
+// Like panics, the event is only written to the output.
+func (l *Logger) writeListenerTimeout(flag, listenerName string, err error) {
+	l.Write(context.Background(), NewErrorEvent(Warning, ex.New(err, ex.OptMessagef("listener %q for %q timed out; %s", listenerName, flag, ex.ErrMessage(err))), OptErrorEventMetaOptions(OptEventMetaTimestamp(l.now()))))
+}
+
+// now returns the current time from the logger clock, or `time.Now()` if it's unset.
+func (l *Logger) now() time.Time {
+	if l == nil || l.Clock == nil {
+		return time.Now()
+	}
+	return l.Clock.Now()
+}
+
 // --------------------------------------------------------------------------------
 // finalizers
 // --------------------------------------------------------------------------------
 
 // Close releases shared resources for the agent.
 func (l *Logger) Close() error {
+	if l.ErrorSuppression != nil {
+		l.ErrorSuppression.Flush()
+	}
+
 	l.Lock()
 	defer l.Unlock()
 
@@ -3,7 +3,11 @@ package logger
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -188,3 +192,389 @@ func TestLoggerProd(t *testing.T) {
 	assert.True(p.Flags.IsEnabled("bailey"))
 	assert.True(p.Formatter.(*TextOutputFormatter).NoColor)
 }
+
+func TestLoggerIncludeCaller(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log, err := New(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptIncludeCaller(0),
+	)
+	assert.Nil(err)
+	assert.True(log.IncludeCaller)
+
+	_, _, line, _ := runtime.Caller(0)
+	log.Infof("this is infof")
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "this is triggered"))
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(lines, 2)
+	assert.Equal(fmt.Sprintf("[info] logger_test.go:%d this is infof", line+1), lines[0])
+	assert.Equal(fmt.Sprintf("[info] logger_test.go:%d this is triggered", line+2), lines[1])
+
+	output.Reset()
+	log.Formatter = NewJSONOutputFormatter()
+	log.Infof("this is json")
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(output.Bytes(), &decoded))
+	assert.True(strings.HasSuffix(decoded[FieldCaller].(string), fmt.Sprintf("logger_test.go:%d", line+11)))
+}
+
+func TestLoggerIncludeCallerSkip(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptIncludeCaller(1),
+	)
+
+	helper := func(message string) {
+		log.Info(message)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	helper("through a helper")
+	assert.Equal(fmt.Sprintf("[info] logger_test.go:%d through a helper\n", line+1), output.String())
+}
+
+func TestLoggerIncludeCallerDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+	)
+	e := NewMessageEvent(Info, "no caller")
+	log.SyncTrigger(context.Background(), e)
+	assert.True(e.Caller.IsZero())
+	assert.Equal("[info] no caller\n", output.String())
+
+	log = MustNew(OptOutput(output), OptIncludeCaller(0), OptDisabled(Info))
+	e = NewMessageEvent(Info, "filtered")
+	log.SyncTrigger(context.Background(), e)
+	assert.True(e.Caller.IsZero())
+}
+
+func TestLoggerSchemaVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptJSON(),
+		OptAll(),
+		OptSchemaVersion("2"),
+	)
+	defer log.Close()
+
+	log.SyncTrigger(context.Background(), NewCRUDAuditEvent("bailey", VerbUpdate, "address", "home"))
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal("2", decoded[FieldSchema])
+	assert.Equal("bailey", decoded["principal"])
+
+	output.Reset()
+	log.SchemaVersion = ""
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "no schema"))
+	decoded = nil
+	assert.Nil(json.Unmarshal(output.Bytes(), &decoded))
+	_, ok := decoded[FieldSchema]
+	assert.False(ok, "the schema field should be omitted if no version is set")
+}
+
+func TestLoggerClock(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptJSON(),
+		OptAll(),
+		OptClock(StaticClock(time.Date(2016, 01, 02, 03, 04, 05, 06, time.UTC))),
+	)
+	defer log.Close()
+
+	log.Info("hello")
+	assert.Nil(log.Drain())
+	assert.Equal(`{"_timestamp":"2016-01-02T03:04:05.000000006Z","flag":"info","message":"hello"}`+"\n", output.String())
+
+	output.Reset()
+	log.Error(fmt.Errorf("bad"))
+	assert.Nil(log.Drain())
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal("2016-01-02T03:04:05.000000006Z", decoded[FieldTimestamp])
+}
+
+func TestLoggerListenerNames(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew()
+	defer log.Close()
+
+	assert.Empty(log.ListenerNames(Info))
+
+	log.Listen(Info, "foo", NewMessageEventListener(func(_ context.Context, me *MessageEvent) {}))
+	log.Listen(Info, "bar", NewMessageEventListener(func(_ context.Context, me *MessageEvent) {}))
+	log.Listen(Error, "buzz", NewMessageEventListener(func(_ context.Context, me *MessageEvent) {}))
+	assert.Equal([]string{"bar", "foo"}, log.ListenerNames(Info))
+	assert.Equal([]string{"buzz"}, log.ListenerNames(Error))
+	assert.Empty(log.ListenerNames(Fatal))
+
+	log.RemoveListener(Info, "bar")
+	assert.Equal([]string{"foo"}, log.ListenerNames(Info))
+	log.RemoveListener(Info, "foo")
+	assert.Empty(log.ListenerNames(Info))
+	assert.False(log.HasListeners(Info))
+}
+
+func TestLoggerRemoveListenerWhileTriggering(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptAll(), OptOutput(nil))
+	defer log.Close()
+
+	var count int32
+	var mu sync.Mutex
+	listener := NewMessageEventListener(func(_ context.Context, me *MessageEvent) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for index := 0; index < 4; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					log.Info("concurrent")
+				}
+			}
+		}()
+	}
+	for index := 0; index < 32; index++ {
+		log.Listen(Info, fmt.Sprintf("listener-%d", index%4), listener)
+		log.RemoveListener(Info, fmt.Sprintf("listener-%d", (index+1)%4))
+	}
+	log.RemoveListeners(Info)
+	assert.False(log.HasListeners(Info))
+
+	mu.Lock()
+	removed := count
+	mu.Unlock()
+	log.Info("after removal")
+	close(done)
+	wg.Wait()
+	log.Drain()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(removed, count, "removed listeners should not receive new events")
+}
+
+func TestLoggerSlowListenerDoesNotDelayFastListener(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(
+		OptOutput(nil),
+		OptListenerQueueDepth(4),
+		OptListenerSaturationPolicy(SaturationPolicyDrop),
+	)
+	defer log.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	log.Listen(Info, "slow", NewMessageEventListener(func(_ context.Context, _ *MessageEvent) {
+		<-release
+	}))
+
+	fast := make(chan string, 12)
+	log.Listen(Info, "fast", NewMessageEventListener(func(_ context.Context, me *MessageEvent) {
+		fast <- me.Message
+	}))
+
+	started := time.Now()
+	for x := 0; x < 12; x++ {
+		log.Info(fmt.Sprint(x))
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(time.Since(started) < time.Second, "triggering should not block on the slow listener")
+
+	for x := 0; x < 12; x++ {
+		select {
+		case message := <-fast:
+			assert.Equal(fmt.Sprint(x), message, "listener ordering should be preserved")
+		case <-time.After(time.Second):
+			assert.FailNow("the fast listener was delayed by the slow listener")
+		}
+	}
+
+	// the slow listener is processing (at most) one event and has four queued, the rest are dropped.
+	assert.True(log.Dropped() >= 7)
+	assert.Equal(log.Dropped(), log.Listeners[Info]["slow"].Dropped())
+	assert.Zero(log.Listeners[Info]["fast"].Dropped())
+}
+
+func TestLoggerSaturationPolicyBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptListenerQueueDepth(1))
+	defer log.Close()
+
+	var processed []string
+	done := make(chan struct{})
+	log.Listen(Info, "blocking", NewMessageEventListener(func(_ context.Context, me *MessageEvent) {
+		processed = append(processed, me.Message)
+		if len(processed) == 5 {
+			close(done)
+		}
+	}))
+	assert.Equal(1, cap(log.Listeners[Info]["blocking"].Work))
+
+	for x := 0; x < 5; x++ {
+		log.Info(fmt.Sprint(x))
+	}
+	<-done
+	assert.Equal([]string{"0", "1", "2", "3", "4"}, processed)
+	assert.Zero(log.Dropped())
+}
+
+func TestLoggerFlagsRuntime(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(OptOutput(output), OptText(OptTextHideTimestamp(), OptTextNoColor()))
+	assert.False(log.IsFlagEnabled(Debug))
+
+	log.Debug("hidden")
+	log.EnableFlag(Debug)
+	assert.True(log.IsFlagEnabled(Debug))
+	log.Debug("shown")
+	log.DisableFlag(Debug)
+	assert.False(log.IsFlagEnabled(Debug))
+	log.Debug("hidden again")
+
+	assert.Equal("[debug] shown\n", output.String())
+}
+
+// this test is intended to be run with the race detector, i.e. `go test -race`.
+func TestLoggerFlagsRuntimeConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(new(bytes.Buffer)))
+
+	wg := sync.WaitGroup{}
+	for x := 0; x < 4; x++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for y := 0; y < 100; y++ {
+				if y%2 == 0 {
+					log.EnableFlag(Debug)
+				} else {
+					log.DisableFlag(Debug)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for y := 0; y < 100; y++ {
+				log.Debugf("debug %d", y)
+				_ = log.Flags.String()
+			}
+		}()
+	}
+	wg.Wait()
+
+	log.EnableFlag(Debug)
+	assert.True(log.IsFlagEnabled(Debug))
+}
+
+func TestLoggerListenerPanicIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(OptOutput(output), OptText(OptTextNoColor(), OptTextHideTimestamp()), OptListenerMaxPanics(2))
+	defer log.Close()
+
+	var panicked, processed int
+	log.Listen(Info, "buggy", func(_ context.Context, _ Event) {
+		panicked++
+		panic("only a flesh wound")
+	})
+	log.Listen(Info, "healthy", func(_ context.Context, _ Event) {
+		processed++
+	})
+
+	for x := 0; x < 3; x++ {
+		log.SyncTrigger(context.Background(), NewMessageEvent(Info, fmt.Sprint(x)))
+	}
+
+	assert.Equal(3, processed, "the other listeners should still run")
+	assert.Equal(2, panicked, "the listener should be disabled after it panics twice")
+	assert.True(log.Listeners[Info]["buggy"].Disabled())
+	assert.Equal(2, log.Listeners[Info]["buggy"].Panics())
+	assert.False(log.Listeners[Info]["healthy"].Disabled())
+
+	assert.Equal(2, strings.Count(output.String(), `listener "buggy" for "info" panicked`), output.String())
+	assert.Contains(output.String(), "only a flesh wound")
+}
+
+func TestLoggerListenerPanicAsync(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil))
+	defer log.Close()
+
+	processed := make(chan struct{}, 1)
+	log.Listen(Info, "buggy", func(_ context.Context, _ Event) {
+		panic("only a flesh wound")
+	})
+	log.Listen(Info, "healthy", func(_ context.Context, _ Event) {
+		processed <- struct{}{}
+	})
+
+	log.Info("test")
+	<-processed
+	assert.Nil(log.Drain())
+	assert.Equal(1, log.Listeners[Info]["buggy"].Panics())
+	assert.False(log.Listeners[Info]["buggy"].Disabled(), "listeners are never disabled by default")
+}
+
+func TestLoggerListenerTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(OptOutput(output), OptText(OptTextNoColor(), OptTextHideTimestamp()), OptListenerTimeout(10*time.Millisecond))
+	defer log.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	deadlines := make(chan bool, 1)
+	log.Listen(Info, "slow", func(ctx context.Context, _ Event) {
+		_, hasDeadline := ctx.Deadline()
+		deadlines <- hasDeadline
+		<-release
+	})
+	var processed int
+	log.ListenWithTimeout(Info, "fast", time.Second, func(_ context.Context, _ Event) {
+		processed++
+	})
+
+	started := time.Now()
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "test"))
+	assert.True(time.Since(started) < time.Second, "the slow listener should be abandoned")
+	assert.True(<-deadlines, "the listener context should have the timeout as its deadline")
+	assert.Equal(1, processed)
+	assert.Contains(output.String(), `listener "slow" for "info" timed out; timeout: 10ms`)
+}
@@ -0,0 +1,22 @@
+package loggertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// AssertEventImplements fails a test if an event doesn't implement the same interfaces as the built-in events.
+func AssertEventImplements(t testing.TB, e logger.Event) bool {
+	t.Helper()
+	if e == nil {
+		t.Errorf("logger event is nil")
+		return false
+	}
+	if missing := logger.MissingEventInterfaces(e); len(missing) > 0 {
+		t.Errorf("logger event %T does not implement: %s", e, strings.Join(missing, ", "))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package loggertest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+type textOnlyTestEvent struct {
+	*logger.EventMeta
+}
+
+func (e textOnlyTestEvent) String() string { return "text only" }
+
+type mockTB struct {
+	testing.TB
+	errors []string
+}
+
+func (m *mockTB) Helper() {}
+
+func (m *mockTB) Errorf(format string, args ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEventImplements(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(AssertEventImplements(t, logger.NewMessageEvent(logger.Info, "this is a test")))
+
+	mock := new(mockTB)
+	assert.False(AssertEventImplements(mock, textOnlyTestEvent{EventMeta: logger.NewEventMeta(logger.Info)}))
+	assert.Len(mock.errors, 1)
+	assert.Contains(mock.errors[0], "loggertest.textOnlyTestEvent")
+	assert.Contains(mock.errors[0], "logger.TextWritable")
+	assert.Contains(mock.errors[0], "json.Marshaler")
+}
@@ -0,0 +1,2 @@
+// Package loggertest provides helpers for testing code that uses the logger.
+package loggertest
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/blend/go-sdk/ansi"
+)
+
+// these are compile time assertions
+var (
+	_ Event          = (*MetricEvent)(nil)
+	_ TextWritable   = (*MetricEvent)(nil)
+	_ json.Marshaler = (*MetricEvent)(nil)
+)
+
+// NewMetricEvent returns a new metric event for a named metric, e.g. the current value of a counter.
+func NewMetricEvent(name string, value float64, options ...MetricEventOption) *MetricEvent {
+	me := MetricEvent{
+		EventMeta: NewEventMeta(Metric),
+		Name:      name,
+		Value:     value,
+	}
+	for _, opt := range options {
+		opt(&me)
+	}
+	return &me
+}
+
+// NewMetricEventListener returns a new metric event listener.
+func NewMetricEventListener(listener func(context.Context, *MetricEvent)) Listener {
+	return func(ctx context.Context, e Event) {
+		if typed, isTyped := e.(*MetricEvent); isTyped {
+			listener(ctx, typed)
+		}
+	}
+}
+
+// MetricEventOption is a mutator for metric events.
+type MetricEventOption func(*MetricEvent)
+
+// OptMetricMeta sets meta options.
+func OptMetricMeta(options ...EventMetaOption) MetricEventOption {
+	return func(e *MetricEvent) {
+		for _, opt := range options {
+			opt(e.EventMeta)
+		}
+	}
+}
+
+// OptMetricName sets a field on the event.
+func OptMetricName(value string) MetricEventOption {
+	return func(e *MetricEvent) { e.Name = value }
+}
+
+// OptMetricValue sets a field on the event.
+func OptMetricValue(value float64) MetricEventOption {
+	return func(e *MetricEvent) { e.Value = value }
+}
+
+// MetricEvent is an event that records the value of a named metric.
+type MetricEvent struct {
+	*EventMeta
+	Name  string
+	Value float64
+}
+
+// WriteText implements TextWritable.
+func (e MetricEvent) WriteText(tf TextFormatter, wr io.Writer) {
+	io.WriteString(wr, tf.Colorize(e.Name, ansi.ColorBlue))
+	io.WriteString(wr, Space)
+	io.WriteString(wr, strconv.FormatFloat(e.Value, 'f', -1, 64))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e MetricEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
+		"name":  e.Name,
+		"value": e.Value,
+	}))
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestMetricEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	me := NewMetricEvent("requests", 1,
+		OptMetricName("responses"),
+		OptMetricValue(1234.5),
+	)
+	assert.Equal(Metric, me.GetFlag())
+	assert.Equal("responses", me.Name)
+	assert.Equal(1234.5, me.Value)
+
+	buf := new(bytes.Buffer)
+	noColor := TextOutputFormatter{
+		NoColor: true,
+	}
+	me.WriteText(noColor, buf)
+	assert.Equal("responses 1234.5", buf.String())
+
+	contents, err := json.Marshal(me)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"name":"responses"`)
+	assert.Contains(string(contents), `"value":1234.5`)
+}
+
+func TestMetricEventListener(t *testing.T) {
+	assert := assert.New(t)
+
+	var didCall bool
+	listener := NewMetricEventListener(func(_ context.Context, _ *MetricEvent) {
+		didCall = true
+	})
+	listener(context.Background(), NewMessageEvent(Info, "not a metric"))
+	assert.False(didCall)
+	listener(context.Background(), NewMetricEvent("requests", 1))
+	assert.True(didCall)
+}
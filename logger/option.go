@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"context"
 	"io"
+	"os"
+	"time"
 
 	"github.com/blend/go-sdk/env"
 )
@@ -55,6 +58,47 @@ func OptOutput(output io.Writer) Option {
 	}
 }
 
+// OptErrorOutput sets a separate output writer for error and fatal events, e.g. `os.Stderr`.
+// The events are formatted with the logger formatter unless an error formatter is set.
+func OptErrorOutput(output io.Writer) Option {
+	return func(l *Logger) error {
+		if output != nil {
+			l.ErrorOutput = NewInterlockedWriter(output)
+		} else {
+			l.ErrorOutput = nil
+		}
+		return nil
+	}
+}
+
+// OptStdStreams writes error and fatal events to os.Stderr, and all other events to os.Stdout,
+// as is expected by container runtimes. See `OptStreams`.
+func OptStdStreams(options ...TextOutputFormatterOption) Option {
+	return OptStreams(os.Stdout, os.Stderr, options...)
+}
+
+// OptStreams writes error and fatal events to one output and all other events to another.
+// Each output gets its own text formatter, with color enabled only if the output is a terminal;
+// the formatter options are applied after that, e.g. to hide timestamps.
+func OptStreams(output, errorOutput io.Writer, options ...TextOutputFormatterOption) Option {
+	return func(l *Logger) error {
+		l.Output = NewInterlockedWriter(output)
+		l.ErrorOutput = NewInterlockedWriter(errorOutput)
+		l.Formatter = newStreamTextOutputFormatter(l.Output, options...)
+		l.ErrorFormatter = newStreamTextOutputFormatter(l.ErrorOutput, options...)
+		return nil
+	}
+}
+
+func newStreamTextOutputFormatter(output io.Writer, options ...TextOutputFormatterOption) *TextOutputFormatter {
+	tf := NewTextOutputFormatter()
+	tf.NoColor = !isTerminal(output)
+	for _, option := range options {
+		option(tf)
+	}
+	return tf
+}
+
 // OptHeading sets a logger message heading.
 // It will write through as the first element of the logger context path.
 func OptHeading(heading string) Option {
@@ -82,6 +126,83 @@ func OptText(opts ...TextOutputFormatterOption) Option {
 	return func(l *Logger) error { l.Formatter = NewTextOutputFormatter(opts...); return nil }
 }
 
+// OptLogfmt sets the output formatter for the logger as logfmt.
+func OptLogfmt(opts ...LogfmtOutputFormatterOption) Option {
+	return func(l *Logger) error { l.Formatter = NewLogfmtOutputFormatter(opts...); return nil }
+}
+
+// OptECS sets the output formatter for the logger as Elastic Common Schema json.
+func OptECS(opts ...ECSOutputFormatterOption) Option {
+	return func(l *Logger) error { l.Formatter = NewECSOutputFormatter(opts...); return nil }
+}
+
+// OptSchemaVersion sets the logger to include the given log schema version as a top level `_schema`
+// field on events, so consumers can tell which version of the schema they're parsing.
+// It applies to events that embed `*EventMeta`, which includes all the builtin events.
+func OptSchemaVersion(version string) Option {
+	return func(l *Logger) error {
+		l.SchemaVersion = version
+		return nil
+	}
+}
+
+// OptIncludeCaller sets the logger to record the file and line each event was triggered from.
+// `skip` is the number of additional stack frames to skip, which is useful if events are
+// triggered through helper functions and the helper's caller should be recorded instead.
+// Resolving the caller is relatively expensive, and is only done for enabled events.
+func OptIncludeCaller(skip int) Option {
+	return func(l *Logger) error {
+		l.IncludeCaller = true
+		l.CallerSkip = skip
+		return nil
+	}
+}
+
+// OptListenerQueueDepth sets the number of events that can be queued for each listener.
+func OptListenerQueueDepth(depth int) Option {
+	return func(l *Logger) error { l.ListenerQueueDepth = depth; return nil }
+}
+
+// OptListenerSaturationPolicy sets what happens to events triggered for a listener whose queue is full.
+// Listeners registered before this option is applied are unaffected.
+func OptListenerSaturationPolicy(policy SaturationPolicy) Option {
+	return func(l *Logger) error { l.ListenerSaturationPolicy = policy; return nil }
+}
+
+// OptListenerMaxPanics sets the number of times a listener can panic before it is disabled.
+// Listeners registered before this option is applied are unaffected.
+func OptListenerMaxPanics(maxPanics int) Option {
+	return func(l *Logger) error { l.ListenerMaxPanics = maxPanics; return nil }
+}
+
+// OptListenerTimeout sets how long a listener can take to process an event before it's abandoned with a warning.
+// Listeners registered before this option is applied are unaffected. See `Worker.Timeout` for the ordering implications.
+func OptListenerTimeout(timeout time.Duration) Option {
+	return func(l *Logger) error { l.ListenerTimeout = timeout; return nil }
+}
+
+// OptErrorSuppression suppresses duplicate error events within a given time to live, while counting them.
+// A summary with the count is triggered when the ttl expires or the logger is closed; see `ErrorSuppression`.
+func OptErrorSuppression(ttl time.Duration, options ...ErrorSuppressionOption) Option {
+	return func(l *Logger) error {
+		es := &ErrorSuppression{TTL: ttl}
+		for _, option := range options {
+			option(es)
+		}
+		es.emit = func(ctx context.Context, e *ErrorEvent) {
+			e.Timestamp = l.now()
+			l.trigger(ctx, e, false)
+		}
+		l.ErrorSuppression = es
+		return nil
+	}
+}
+
+// OptClock sets the clock used to stamp the events the logger creates.
+func OptClock(clock Clock) Option {
+	return func(l *Logger) error { l.Clock = clock; return nil }
+}
+
 // OptFormatter sets the output formatter.
 func OptFormatter(formatter WriteFormatter) Option {
 	return func(l *Logger) error { l.Formatter = formatter; return nil }
@@ -2,6 +2,9 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"os"
 	"testing"
 	"time"
 
@@ -56,6 +59,27 @@ func TestOptOutput(t *testing.T) {
 	assert.NotNil(typed.Output)
 }
 
+func TestOptStreams(t *testing.T) {
+	assert := assert.New(t)
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	log := None()
+	assert.Nil(OptStreams(stdout, stderr, OptTextHideTimestamp())(log))
+	assert.True(log.Formatter.(*TextOutputFormatter).NoColor, "buffers aren't terminals")
+	assert.True(log.ErrorFormatter.(*TextOutputFormatter).NoColor)
+
+	log.Write(context.Background(), NewErrorEvent(Error, fmt.Errorf("this is only a test")))
+	log.Write(context.Background(), NewErrorEvent(Fatal, fmt.Errorf("this is also a test")))
+	log.Write(context.Background(), NewMessageEvent(Info, "hello"))
+
+	assert.Equal("[error] this is only a test\n[fatal] this is also a test\n", stderr.String())
+	assert.Equal("[info] hello\n", stdout.String())
+
+	assert.Nil(OptStdStreams()(log))
+	assert.Equal(os.Stdout, log.Output.(*InterlockedWriter).Output)
+	assert.Equal(os.Stderr, log.ErrorOutput.(*InterlockedWriter).Output)
+}
+
 func TestOptions(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewRateAggregator returns a new rate aggregator.
+func NewRateAggregator(options ...RateAggregatorOption) *RateAggregator {
+	ra := &RateAggregator{
+		Window: DefaultRateAggregatorWindow,
+	}
+	for _, option := range options {
+		option(ra)
+	}
+	return ra
+}
+
+// RateAggregatorOption mutates a rate aggregator.
+type RateAggregatorOption func(*RateAggregator)
+
+// OptRateAggregatorWindow sets the time over which rates are computed before the window is reset.
+func OptRateAggregatorWindow(window time.Duration) RateAggregatorOption {
+	return func(ra *RateAggregator) { ra.Window = window }
+}
+
+// RateAggregator aggregates the per second rate of change of counter metric events per metric name.
+// A counter that decreases is assumed to have been reset. It is safe to use from multiple goroutines.
+type RateAggregator struct {
+	sync.Mutex
+
+	Window time.Duration
+
+	metrics map[string]*rateWindow
+}
+
+// Listener returns a listener that adds metric events to the aggregator.
+func (ra *RateAggregator) Listener() Listener {
+	return NewMetricEventListener(func(_ context.Context, e *MetricEvent) {
+		ra.Add(e.Name, e.Value, e.GetTimestamp())
+	})
+}
+
+// Add adds a counter value for a given metric as of a given time.
+func (ra *RateAggregator) Add(name string, value float64, timestamp time.Time) {
+	ra.Lock()
+	defer ra.Unlock()
+
+	if ra.metrics == nil {
+		ra.metrics = make(map[string]*rateWindow)
+	}
+	window, ok := ra.metrics[name]
+	if !ok {
+		ra.metrics[name] = &rateWindow{Start: timestamp, Last: timestamp, Value: value}
+		return
+	}
+	window.add(value, timestamp, ra.Window)
+}
+
+// Snapshot returns the rate of change of each metric over its current window.
+func (ra *RateAggregator) Snapshot() map[string]RateSummary {
+	ra.Lock()
+	defer ra.Unlock()
+
+	output := make(map[string]RateSummary, len(ra.metrics))
+	for name, window := range ra.metrics {
+		output[name] = window.summary()
+	}
+	return output
+}
+
+// Reset removes all aggregated metrics.
+func (ra *RateAggregator) Reset() {
+	ra.Lock()
+	defer ra.Unlock()
+	ra.metrics = nil
+}
+
+// RateSummary is the rate of change of a metric over a window.
+type RateSummary struct {
+	// Value is the last value of the metric.
+	Value float64
+	// Delta is the increase of the metric over the window, excluding counter resets.
+	Delta float64
+	// Elapsed is the time between the first and last values in the window.
+	Elapsed time.Duration
+	// Rate is the increase per second over the window, or zero if the window has a single value.
+	Rate float64
+}
+
+// rateWindow is the aggregated state for a metric.
+type rateWindow struct {
+	Start time.Time
+	Last  time.Time
+	Value float64
+	Delta float64
+}
+
+func (rw *rateWindow) add(value float64, timestamp time.Time, window time.Duration) {
+	// events can be delivered out of order by concurrent listeners; skip stale values.
+	if timestamp.Before(rw.Last) {
+		return
+	}
+	if window > 0 && timestamp.Sub(rw.Start) > window {
+		rw.Start = rw.Last
+		rw.Delta = 0
+	}
+	// a decrease is a counter reset, so the new value is only a baseline.
+	if value >= rw.Value {
+		rw.Delta += value - rw.Value
+	}
+	rw.Value = value
+	rw.Last = timestamp
+}
+
+func (rw *rateWindow) summary() RateSummary {
+	output := RateSummary{
+		Value:   rw.Value,
+		Delta:   rw.Delta,
+		Elapsed: rw.Last.Sub(rw.Start),
+	}
+	if output.Elapsed > 0 {
+		output.Rate = rw.Delta / output.Elapsed.Seconds()
+	}
+	return output
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRateAggregator(t *testing.T) {
+	assert := assert.New(t)
+
+	ra := NewRateAggregator()
+	assert.Equal(DefaultRateAggregatorWindow, ra.Window)
+
+	start := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	ra.Add("requests", 100, start)
+	assert.Zero(ra.Snapshot()["requests"].Rate, "a single value should not have a rate")
+
+	for x := 1; x <= 10; x++ {
+		ra.Add("requests", float64(100+x*20), start.Add(time.Duration(x)*time.Second))
+	}
+	ra.Add("errors", 5, start)
+	ra.Add("errors", 6, start.Add(4*time.Second))
+
+	snapshot := ra.Snapshot()
+	assert.Len(snapshot, 2)
+	summary := snapshot["requests"]
+	assert.Equal(300, summary.Value)
+	assert.Equal(200, summary.Delta)
+	assert.Equal(10*time.Second, summary.Elapsed)
+	assert.Equal(20, summary.Rate)
+	assert.Equal(0.25, snapshot["errors"].Rate)
+
+	ra.Reset()
+	assert.Empty(ra.Snapshot())
+}
+
+func TestRateAggregatorCounterReset(t *testing.T) {
+	assert := assert.New(t)
+
+	ra := NewRateAggregator()
+	start := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	ra.Add("requests", 100, start)
+	ra.Add("requests", 110, start.Add(time.Second))
+	// the counter was reset, e.g. by a restart.
+	ra.Add("requests", 3, start.Add(2*time.Second))
+	ra.Add("requests", 13, start.Add(3*time.Second))
+	ra.Add("requests", 23, start.Add(4*time.Second))
+
+	summary := ra.Snapshot()["requests"]
+	assert.Equal(23, summary.Value)
+	assert.Equal(30, summary.Delta, "the reset should not count as a decrease")
+	assert.Equal(7.5, summary.Rate)
+}
+
+func TestRateAggregatorWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	ra := NewRateAggregator(OptRateAggregatorWindow(10 * time.Second))
+	start := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	for x := 0; x <= 10; x++ {
+		ra.Add("requests", float64(x*10), start.Add(time.Duration(x)*time.Second))
+	}
+	assert.Equal(10, ra.Snapshot()["requests"].Rate)
+
+	// the rate slows down; the new window starts from the last value of the old one.
+	ra.Add("requests", 102, start.Add(11*time.Second))
+	ra.Add("requests", 104, start.Add(12*time.Second))
+	summary := ra.Snapshot()["requests"]
+	assert.Equal(4, summary.Delta)
+	assert.Equal(2*time.Second, summary.Elapsed)
+	assert.Equal(2, summary.Rate)
+}
+
+func TestRateAggregatorListener(t *testing.T) {
+	assert := assert.New(t)
+
+	ra := NewRateAggregator()
+	log := MustNew(OptAll(), OptOutput(nil))
+	defer log.Close()
+	log.Listen(Metric, "rates", ra.Listener())
+
+	start := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	for x := 0; x <= 4; x++ {
+		log.SyncTrigger(context.Background(), NewMetricEvent("requests", float64(x*5), OptMetricMeta(OptEventMetaTimestamp(start.Add(time.Duration(x)*time.Second)))))
+	}
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "not a metric"))
+
+	assert.Equal(5, ra.Snapshot()["requests"].Rate)
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// these are compile time assertions
+var (
+	_ io.Writer = (*RingBufferWriter)(nil)
+)
+
+// NewRingBufferWriter returns a new ring buffer writer that retains the
+// most recent `capacity` lines written to it.
+func NewRingBufferWriter(capacity int) *RingBufferWriter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBufferWriter{
+		lines: make([]string, capacity),
+	}
+}
+
+// RingBufferWriter is a writer that retains the most recent lines written to it in memory.
+// It is safe to use from multiple goroutines.
+type RingBufferWriter struct {
+	sync.Mutex
+
+	lines   []string
+	head    int
+	count   int
+	partial []byte
+}
+
+// Capacity returns the maximum number of lines retained.
+func (rbw *RingBufferWriter) Capacity() int {
+	return len(rbw.lines)
+}
+
+// Len returns the number of lines currently retained.
+func (rbw *RingBufferWriter) Len() int {
+	rbw.Lock()
+	defer rbw.Unlock()
+	return rbw.count
+}
+
+// Write writes the given bytes to the buffer.
+func (rbw *RingBufferWriter) Write(contents []byte) (int, error) {
+	rbw.Lock()
+	defer rbw.Unlock()
+
+	remaining := contents
+	for {
+		index := bytes.IndexByte(remaining, '\n')
+		if index < 0 {
+			break
+		}
+		if len(rbw.partial) > 0 {
+			rbw.push(string(append(rbw.partial, remaining[:index]...)))
+			rbw.partial = nil
+		} else {
+			rbw.push(string(remaining[:index]))
+		}
+		remaining = remaining[index+1:]
+	}
+	if len(remaining) > 0 {
+		rbw.partial = append(rbw.partial, remaining...)
+	}
+	return len(contents), nil
+}
+
+// Dump returns the retained lines, oldest first, without their trailing newlines.
+// A trailing partial line is not included.
+func (rbw *RingBufferWriter) Dump() []string {
+	rbw.Lock()
+	defer rbw.Unlock()
+
+	output := make([]string, rbw.count)
+	start := rbw.head - rbw.count
+	if start < 0 {
+		start += len(rbw.lines)
+	}
+	for index := 0; index < rbw.count; index++ {
+		output[index] = rbw.lines[(start+index)%len(rbw.lines)]
+	}
+	return output
+}
+
+// Clear removes all retained lines.
+func (rbw *RingBufferWriter) Clear() {
+	rbw.Lock()
+	defer rbw.Unlock()
+	for index := range rbw.lines {
+		rbw.lines[index] = ""
+	}
+	rbw.head = 0
+	rbw.count = 0
+	rbw.partial = nil
+}
+
+// push adds a line, overwriting the oldest line if the buffer is full.
+func (rbw *RingBufferWriter) push(line string) {
+	rbw.lines[rbw.head] = line
+	rbw.head = (rbw.head + 1) % len(rbw.lines)
+	if rbw.count < len(rbw.lines) {
+		rbw.count++
+	}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRingBufferWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	rbw := NewRingBufferWriter(3)
+	assert.Equal(3, rbw.Capacity())
+	assert.Empty(rbw.Dump())
+
+	fmt.Fprintln(rbw, "one")
+	fmt.Fprintln(rbw, "two")
+	assert.Equal([]string{"one", "two"}, rbw.Dump())
+
+	fmt.Fprintln(rbw, "three")
+	fmt.Fprintln(rbw, "four")
+	fmt.Fprintln(rbw, "five")
+	assert.Equal(3, rbw.Len())
+	assert.Equal([]string{"three", "four", "five"}, rbw.Dump())
+
+	rbw.Clear()
+	assert.Zero(rbw.Len())
+	assert.Empty(rbw.Dump())
+}
+
+func TestRingBufferWriterPartialLines(t *testing.T) {
+	assert := assert.New(t)
+
+	rbw := NewRingBufferWriter(5)
+	count, err := rbw.Write([]byte("one\ntw"))
+	assert.Nil(err)
+	assert.Equal(6, count)
+	assert.Equal([]string{"one"}, rbw.Dump())
+
+	rbw.Write([]byte("o\nthree\n\nfour"))
+	assert.Equal([]string{"one", "two", "three", ""}, rbw.Dump())
+}
+
+func TestRingBufferWriterLogger(t *testing.T) {
+	assert := assert.New(t)
+
+	rbw := NewRingBufferWriter(2)
+	log := MustNew(OptOutput(rbw), OptText(OptTextHideTimestamp(), OptTextNoColor()))
+	defer log.Close()
+
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "one"))
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "two"))
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "three"))
+	assert.Equal([]string{"[info] two", "[info] three"}, rbw.Dump())
+}
+
+func TestRingBufferWriterConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	rbw := NewRingBufferWriter(10)
+	wg := sync.WaitGroup{}
+	for x := 0; x < 8; x++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for y := 0; y < 100; y++ {
+				fmt.Fprintf(rbw, "%d-%d\n", id, y)
+			}
+		}(x)
+	}
+	wg.Wait()
+	assert.Len(rbw.Dump(), 10)
+}
@@ -0,0 +1,395 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// these are compile time assertions
+var (
+	_ io.WriteCloser = (*RotatingFileWriter)(nil)
+)
+
+const (
+	// ErrRotatingFileWriterCompressedPath is returned if the active file for a rotating file writer is a compressed file.
+	ErrRotatingFileWriterCompressedPath ex.Class = "rotating file writer; cannot append to a compressed file"
+)
+
+// CompressedFileSuffix is the suffix added to rotated files that are compressed.
+const CompressedFileSuffix = ".gz"
+
+// NewRotatingFileWriter returns a new rotating file writer.
+// If `maxFiles` is zero or negative all rotated files are kept.
+func NewRotatingFileWriter(path string, maxBytes int64, maxFiles int, options ...RotatingFileWriterOption) *RotatingFileWriter {
+	rfw := &RotatingFileWriter{
+		Path:     path,
+		MaxBytes: maxBytes,
+		MaxFiles: maxFiles,
+		FileMode: DefaultRotatingFileWriterFileMode,
+		Clock:    SystemClock{},
+	}
+	for _, option := range options {
+		option(rfw)
+	}
+	return rfw
+}
+
+// RotatingFileWriterOption is an option for rotating file writers.
+type RotatingFileWriterOption func(*RotatingFileWriter)
+
+// OptRotatingFileWriterDaily sets the writer to also rotate the file once a day.
+// The day boundary is computed in UTC.
+func OptRotatingFileWriterDaily() RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.RotateDaily = true }
+}
+
+// OptRotatingFileWriterCompress sets the writer to gzip files as they're rotated.
+func OptRotatingFileWriterCompress() RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.Compress = true }
+}
+
+// OptRotatingFileWriterFileMode sets the file mode for created files.
+func OptRotatingFileWriterFileMode(mode os.FileMode) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.FileMode = mode }
+}
+
+// OptRotatingFileWriterClock sets the clock used for daily rotation.
+func OptRotatingFileWriterClock(clock Clock) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.Clock = clock }
+}
+
+// RotatingFileWriter is a file writer that rotates the file it writes to
+// based on the size of the file, and optionally once a day.
+// It is safe to use from multiple goroutines.
+type RotatingFileWriter struct {
+	sync.Mutex
+
+	Path        string
+	MaxBytes    int64
+	MaxFiles    int
+	RotateDaily bool
+	Compress    bool
+	FileMode    os.FileMode
+	Clock       Clock
+	Errors      chan error
+
+	file        *os.File
+	size        int64
+	openedAt    time.Time
+	compressing sync.WaitGroup
+
+	// shiftMu guards renaming rotated files, and the indexes of the files being compressed.
+	shiftMu sync.Mutex
+	pending []*rotatedFile
+}
+
+// rotatedFile is a rotated file being compressed, by its current index,
+// which is incremented as newer files are rotated.
+type rotatedFile struct {
+	index int
+}
+
+// Write writes the given bytes to the file, rotating it first if necessary.
+func (rfw *RotatingFileWriter) Write(contents []byte) (count int, err error) {
+	rfw.Lock()
+	defer rfw.Unlock()
+
+	if err = rfw.ensureOpen(); err != nil {
+		return
+	}
+	if rfw.shouldRotate(len(contents)) {
+		if err = rfw.rotate(); err != nil {
+			return
+		}
+	}
+
+	count, err = rfw.file.Write(contents)
+	rfw.size += int64(count)
+	if err != nil {
+		err = ex.New(err)
+	}
+	return
+}
+
+// Rotate forces a rotation of the file.
+func (rfw *RotatingFileWriter) Rotate() error {
+	rfw.Lock()
+	defer rfw.Unlock()
+	return rfw.rotate()
+}
+
+// Close closes the underlying file.
+// It waits for any in-flight compression of rotated files to finish.
+func (rfw *RotatingFileWriter) Close() error {
+	rfw.Lock()
+	err := rfw.closeFile()
+	rfw.Unlock()
+	rfw.compressing.Wait()
+	return err
+}
+
+// RotatedPath returns the path for a rotated file at a given index.
+func (rfw *RotatingFileWriter) RotatedPath(index int) string {
+	return fmt.Sprintf("%s.%d", rfw.Path, index)
+}
+
+//
+// internal helpers
+//
+
+func (rfw *RotatingFileWriter) now() time.Time {
+	if rfw.Clock != nil {
+		return rfw.Clock.Now()
+	}
+	return time.Now()
+}
+
+// ensureOpen opens the file if it is not open, or re-opens
+// it if it was removed or replaced since it was opened.
+func (rfw *RotatingFileWriter) ensureOpen() error {
+	if rfw.file != nil {
+		current, err := rfw.file.Stat()
+		if err == nil {
+			if onDisk, err := os.Stat(rfw.Path); err == nil && os.SameFile(current, onDisk) {
+				return nil
+			}
+		}
+		if err := rfw.closeFile(); err != nil {
+			return err
+		}
+	}
+	return rfw.openFile()
+}
+
+func (rfw *RotatingFileWriter) openFile() error {
+	if strings.HasSuffix(rfw.Path, CompressedFileSuffix) {
+		return ex.New(ErrRotatingFileWriterCompressedPath, ex.OptMessagef("path: %s", rfw.Path))
+	}
+	file, err := os.OpenFile(rfw.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, rfw.FileMode)
+	if err != nil {
+		return ex.New(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return ex.New(err)
+	}
+	rfw.file = file
+	rfw.size = info.Size()
+	rfw.openedAt = rfw.now()
+	return nil
+}
+
+func (rfw *RotatingFileWriter) closeFile() error {
+	if rfw.file == nil {
+		return nil
+	}
+	err := rfw.file.Close()
+	rfw.file = nil
+	rfw.size = 0
+	if err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+func (rfw *RotatingFileWriter) shouldRotate(length int) bool {
+	if rfw.size == 0 {
+		return false
+	}
+	if rfw.MaxBytes > 0 && rfw.size+int64(length) > rfw.MaxBytes {
+		return true
+	}
+	if rfw.RotateDaily {
+		return !sameDay(rfw.openedAt, rfw.now())
+	}
+	return false
+}
+
+// rotate closes the current file, shifts the existing rotated files,
+// removing the oldest, and opens a new file.
+func (rfw *RotatingFileWriter) rotate() error {
+	if err := rfw.closeFile(); err != nil {
+		return err
+	}
+
+	if err := rfw.shift(); err != nil {
+		return err
+	}
+	return rfw.openFile()
+}
+
+// shift shifts the rotated files up an index, removing the oldest, and rotates the active file to the first index,
+// starting its compression if it's enabled.
+func (rfw *RotatingFileWriter) shift() error {
+	rfw.shiftMu.Lock()
+	defer rfw.shiftMu.Unlock()
+
+	oldest := rfw.MaxFiles
+	if !rfw.keepsAllFiles() {
+		for _, suffix := range []string{"", CompressedFileSuffix} {
+			if err := removeIfExists(rfw.RotatedPath(oldest) + suffix); err != nil {
+				return err
+			}
+		}
+	} else {
+		// shift every rotated file up into the first unused index.
+		oldest = rfw.unusedRotatedIndex()
+	}
+	for _, suffix := range []string{"", CompressedFileSuffix} {
+		for index := oldest - 1; index > 0; index-- {
+			if err := renameIfExists(rfw.RotatedPath(index)+suffix, rfw.RotatedPath(index+1)+suffix); err != nil {
+				return err
+			}
+		}
+	}
+	for _, pending := range rfw.pending {
+		pending.index++
+	}
+	if err := renameIfExists(rfw.Path, rfw.RotatedPath(1)); err != nil {
+		return err
+	}
+	if !rfw.Compress {
+		return nil
+	}
+
+	// the rotated file is read through its descriptor, so it can be shifted while it's compressed.
+	source, err := os.Open(rfw.RotatedPath(1))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return ex.New(err)
+	}
+	rotated := &rotatedFile{index: 1}
+	rfw.pending = append(rfw.pending, rotated)
+	rfw.compressing.Add(1)
+	go func() {
+		defer rfw.compressing.Done()
+		if err := rfw.compress(source, rotated); err != nil {
+			rfw.sendError(err)
+		}
+	}()
+	return nil
+}
+
+// compress gzips a rotated file to a temporary file, then moves it into place at
+// the rotated file's current index, removing the uncompressed file.
+// If the rotated file was shifted out while it was compressed, the compressed file is discarded.
+func (rfw *RotatingFileWriter) compress(source *os.File, rotated *rotatedFile) error {
+	tempPath, err := compressFile(source, rfw.Path, rfw.FileMode)
+
+	rfw.shiftMu.Lock()
+	defer rfw.shiftMu.Unlock()
+	for index, pending := range rfw.pending {
+		if pending == rotated {
+			rfw.pending = append(rfw.pending[:index], rfw.pending[index+1:]...)
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !rfw.keepsAllFiles() && rotated.index > rfw.MaxFiles {
+		return removeIfExists(tempPath)
+	}
+	path := rfw.RotatedPath(rotated.index)
+	if err = os.Rename(tempPath, path+CompressedFileSuffix); err != nil {
+		removeIfExists(tempPath)
+		return ex.New(err)
+	}
+	return removeIfExists(path)
+}
+
+// keepsAllFiles returns if rotated files are never removed, i.e. if `MaxFiles` is zero or negative.
+func (rfw *RotatingFileWriter) keepsAllFiles() bool {
+	return rfw.MaxFiles <= 0
+}
+
+// unusedRotatedIndex returns the lowest index without a rotated file, compressed or not.
+func (rfw *RotatingFileWriter) unusedRotatedIndex() int {
+	index := 1
+	for fileExists(rfw.RotatedPath(index)) || fileExists(rfw.RotatedPath(index)+CompressedFileSuffix) {
+		index++
+	}
+	return index
+}
+
+// sendError sends an error to the errors channel if it's set, without blocking.
+func (rfw *RotatingFileWriter) sendError(err error) {
+	if rfw.Errors == nil {
+		return
+	}
+	select {
+	case rfw.Errors <- err:
+	default:
+	}
+}
+
+// compressFile gzips a file to a new temporary file next to a given path, closing the source,
+// and returns the path of the temporary file.
+func compressFile(source *os.File, path string, mode os.FileMode) (string, error) {
+	defer source.Close()
+
+	destination, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*"+CompressedFileSuffix+".tmp")
+	if err != nil {
+		return "", ex.New(err)
+	}
+	tempPath := destination.Name()
+
+	gz := gzip.NewWriter(destination)
+	if _, err = io.Copy(gz, source); err != nil {
+		destination.Close()
+		os.Remove(tempPath)
+		return "", ex.New(err)
+	}
+	if err = gz.Close(); err != nil {
+		destination.Close()
+		os.Remove(tempPath)
+		return "", ex.New(err)
+	}
+	if err = destination.Chmod(mode); err != nil {
+		destination.Close()
+		os.Remove(tempPath)
+		return "", ex.New(err)
+	}
+	if err = destination.Close(); err != nil {
+		os.Remove(tempPath)
+		return "", ex.New(err)
+	}
+	return tempPath, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return ex.New(err)
+	}
+	return nil
+}
+
+func renameIfExists(from, to string) error {
+	if err := os.Rename(from, to); err != nil && !os.IsNotExist(err) {
+		return ex.New(err)
+	}
+	return nil
+}
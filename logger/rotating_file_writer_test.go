@@ -0,0 +1,237 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestRotatingFileWriterSize(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rfw := NewRotatingFileWriter(path, 10, 2)
+	defer rfw.Close()
+
+	_, err = rfw.Write([]byte("01234567\n"))
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("abcdefgh\n"))
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("ABCDEFGH\n"))
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("zyxwvuts\n"))
+	assert.Nil(err)
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("zyxwvuts\n", string(contents))
+
+	contents, err = ioutil.ReadFile(rfw.RotatedPath(1))
+	assert.Nil(err)
+	assert.Equal("ABCDEFGH\n", string(contents))
+
+	contents, err = ioutil.ReadFile(rfw.RotatedPath(2))
+	assert.Nil(err)
+	assert.Equal("abcdefgh\n", string(contents))
+
+	_, err = os.Stat(rfw.RotatedPath(3))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestRotatingFileWriterKeepAll(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rfw := NewRotatingFileWriter(path, 10, 0)
+	defer rfw.Close()
+
+	for _, line := range []string{"01234567\n", "abcdefgh\n", "ABCDEFGH\n", "zyxwvuts\n"} {
+		_, err = rfw.Write([]byte(line))
+		assert.Nil(err)
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("zyxwvuts\n", string(contents), "the active file should be kept")
+
+	for index, expected := range []string{"ABCDEFGH\n", "abcdefgh\n", "01234567\n"} {
+		contents, err = ioutil.ReadFile(rfw.RotatedPath(index + 1))
+		assert.Nil(err)
+		assert.Equal(expected, string(contents))
+	}
+	_, err = os.Stat(rfw.RotatedPath(4))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestRotatingFileWriterAppends(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	assert.Nil(ioutil.WriteFile(path, []byte("existing\n"), 0644))
+
+	rfw := NewRotatingFileWriter(path, 1024, 2)
+	_, err = rfw.Write([]byte("new\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Close())
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("existing\nnew\n", string(contents))
+}
+
+func TestRotatingFileWriterReopensRemoved(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rfw := NewRotatingFileWriter(path, 1024, 2)
+	defer rfw.Close()
+
+	_, err = rfw.Write([]byte("first\n"))
+	assert.Nil(err)
+	assert.Nil(os.Remove(path))
+
+	_, err = rfw.Write([]byte("second\n"))
+	assert.Nil(err)
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("second\n", string(contents))
+}
+
+func TestRotatingFileWriterDaily(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	now := time.Date(2019, 01, 02, 23, 59, 00, 00, time.UTC)
+	clock := ClockFunc(func() time.Time { return now })
+
+	path := filepath.Join(tempDir, "app.log")
+	rfw := NewRotatingFileWriter(path, 0, 1, OptRotatingFileWriterDaily(), OptRotatingFileWriterClock(clock))
+	defer rfw.Close()
+
+	_, err = rfw.Write([]byte("yesterday\n"))
+	assert.Nil(err)
+	now = now.Add(2 * time.Minute)
+	_, err = rfw.Write([]byte("today\n"))
+	assert.Nil(err)
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("today\n", string(contents))
+
+	contents, err = ioutil.ReadFile(rfw.RotatedPath(1))
+	assert.Nil(err)
+	assert.Equal("yesterday\n", string(contents))
+}
+
+func TestRotatingFileWriterCompress(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rfw := NewRotatingFileWriter(path, 10, 2, OptRotatingFileWriterCompress())
+
+	_, err = rfw.Write([]byte("01234567\n"))
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("abcdefgh\n"))
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("ABCDEFGH\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Close())
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("ABCDEFGH\n", string(contents))
+
+	_, err = os.Stat(rfw.RotatedPath(1))
+	assert.True(os.IsNotExist(err), "the uncompressed rotated file should be removed")
+
+	for index, expected := range []string{"abcdefgh\n", "01234567\n"} {
+		compressed, err := os.Open(rfw.RotatedPath(index+1) + CompressedFileSuffix)
+		assert.Nil(err)
+		gz, err := gzip.NewReader(compressed)
+		assert.Nil(err)
+		contents, err = ioutil.ReadAll(gz)
+		assert.Nil(err)
+		assert.Equal(expected, string(contents))
+		compressed.Close()
+	}
+}
+
+func TestRotatingFileWriterCompressedPath(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	rfw := NewRotatingFileWriter(filepath.Join(tempDir, "app.log.gz"), 10, 2, OptRotatingFileWriterCompress())
+	_, err = rfw.Write([]byte("foo\n"))
+	assert.True(ex.Is(err, ErrRotatingFileWriterCompressedPath))
+}
+
+func TestRotatingFileWriterCompressRotatesWhileCompressing(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file_writer")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rfw := NewRotatingFileWriter(path, 10, 2, OptRotatingFileWriterCompress())
+	rfw.Errors = make(chan error)
+
+	for x := 0; x < 100; x++ {
+		_, err = rfw.Write([]byte("01234567\n"))
+		assert.Nil(err)
+	}
+	assert.Nil(rfw.Close())
+
+	files, err := ioutil.ReadDir(tempDir)
+	assert.Nil(err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	assert.Equal([]string{"app.log", "app.log.1.gz", "app.log.2.gz"}, names)
+}
+
+func TestRotatingFileWriterSendErrorDoesNotBlock(t *testing.T) {
+	assert := assert.New(t)
+
+	rfw := NewRotatingFileWriter("app.log", 10, 2)
+	rfw.sendError(ex.New("no errors channel"))
+
+	rfw.Errors = make(chan error, 1)
+	rfw.sendError(ex.New("first"))
+	rfw.sendError(ex.New("dropped"))
+	assert.Equal("first", ex.ErrClass(<-rfw.Errors).Error())
+}
@@ -40,6 +40,15 @@ func NewRPCEventListener(listener func(context.Context, *RPCEvent)) Listener {
 // RPCEventOption is a mutator for RPCEvents.
 type RPCEventOption func(*RPCEvent)
 
+// OptRPCMeta sets meta options.
+func OptRPCMeta(options ...EventMetaOption) RPCEventOption {
+	return func(e *RPCEvent) {
+		for _, opt := range options {
+			opt(e.EventMeta)
+		}
+	}
+}
+
 // OptRPCEngine sets a field on the event.
 func OptRPCEngine(value string) RPCEventOption {
 	return func(e *RPCEvent) { e.Engine = value }
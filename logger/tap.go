@@ -0,0 +1,35 @@
+package logger
+
+import "context"
+
+// Tap registers a function that is called synchronously with each event triggered for a flag,
+// and returns a function that removes it.
+func (l *Logger) Tap(flag string, tap func(Event)) (untap func()) {
+	return l.TapContext(flag, func(_ context.Context, e Event) { tap(e) })
+}
+
+// TapContext registers a function that is called synchronously with the context and each event
+// triggered for a flag, and returns a function that removes it. See `Tap`.
+func (l *Logger) TapContext(flag string, tap func(context.Context, Event)) (untap func()) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.taps == nil {
+		l.taps = make(map[string]map[uint64]func(context.Context, Event))
+	}
+	if l.taps[flag] == nil {
+		l.taps[flag] = make(map[uint64]func(context.Context, Event))
+	}
+	l.nextTap++
+	id := l.nextTap
+	l.taps[flag][id] = tap
+
+	return func() {
+		l.Lock()
+		defer l.Unlock()
+		delete(l.taps[flag], id)
+		if len(l.taps[flag]) == 0 {
+			delete(l.taps, flag)
+		}
+	}
+}
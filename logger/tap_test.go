@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLoggerTap(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptAll(), OptOutput(nil))
+	defer log.Close()
+
+	var logged []Event
+	untap := log.Tap(Error, func(e Event) { logged = append(logged, e) })
+
+	var l Log = log
+	l.Infof("not an error")
+	l.Error(fmt.Errorf("this is an error"))
+	assert.Len(logged, 1, "the tap should only observe events for its flag")
+	typed, ok := logged[0].(*ErrorEvent)
+	assert.True(ok)
+	assert.Equal("this is an error", typed.Err.Error())
+
+	untap()
+	l.Error(fmt.Errorf("this is another error"))
+	assert.Len(logged, 1, "the tap shouldn't observe events after untap")
+	assert.Empty(log.taps)
+
+	untap()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -62,12 +63,31 @@ func OptTextNoColor() TextOutputFormatterOption {
 	return func(tf *TextOutputFormatter) { tf.NoColor = true }
 }
 
+// OptTextShowLabels shows event labels in output.
+func OptTextShowLabels() TextOutputFormatterOption {
+	return func(tf *TextOutputFormatter) { tf.ShowLabels = true }
+}
+
+// OptTextAlignLabels shows event labels in output, with the label keys
+// padded to a common width when the output is a terminal.
+// Alignment is skipped for outputs that are not terminals, e.g. files or pipes.
+func OptTextAlignLabels(width int) TextOutputFormatterOption {
+	return func(tf *TextOutputFormatter) {
+		tf.ShowLabels = true
+		tf.AlignLabels = true
+		tf.LabelWidth = width
+	}
+}
+
 // TextOutputFormatter handles formatting messages as text.
 type TextOutputFormatter struct {
 	HideTimestamp bool
 	HideFields    bool
 	NoColor       bool
 	TimeFormat    string
+	ShowLabels    bool
+	AlignLabels   bool
+	LabelWidth    int
 
 	BufferPool *bufferutil.Pool
 }
@@ -95,7 +115,7 @@ func (tf TextOutputFormatter) FormatFlag(flag string, color ansi.Color) string {
 
 // FormatTimestamp returns a new timestamp string.
 func (tf TextOutputFormatter) FormatTimestamp(ts time.Time) string {
-	value := ts.Format(tf.TimeFormatOrDefault())
+	value := ts.UTC().Format(tf.TimeFormatOrDefault())
 	return tf.Colorize(fmt.Sprintf("%-30s", value), ansi.ColorLightBlack)
 }
 
@@ -120,6 +140,11 @@ func (tf TextOutputFormatter) FormatFields(fields Fields) string {
 	return FormatFields(tf, ansi.ColorBlue, fields)
 }
 
+// FormatLabels returns the labels section of the message as a string.
+func (tf TextOutputFormatter) FormatLabels(labels Labels) string {
+	return FormatLabels(tf, ansi.ColorBlue, labels)
+}
+
 // WriteFormat implements write formatter.
 func (tf TextOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
 	buffer := tf.BufferPool.Get()
@@ -139,6 +164,13 @@ func (tf TextOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 	buffer.WriteString(tf.FormatFlag(e.GetFlag(), FlagTextColor(e.GetFlag())))
 	buffer.WriteString(Space)
 
+	if typed, ok := e.(interface{ GetCaller() Caller }); ok {
+		if caller := typed.GetCaller(); !caller.IsZero() {
+			buffer.WriteString(tf.Colorize(caller.Short(), ansi.ColorLightBlack))
+			buffer.WriteString(Space)
+		}
+	}
+
 	if typed, ok := e.(TextWritable); ok {
 		typed.WriteText(tf, buffer)
 	} else if stringer, ok := e.(fmt.Stringer); ok {
@@ -150,7 +182,37 @@ func (tf TextOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 		buffer.WriteString(tf.FormatFields(subContextFields))
 	}
 
+	if tf.ShowLabels {
+		if typed, ok := e.(interface{ GetLabels() Labels }); ok {
+			if labels := typed.GetLabels(); len(labels) > 0 {
+				buffer.WriteString("\t")
+				if tf.AlignLabels && isTerminal(output) {
+					buffer.WriteString(FormatLabelsAligned(tf, labels, tf.LabelWidth))
+				} else {
+					buffer.WriteString(tf.FormatLabels(labels))
+				}
+			}
+		}
+	}
+
 	buffer.WriteString(Newline)
 	_, err := io.Copy(output, buffer)
 	return err
 }
+
+// isTerminal returns if a given output is a terminal (character device),
+// looking through interlocked writers.
+func isTerminal(output io.Writer) bool {
+	if typed, ok := output.(*InterlockedWriter); ok {
+		return isTerminal(typed.Output)
+	}
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -101,3 +103,29 @@ func TestTextOutputFormatterFormatFields(t *testing.T) {
 	expected := fmt.Sprintf("%s=%s %s=%s", ansi.ColorBlue.Apply("buzz"), "fuzz", ansi.ColorBlue.Apply("foo"), "bar")
 	assert.Equal(expected, actual)
 }
+
+func TestTextOutputFormatterLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewMessageEvent(Info, "foo")
+	e.Labels = Labels{"service": "api", "env": "prod"}
+
+	buf := new(bytes.Buffer)
+	tf := NewTextOutputFormatter(OptTextNoColor(), OptTextHideTimestamp())
+	assert.Nil(tf.WriteFormat(context.Background(), buf, e))
+	assert.Equal("[info] foo\n", buf.String())
+
+	buf.Reset()
+	tf = NewTextOutputFormatter(OptTextNoColor(), OptTextHideTimestamp(), OptTextShowLabels())
+	assert.Nil(tf.WriteFormat(context.Background(), buf, e))
+	assert.Equal("[info] foo\tenv=prod service=api\n", buf.String())
+
+	// buffers are not terminals, so alignment is skipped
+	buf.Reset()
+	tf = NewTextOutputFormatter(OptTextNoColor(), OptTextHideTimestamp(), OptTextAlignLabels(10))
+	assert.True(tf.ShowLabels)
+	assert.True(tf.AlignLabels)
+	assert.Equal(10, tf.LabelWidth)
+	assert.Nil(tf.WriteFormat(context.Background(), NewInterlockedWriter(buf), e))
+	assert.Equal("[info] foo\tenv=prod service=api\n", buf.String())
+}
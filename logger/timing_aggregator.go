@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewTimingAggregator returns a new timing aggregator.
+func NewTimingAggregator(options ...TimingAggregatorOption) *TimingAggregator {
+	ta := &TimingAggregator{
+		ReservoirSize: DefaultTimingAggregatorReservoirSize,
+		Quantiles:     DefaultTimingAggregatorQuantiles,
+		random:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, option := range options {
+		option(ta)
+	}
+	return ta
+}
+
+// TimingAggregatorOption mutates a timing aggregator.
+type TimingAggregatorOption func(*TimingAggregator)
+
+// OptTimingAggregatorReservoirSize sets the maximum number of samples retained per operation.
+func OptTimingAggregatorReservoirSize(size int) TimingAggregatorOption {
+	return func(ta *TimingAggregator) { ta.ReservoirSize = size }
+}
+
+// OptTimingAggregatorQuantiles sets the quantiles computed for snapshots, e.g. `0.5, 0.95, 0.99`.
+func OptTimingAggregatorQuantiles(quantiles ...float64) TimingAggregatorOption {
+	return func(ta *TimingAggregator) { ta.Quantiles = quantiles }
+}
+
+// TimingAggregator aggregates the elapsed times of timing events per operation.
+// Quantiles are estimated from a sample of at most `ReservoirSize` elapsed times per operation.
+type TimingAggregator struct {
+	sync.Mutex
+
+	ReservoirSize int
+	Quantiles     []float64
+
+	operations map[string]*timingHistogram
+	random     *rand.Rand
+}
+
+// Listener returns a listener that adds timing events to the aggregator.
+func (ta *TimingAggregator) Listener() Listener {
+	return NewTimingEventListener(func(_ context.Context, e *TimingEvent) {
+		ta.Add(e.Operation, e.Elapsed)
+	})
+}
+
+// Add adds an elapsed time for a given operation.
+func (ta *TimingAggregator) Add(operation string, elapsed time.Duration) {
+	ta.Lock()
+	defer ta.Unlock()
+
+	if ta.operations == nil {
+		ta.operations = make(map[string]*timingHistogram)
+	}
+	histogram, ok := ta.operations[operation]
+	if !ok {
+		histogram = &timingHistogram{Min: elapsed, Max: elapsed}
+		ta.operations[operation] = histogram
+	}
+	histogram.add(elapsed, ta.ReservoirSize, ta.random)
+}
+
+// Snapshot returns a summary of the elapsed times for each operation.
+func (ta *TimingAggregator) Snapshot() map[string]TimingSummary {
+	ta.Lock()
+	defer ta.Unlock()
+
+	output := make(map[string]TimingSummary, len(ta.operations))
+	for operation, histogram := range ta.operations {
+		output[operation] = histogram.summary(ta.Quantiles)
+	}
+	return output
+}
+
+// Reset removes all aggregated timings.
+func (ta *TimingAggregator) Reset() {
+	ta.Lock()
+	defer ta.Unlock()
+	ta.operations = nil
+}
+
+// TimingSummary is a summary of the elapsed times for an operation.
+type TimingSummary struct {
+	Count     int64
+	Min       time.Duration
+	Max       time.Duration
+	Mean      time.Duration
+	Quantiles map[float64]time.Duration
+}
+
+// Quantile returns a computed quantile, or zero if it was not computed.
+func (ts TimingSummary) Quantile(quantile float64) time.Duration {
+	return ts.Quantiles[quantile]
+}
+
+// timingHistogram is the aggregated state for an operation.
+type timingHistogram struct {
+	Count     int64
+	Min       time.Duration
+	Max       time.Duration
+	Sum       time.Duration
+	Reservoir []time.Duration
+}
+
+// add adds a sample, keeping a uniform random sample of at most `size` values (i.e. "algorithm r").
+func (th *timingHistogram) add(elapsed time.Duration, size int, random *rand.Rand) {
+	th.Count++
+	th.Sum += elapsed
+	if elapsed < th.Min {
+		th.Min = elapsed
+	}
+	if elapsed > th.Max {
+		th.Max = elapsed
+	}
+	if size <= 0 {
+		return
+	}
+	if len(th.Reservoir) < size {
+		th.Reservoir = append(th.Reservoir, elapsed)
+		return
+	}
+	if index := random.Int63n(th.Count); index < int64(size) {
+		th.Reservoir[index] = elapsed
+	}
+}
+
+func (th *timingHistogram) summary(quantiles []float64) TimingSummary {
+	output := TimingSummary{
+		Count:     th.Count,
+		Min:       th.Min,
+		Max:       th.Max,
+		Quantiles: make(map[float64]time.Duration, len(quantiles)),
+	}
+	if th.Count > 0 {
+		output.Mean = th.Sum / time.Duration(th.Count)
+	}
+	if len(th.Reservoir) == 0 {
+		return output
+	}
+
+	sorted := make([]time.Duration, len(th.Reservoir))
+	copy(sorted, th.Reservoir)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for _, quantile := range quantiles {
+		output.Quantiles[quantile] = nearestRank(sorted, quantile)
+	}
+	return output
+}
+
+// nearestRank returns the value at a given quantile of a sorted set of values.
+func nearestRank(sorted []time.Duration, quantile float64) time.Duration {
+	if quantile <= 0 {
+		return sorted[0]
+	}
+	if quantile >= 1 {
+		return sorted[len(sorted)-1]
+	}
+	rank := int(quantile*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
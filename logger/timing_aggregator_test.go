@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestTimingAggregatorExact(t *testing.T) {
+	assert := assert.New(t)
+
+	ta := NewTimingAggregator()
+	assert.Equal(DefaultTimingAggregatorReservoirSize, ta.ReservoirSize)
+	assert.Equal(DefaultTimingAggregatorQuantiles, ta.Quantiles)
+
+	for x := 100; x > 0; x-- {
+		ta.Add("fetch-user", time.Duration(x)*time.Millisecond)
+	}
+	ta.Add("fetch-account", time.Second)
+
+	snapshot := ta.Snapshot()
+	assert.Len(snapshot, 2)
+
+	summary := snapshot["fetch-user"]
+	assert.Equal(100, summary.Count)
+	assert.Equal(time.Millisecond, summary.Min)
+	assert.Equal(100*time.Millisecond, summary.Max)
+	assert.Equal(50500*time.Microsecond, summary.Mean)
+	assert.Equal(50*time.Millisecond, summary.Quantile(0.5))
+	assert.Equal(95*time.Millisecond, summary.Quantile(0.95))
+	assert.Equal(99*time.Millisecond, summary.Quantile(0.99))
+	assert.Zero(summary.Quantile(0.75))
+
+	summary = snapshot["fetch-account"]
+	assert.Equal(1, summary.Count)
+	assert.Equal(time.Second, summary.Quantile(0.5))
+
+	ta.Reset()
+	assert.Empty(ta.Snapshot())
+}
+
+func TestTimingAggregatorApproximate(t *testing.T) {
+	assert := assert.New(t)
+
+	ta := NewTimingAggregator(OptTimingAggregatorReservoirSize(512), OptTimingAggregatorQuantiles(0.5, 0.95, 0.99))
+	ta.random = rand.New(rand.NewSource(1))
+
+	// a uniform distribution of 1ms to 10s, in a shuffled order.
+	values := rand.New(rand.NewSource(2)).Perm(10000)
+	for _, value := range values {
+		ta.Add("fetch-user", time.Duration(value+1)*time.Millisecond)
+	}
+
+	summary := ta.Snapshot()["fetch-user"]
+	assert.Equal(10000, summary.Count)
+	assert.Len(ta.operations["fetch-user"].Reservoir, 512, "the reservoir should be bounded")
+	assert.Equal(time.Millisecond, summary.Min)
+	assert.Equal(10*time.Second, summary.Max)
+
+	assertWithin := func(expected, actual time.Duration) {
+		delta := expected - actual
+		if delta < 0 {
+			delta = -delta
+		}
+		assert.True(delta < 500*time.Millisecond, fmt.Sprintf("expected %v to be within 500ms of %v", actual, expected))
+	}
+	assertWithin(5*time.Second, summary.Quantile(0.5))
+	assertWithin(9500*time.Millisecond, summary.Quantile(0.95))
+	assertWithin(9900*time.Millisecond, summary.Quantile(0.99))
+}
+
+func TestTimingAggregatorListener(t *testing.T) {
+	assert := assert.New(t)
+
+	ta := NewTimingAggregator()
+	log := MustNew(OptAll(), OptOutput(nil))
+	defer log.Close()
+	log.Listen(Timing, "aggregator", ta.Listener())
+
+	wg := sync.WaitGroup{}
+	for x := 0; x < 4; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for y := 0; y < 25; y++ {
+				log.SyncTrigger(context.Background(), NewTimingEvent("fetch-user", time.Millisecond))
+			}
+		}()
+	}
+	wg.Wait()
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "not timing"))
+
+	assert.Equal(100, ta.Snapshot()["fetch-user"].Count)
+}
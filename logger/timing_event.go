@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/blend/go-sdk/ansi"
+	"github.com/blend/go-sdk/timeutil"
+)
+
+// these are compile time assertions
+var (
+	_ Event = (*TimingEvent)(nil)
+)
+
+// NewTimingEvent returns a new timing event for a named operation.
+func NewTimingEvent(operation string, elapsed time.Duration, options ...TimingEventOption) *TimingEvent {
+	te := TimingEvent{
+		EventMeta: NewEventMeta(Timing),
+		Operation: operation,
+		Elapsed:   elapsed,
+	}
+	for _, opt := range options {
+		opt(&te)
+	}
+	return &te
+}
+
+// NewTimingEventListener returns a new timing event listener.
+func NewTimingEventListener(listener func(context.Context, *TimingEvent)) Listener {
+	return func(ctx context.Context, e Event) {
+		if typed, isTyped := e.(*TimingEvent); isTyped {
+			listener(ctx, typed)
+		}
+	}
+}
+
+// TimingEventOption is a mutator for timing events.
+type TimingEventOption func(*TimingEvent)
+
+// OptTimingMeta sets meta options.
+func OptTimingMeta(options ...EventMetaOption) TimingEventOption {
+	return func(e *TimingEvent) {
+		for _, opt := range options {
+			opt(e.EventMeta)
+		}
+	}
+}
+
+// OptTimingOperation sets a field on the event.
+func OptTimingOperation(value string) TimingEventOption {
+	return func(e *TimingEvent) { e.Operation = value }
+}
+
+// OptTimingElapsed sets a field on the event.
+func OptTimingElapsed(value time.Duration) TimingEventOption {
+	return func(e *TimingEvent) { e.Elapsed = value }
+}
+
+// TimingEvent is an event that records how long a named operation took.
+type TimingEvent struct {
+	*EventMeta
+	Operation string
+	Elapsed   time.Duration
+}
+
+// WriteText implements TextWritable.
+func (e TimingEvent) WriteText(tf TextFormatter, wr io.Writer) {
+	io.WriteString(wr, tf.Colorize(e.Operation, ansi.ColorBlue))
+	io.WriteString(wr, Space)
+	io.WriteString(wr, e.Elapsed.String())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e TimingEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
+		"operation": e.Operation,
+		"elapsed":   timeutil.Milliseconds(e.Elapsed),
+	}))
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestTimingEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	te := NewTimingEvent("fetch-user", time.Second,
+		OptTimingOperation("fetch-account"),
+		OptTimingElapsed(time.Millisecond),
+	)
+	assert.Equal(Timing, te.GetFlag())
+	assert.Equal("fetch-account", te.Operation)
+	assert.Equal(time.Millisecond, te.Elapsed)
+
+	buf := new(bytes.Buffer)
+	noColor := TextOutputFormatter{
+		NoColor: true,
+	}
+	te.WriteText(noColor, buf)
+	assert.Equal("fetch-account 1ms", buf.String())
+
+	contents, err := json.Marshal(te)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"operation":"fetch-account"`)
+	assert.Contains(string(contents), `"elapsed":1`)
+}
+
+func TestTimingEventListener(t *testing.T) {
+	assert := assert.New(t)
+
+	var didCall bool
+	listener := NewTimingEventListener(func(_ context.Context, _ *TimingEvent) {
+		didCall = true
+	})
+	listener(context.Background(), NewMessageEvent(Info, "not timing"))
+	assert.False(didCall)
+	listener(context.Background(), NewTimingEvent("fetch-user", time.Second))
+	assert.True(didCall)
+}
@@ -2,26 +2,126 @@ package logger
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/blend/go-sdk/async"
 	"github.com/blend/go-sdk/ex"
 )
 
+// ErrListenerTimeout is the class of errors for listener invocations abandoned because they exceeded the worker timeout.
+const ErrListenerTimeout ex.Class = "worker; listener timed out"
+
 // NewWorker returns a new worker.
-func NewWorker(listener Listener) *Worker {
-	return &Worker{
+func NewWorker(listener Listener, options ...WorkerOption) *Worker {
+	w := &Worker{
 		Latch:    async.NewLatch(),
 		Listener: listener,
-		Work:     make(chan EventWithContext, DefaultWorkerQueueDepth),
 	}
+	for _, option := range options {
+		option(w)
+	}
+	if w.Work == nil {
+		w.Work = make(chan EventWithContext, DefaultWorkerQueueDepth)
+	}
+	return w
+}
+
+// WorkerOption mutates a worker.
+type WorkerOption func(*Worker)
+
+// OptWorkerQueueDepth sets the number of events that can be queued for the worker.
+func OptWorkerQueueDepth(depth int) WorkerOption {
+	return func(w *Worker) { w.Work = make(chan EventWithContext, depth) }
+}
+
+// OptWorkerSaturationPolicy sets what happens to events enqueued when the worker's queue is full.
+func OptWorkerSaturationPolicy(policy SaturationPolicy) WorkerOption {
+	return func(w *Worker) { w.SaturationPolicy = policy }
+}
+
+// OptWorkerPanicHandler sets a handler called with the recovered error when the listener panics.
+func OptWorkerPanicHandler(handler func(error)) WorkerOption {
+	return func(w *Worker) { w.PanicHandler = handler }
+}
+
+// OptWorkerMaxPanics sets the number of times the listener can panic before the worker disables it.
+// Disabled workers skip any further events; zero means the listener is never disabled.
+func OptWorkerMaxPanics(maxPanics int) WorkerOption {
+	return func(w *Worker) { w.MaxPanics = maxPanics }
+}
+
+// OptWorkerTimeout sets how long the listener can take to process an event before it's abandoned.
+// See `Worker.Timeout` for the ordering implications.
+func OptWorkerTimeout(timeout time.Duration) WorkerOption {
+	return func(w *Worker) { w.Timeout = timeout }
+}
+
+// OptWorkerMaxAbandoned sets the number of timed out listener invocations that can still be running before the worker skips events.
+// See `Worker.MaxAbandoned`.
+func OptWorkerMaxAbandoned(maxAbandoned int) WorkerOption {
+	return func(w *Worker) { w.MaxAbandoned = maxAbandoned }
+}
+
+// OptWorkerTimeoutHandler sets a handler called with an `ErrListenerTimeout` error when a listener invocation is abandoned.
+func OptWorkerTimeoutHandler(handler func(error)) WorkerOption {
+	return func(w *Worker) { w.TimeoutHandler = handler }
 }
 
 // Worker is an agent that processes a listener.
 type Worker struct {
 	*async.Latch
-	Errors   chan error
-	Listener Listener
-	Work     chan EventWithContext
+	Errors           chan error
+	Listener         Listener
+	Work             chan EventWithContext
+	SaturationPolicy SaturationPolicy
+	PanicHandler     func(error)
+	MaxPanics        int
+	// Timeout, if positive, is how long the listener can take to process an event.
+	// The listener is called on its own goroutine with a context that has the timeout as its deadline,
+	// and if it doesn't return in time it's abandoned (but not stopped) so the worker can move on.
+	// This means an abandoned invocation can still be running while the next event is processed,
+	// so events are no longer guaranteed to be processed in order, and the listener must be safe to call concurrently.
+	Timeout        time.Duration
+	TimeoutHandler func(error)
+	// MaxAbandoned is how many abandoned invocations can still be running before the worker skips events,
+	// counting them as dropped, so a hung listener can't leak goroutines without limit.
+	// If unset, `DefaultWorkerMaxAbandoned` is used.
+	MaxAbandoned int
+
+	dropped   int64
+	panics    int64
+	disabled  int32
+	abandoned int64
+	skipping  int32
+}
+
+// Enqueue queues an event to be processed by the worker.
+// If the queue is full, it either blocks until there is space or drops the event
+// based on the saturation policy; it returns false if the event was dropped.
+func (w *Worker) Enqueue(ec EventWithContext) bool {
+	if w.SaturationPolicy == SaturationPolicyDrop {
+		select {
+		case w.Work <- ec:
+			return true
+		default:
+			atomic.AddInt64(&w.dropped, 1)
+			return false
+		}
+	}
+	// a stopped worker will never drain its queue, so don't block on it.
+	select {
+	case w.Work <- ec:
+		return true
+	case <-w.NotifyStopped():
+		return false
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full, or skipped because
+// too many abandoned invocations were still running (see `MaxAbandoned`).
+func (w *Worker) Dropped() int64 {
+	return atomic.LoadInt64(&w.dropped)
 }
 
 // Start starts the worker.
@@ -90,11 +190,101 @@ func (w *Worker) Dispatch() {
 	}
 }
 
-// Process calls the listener for an event.
+// Panics returns the number of times the listener has panicked.
+func (w *Worker) Panics() int64 {
+	return atomic.LoadInt64(&w.panics)
+}
+
+// Disabled returns if the listener was disabled because it panicked `MaxPanics` times.
+func (w *Worker) Disabled() bool {
+	return atomic.LoadInt32(&w.disabled) == 1
+}
+
+// Abandoned returns the number of abandoned listener invocations that are still running.
+func (w *Worker) Abandoned() int64 {
+	return atomic.LoadInt64(&w.abandoned)
+}
+
+// MaxAbandonedOrDefault returns the max abandoned invocations or a default.
+func (w *Worker) MaxAbandonedOrDefault() int {
+	if w.MaxAbandoned > 0 {
+		return w.MaxAbandoned
+	}
+	return DefaultWorkerMaxAbandoned
+}
+
+// Listener invocation states, used to decide if a timed out invocation was abandoned.
+const (
+	invocationRunning int32 = iota
+	invocationReturned
+	invocationAbandoned
+)
+
+// Process calls the listener for an event, recovering panics and abandoning it after the `Timeout`.
 func (w *Worker) Process(ec EventWithContext) (err error) {
+	if w.Disabled() {
+		return nil
+	}
+	if w.Timeout <= 0 {
+		return w.process(ec)
+	}
+	if atomic.LoadInt64(&w.abandoned) >= int64(w.MaxAbandonedOrDefault()) {
+		atomic.AddInt64(&w.dropped, 1)
+		if atomic.CompareAndSwapInt32(&w.skipping, 0, 1) && w.TimeoutHandler != nil {
+			w.TimeoutHandler(ex.New(ErrListenerTimeout, ex.OptMessagef("%d abandoned invocations are still running; skipping events until one returns", w.MaxAbandonedOrDefault())))
+		}
+		return nil
+	}
+
+	parent := ec.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, w.Timeout)
+	done := make(chan error, 1)
+	state := invocationRunning
+	go func() {
+		defer cancel()
+		done <- w.process(EventWithContext{ctx, ec.Event})
+		if !atomic.CompareAndSwapInt32(&state, invocationRunning, invocationReturned) {
+			atomic.AddInt64(&w.abandoned, -1)
+			atomic.StoreInt32(&w.skipping, 0)
+		}
+	}()
+
+	// the timeout is checked with its own timer, as the event context may already be cancelled, e.g. for a finished request.
+	timeout := time.NewTimer(w.Timeout)
+	defer timeout.Stop()
+	select {
+	case err = <-done:
+		return
+	case <-timeout.C:
+		// the abandoned count is incremented before the invocation is marked abandoned, so it can't be decremented first.
+		atomic.AddInt64(&w.abandoned, 1)
+		if !atomic.CompareAndSwapInt32(&state, invocationRunning, invocationAbandoned) {
+			// the listener returned just as the timer fired.
+			atomic.AddInt64(&w.abandoned, -1)
+			return <-done
+		}
+		err = ex.New(ErrListenerTimeout, ex.OptMessagef("timeout: %v", w.Timeout))
+		if w.TimeoutHandler != nil {
+			w.TimeoutHandler(err)
+		}
+		return
+	}
+}
+
+// process calls the listener for an event, recovering panics.
+func (w *Worker) process(ec EventWithContext) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = ex.New(r)
+			if panics := atomic.AddInt64(&w.panics, 1); w.MaxPanics > 0 && panics >= int64(w.MaxPanics) {
+				atomic.StoreInt32(&w.disabled, 1)
+			}
+			if w.PanicHandler != nil {
+				w.PanicHandler(err)
+			}
 			return
 		}
 	}()
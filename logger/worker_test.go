@@ -3,10 +3,12 @@ package logger
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
 )
 
 func TestWorker(t *testing.T) {
@@ -62,3 +64,56 @@ func TestWorkerDrain(t *testing.T) {
 
 	assert.True(didFire)
 }
+
+func TestWorkerTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	var timeouts int
+	w := NewWorker(func(_ context.Context, _ Event) {
+		time.Sleep(500 * time.Millisecond)
+	}, OptWorkerTimeout(time.Millisecond), OptWorkerTimeoutHandler(func(_ error) { timeouts++ }))
+	err := w.Process(EventWithContext{context.Background(), NewMessageEvent(Info, "test")})
+	assert.True(ex.Is(err, ErrListenerTimeout))
+	assert.Equal(1, timeouts)
+
+	// listeners that finish in time, or panic, are unaffected.
+	w = NewWorker(func(_ context.Context, _ Event) {}, OptWorkerTimeout(time.Second))
+	assert.Nil(w.Process(EventWithContext{context.Background(), NewMessageEvent(Info, "test")}))
+	w = NewWorker(func(_ context.Context, _ Event) { panic("only a test") }, OptWorkerTimeout(time.Second))
+	assert.NotNil(w.Process(EventWithContext{context.Background(), NewMessageEvent(Info, "test")}))
+	assert.Equal(1, w.Panics())
+}
+
+func TestWorkerMaxAbandoned(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	var timeouts int32
+	w := NewWorker(func(_ context.Context, _ Event) {
+		<-release
+	},
+		OptWorkerTimeout(time.Millisecond),
+		OptWorkerMaxAbandoned(2),
+		OptWorkerTimeoutHandler(func(_ error) { atomic.AddInt32(&timeouts, 1) }),
+	)
+	ec := EventWithContext{context.Background(), NewMessageEvent(Info, "test")}
+
+	assert.True(ex.Is(w.Process(ec), ErrListenerTimeout))
+	assert.True(ex.Is(w.Process(ec), ErrListenerTimeout))
+	assert.Equal(2, w.Abandoned())
+
+	// once the max is reached events are skipped, and the handler is only called once.
+	assert.Nil(w.Process(ec))
+	assert.Nil(w.Process(ec))
+	assert.Equal(2, w.Abandoned(), "skipped events shouldn't start invocations")
+	assert.Equal(2, w.Dropped())
+	assert.Equal(3, atomic.LoadInt32(&timeouts))
+
+	close(release)
+	for w.Abandoned() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	w.Listener = func(_ context.Context, _ Event) {}
+	assert.Nil(w.Process(ec))
+	assert.Equal(2, w.Dropped())
+}
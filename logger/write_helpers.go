@@ -15,7 +15,12 @@ import (
 
 // WriteHTTPRequest is a helper method to write request start events to a writer.
 func WriteHTTPRequest(tf TextFormatter, wr io.Writer, req *http.Request) {
-	if ip := webutil.GetRemoteAddr(req); len(ip) > 0 {
+	writeHTTPRequest(tf, wr, webutil.GetRemoteAddr(req), req)
+}
+
+// writeHTTPRequest writes a request start event with a given client ip.
+func writeHTTPRequest(tf TextFormatter, wr io.Writer, ip string, req *http.Request) {
+	if len(ip) > 0 {
 		io.WriteString(wr, ip)
 		io.WriteString(wr, Space)
 	}
@@ -28,7 +33,12 @@ func WriteHTTPRequest(tf TextFormatter, wr io.Writer, req *http.Request) {
 
 // WriteHTTPResponse is a helper method to write request complete events to a writer.
 func WriteHTTPResponse(tf TextFormatter, wr io.Writer, req *http.Request, statusCode, contentLength int, contentType string, elapsed time.Duration) {
-	if ip := webutil.GetRemoteAddr(req); len(ip) > 0 {
+	writeHTTPResponse(tf, wr, webutil.GetRemoteAddr(req), req, statusCode, contentLength, contentType, elapsed)
+}
+
+// writeHTTPResponse writes a request complete event with a given client ip.
+func writeHTTPResponse(tf TextFormatter, wr io.Writer, ip string, req *http.Request, statusCode, contentLength int, contentType string, elapsed time.Duration) {
+	if len(ip) > 0 {
 		io.WriteString(wr, ip)
 		io.WriteString(wr, Space)
 	}
@@ -79,6 +89,44 @@ func FormatFields(tf TextFormatter, keyColor ansi.Color, fields Fields) string {
 	return strings.Join(values, " ")
 }
 
+// FormatLabels formats the output of labels.
+// Label keys will be printed in alphabetic order.
+func FormatLabels(tf TextFormatter, keyColor ansi.Color, labels Labels) string {
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var values []string
+	for _, key := range keys {
+		values = append(values, fmt.Sprintf("%s=%s", tf.Colorize(key, keyColor), labels[key]))
+	}
+	return strings.Join(values, " ")
+}
+
+// FormatLabelsAligned formats the output of labels with each key padded to a common width
+// so that labels line up in columns across log lines.
+// Label keys will be printed in alphabetic order.
+// If `width` is less than the length of the longest key, the longest key length is used.
+func FormatLabelsAligned(tf TextFormatter, labels Labels, width int) string {
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+	sort.Strings(keys)
+
+	var values []string
+	for _, key := range keys {
+		// pad before colorizing so the escape codes don't count towards the width.
+		values = append(values, fmt.Sprintf("%s=%s", tf.Colorize(fmt.Sprintf("%-*s", width, key), ansi.ColorBlue), labels[key]))
+	}
+	return strings.Join(values, " ")
+}
+
 // MergeDecomposed merges sets of decomposed data.
 func MergeDecomposed(sets ...map[string]interface{}) map[string]interface{} {
 	output := make(map[string]interface{})
@@ -66,3 +66,30 @@ func TestFormatHeaders(t *testing.T) {
 	actual = FormatHeaders(tf, ansi.ColorBlue, http.Header{"Foo": []string{"bar"}, "Moo": []string{"loo"}})
 	assert.Equal("{ "+ansi.ColorBlue.Apply("Foo")+":bar "+ansi.ColorBlue.Apply("Moo")+":loo }", actual)
 }
+
+func TestFormatLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	tf := NewTextOutputFormatter(OptTextNoColor())
+	actual := FormatLabels(tf, ansi.ColorBlue, Labels{"moo": "loo", "foo": "bar"})
+	assert.Equal("foo=bar moo=loo", actual)
+
+	tf = NewTextOutputFormatter()
+	actual = FormatLabels(tf, ansi.ColorBlue, Labels{"foo": "bar", "moo": "loo"})
+	assert.Equal(ansi.ColorBlue.Apply("foo")+"=bar "+ansi.ColorBlue.Apply("moo")+"=loo", actual)
+}
+
+func TestFormatLabelsAligned(t *testing.T) {
+	assert := assert.New(t)
+
+	tf := NewTextOutputFormatter(OptTextNoColor())
+	actual := FormatLabelsAligned(tf, Labels{"service": "api", "env": "prod"}, 0)
+	assert.Equal("env    =prod service=api", actual)
+
+	actual = FormatLabelsAligned(tf, Labels{"service": "api", "env": "prod"}, 10)
+	assert.Equal("env       =prod service   =api", actual)
+
+	tf = NewTextOutputFormatter()
+	actual = FormatLabelsAligned(tf, Labels{"env": "prod"}, 5)
+	assert.Equal(ansi.ColorBlue.Apply("env  ")+"=prod", actual)
+}
@@ -56,72 +56,26 @@ type Manager struct {
 // OAuthURL is the auth url for google with a given clientID.
 // This is typically the link that a user will click on to start the auth process.
 func (m *Manager) OAuthURL(r *http.Request, stateOptions ...StateOption) (oauthURL string, err error) {
-	var state string
-	state, err = SerializeState(m.CreateState(stateOptions...))
-	if err != nil {
-		return
-	}
+	return m.oauthURL(r, nil, stateOptions...)
+}
 
-	var opts []oauth2.AuthCodeOption
-	if len(m.HostedDomain) > 0 {
-		opts = append(opts, oauth2.SetAuthURLParam("hd", m.HostedDomain))
-	}
-	oauthURL = m.conf(r).AuthCodeURL(state, opts...)
-	return
+// OAuthURLPKCE is the auth url with a PKCE code challenge for a given code verifier.
+// The same code verifier must be passed to `FinishPKCE`; it is typically created with `NewPKCEVerifier`.
+func (m *Manager) OAuthURLPKCE(r *http.Request, codeVerifier string, stateOptions ...StateOption) (oauthURL string, err error) {
+	return m.oauthURL(r, []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", PKCEChallenge(codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", PKCEChallengeMethod),
+	}, stateOptions...)
 }
 
 // Finish processes the returned code, exchanging for an access token, and fetches the user profile.
 func (m *Manager) Finish(r *http.Request) (result *Result, err error) {
-	if m.Tracer != nil {
-		tf := m.Tracer.Start(r.Context(), m.conf(r))
-		if tf != nil {
-			defer func() { tf.Finish(r.Context(), m.conf(r), result, err) }()
-		}
-	}
-
-	// grab the code off the request.
-	code := r.URL.Query().Get("code")
-	if len(code) == 0 {
-		err = ErrCodeMissing
-		return
-	}
-
-	// fetch the state
-	state := r.URL.Query().Get("state")
-	result = &Result{}
-	if len(state) > 0 {
-		var deserialized State
-		deserialized, err = DeserializeState(state)
-		if err != nil {
-			return
-		}
-		result.State = deserialized
-	}
-
-	err = m.ValidateState(result.State)
-	if err != nil {
-		return
-	}
-
-	// Handle the exchange code to initiate a transport.
-	tok, err := m.conf(r).Exchange(r.Context(), code)
-	if err != nil {
-		err = ex.New(ErrFailedCodeExchange, ex.OptInner(err))
-		return
-	}
-
-	result.Response.AccessToken = tok.AccessToken
-	result.Response.TokenType = tok.TokenType
-	result.Response.RefreshToken = tok.RefreshToken
-	result.Response.Expiry = tok.Expiry
+	return m.finish(r)
+}
 
-	var prof Profile
-	prof, err = m.FetchProfile(r.Context(), tok.AccessToken)
-	if err != nil {
-		return
-	}
-	result.Profile = prof
-	return
+// FinishPKCE processes the returned code like `Finish`, sending the PKCE code verifier with the exchange.
+func (m *Manager) FinishPKCE(r *http.Request, codeVerifier string) (result *Result, err error) {
+	return m.finish(r, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
 }
 
 // FetchProfile gets a google profile for an access token.
@@ -153,8 +107,10 @@ func (m *Manager) CreateState(options ...StateOption) (state State) {
 	for _, opt := range options {
 		opt(&state)
 	}
-	if len(m.Secret) > 0 && state.Token == "" && state.SecureToken == "" {
-		state.Token = uuid.V4().String()
+	if len(m.Secret) > 0 && state.SecureToken == "" {
+		if state.Token == "" {
+			state.Token = uuid.V4().String()
+		}
 		state.SecureToken = m.hash(state.Token)
 	}
 	return
@@ -196,6 +152,73 @@ func (m *Manager) ValidateProfile(p *Profile) error {
 // internal helpers
 // --------------------------------------------------------------------------------
 
+func (m *Manager) oauthURL(r *http.Request, opts []oauth2.AuthCodeOption, stateOptions ...StateOption) (oauthURL string, err error) {
+	var state string
+	state, err = SerializeState(m.CreateState(stateOptions...))
+	if err != nil {
+		return
+	}
+
+	if len(m.HostedDomain) > 0 {
+		opts = append(opts, oauth2.SetAuthURLParam("hd", m.HostedDomain))
+	}
+	oauthURL = m.conf(r).AuthCodeURL(state, opts...)
+	return
+}
+
+func (m *Manager) finish(r *http.Request, exchangeOptions ...oauth2.AuthCodeOption) (result *Result, err error) {
+	if m.Tracer != nil {
+		tf := m.Tracer.Start(r.Context(), m.conf(r))
+		if tf != nil {
+			defer func() { tf.Finish(r.Context(), m.conf(r), result, err) }()
+		}
+	}
+
+	// grab the code off the request.
+	code := r.URL.Query().Get("code")
+	if len(code) == 0 {
+		err = ErrCodeMissing
+		return
+	}
+
+	// fetch the state
+	state := r.URL.Query().Get("state")
+	result = &Result{}
+	if len(state) > 0 {
+		var deserialized State
+		deserialized, err = DeserializeState(state)
+		if err != nil {
+			return
+		}
+		result.State = deserialized
+	}
+
+	err = m.ValidateState(result.State)
+	if err != nil {
+		return
+	}
+
+	// Handle the exchange code to initiate a transport.
+	tok, err := m.conf(r).Exchange(r.Context(), code, exchangeOptions...)
+	if err != nil {
+		err = ex.New(ErrFailedCodeExchange, ex.OptInner(err))
+		return
+	}
+
+	result.Response.AccessToken = tok.AccessToken
+	result.Response.TokenType = tok.TokenType
+	result.Response.RefreshToken = tok.RefreshToken
+	result.Response.Expiry = tok.Expiry
+
+	var prof Profile
+	prof, err = m.FetchProfile(r.Context(), tok.AccessToken)
+	if err != nil {
+		return
+	}
+	result.Profile = prof
+	return
+}
+
 func (m *Manager) conf(r *http.Request) *oauth2.Config {
 	return &oauth2.Config{
 		ClientID:     m.ClientID,
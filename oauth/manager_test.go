@@ -171,6 +171,26 @@ func TestManagerOAuthURLRedirect(t *testing.T) {
 	assert.Equal("bar_foo", deserialized.RedirectURI)
 }
 
+func TestManagerOAuthURLPKCE(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := New()
+	assert.Nil(err)
+	m.ClientID = "test_client_id"
+	m.RedirectURI = "https://local.shortcut-service.centrio.com/oauth/google"
+
+	verifier, err := NewPKCEVerifier()
+	assert.Nil(err)
+	urlFragment, err := m.OAuthURLPKCE(nil, verifier)
+	assert.Nil(err)
+
+	u, err := url.Parse(urlFragment)
+	assert.Nil(err)
+	assert.Equal(PKCEChallenge(verifier), u.Query().Get("code_challenge"))
+	assert.Equal(PKCEChallengeMethod, u.Query().Get("code_challenge_method"))
+	assert.Empty(u.Query().Get("code_verifier"))
+}
+
 func TestManagerValidateProfile(t *testing.T) {
 	assert := assert.New(t)
 
@@ -217,6 +237,11 @@ func TestManagerValidateState(t *testing.T) {
 	secure := MustNew()
 	secure.Secret = crypto.MustCreateKey(32)
 	assert.Nil(secure.ValidateState(secure.CreateState()))
+
+	withToken := secure.CreateState(OptStateToken("test_token"))
+	assert.Equal("test_token", withToken.Token)
+	assert.NotEmpty(withToken.SecureToken)
+	assert.Nil(secure.ValidateState(withToken))
 }
 
 func TestManagerRequestDefaulkts(t *testing.T) {
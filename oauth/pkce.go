@@ -0,0 +1,31 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// PKCEChallengeMethod is the PKCE code challenge method used by `OAuthURLPKCE`.
+	PKCEChallengeMethod = "S256"
+	// PKCEVerifierBytes is the number of random bytes in a code verifier.
+	PKCEVerifierBytes = 32
+)
+
+// NewPKCEVerifier returns a new random PKCE (RFC 7636) code verifier.
+func NewPKCEVerifier() (string, error) {
+	verifier := make([]byte, PKCEVerifierBytes)
+	if _, err := rand.Read(verifier); err != nil {
+		return "", ex.New(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(verifier), nil
+}
+
+// PKCEChallenge returns the S256 code challenge for a code verifier.
+func PKCEChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,25 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("oYhHRnJq5p3bEZ0eOmtmtmtCtGh0O5ZlEwoz_-xUNtk", PKCEChallenge("dBjftJeZ4CVP-mB92K1uVzT2-3ypV3R1wXQx7Ywm4Ik"))
+}
+
+func TestNewPKCEVerifier(t *testing.T) {
+	assert := assert.New(t)
+
+	verifier, err := NewPKCEVerifier()
+	assert.Nil(err)
+	assert.Len(verifier, 43)
+
+	other, err := NewPKCEVerifier()
+	assert.Nil(err)
+	assert.NotEqual(verifier, other)
+}
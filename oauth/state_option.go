@@ -3,6 +3,13 @@ package oauth
 // StateOption is an option for state objects
 type StateOption func(*State)
 
+// OptStateToken sets the plaintext token on the state.
+func OptStateToken(token string) StateOption {
+	return func(s *State) {
+		s.Token = token
+	}
+}
+
 // OptStateSecureToken sets the secure token on the state.
 func OptStateSecureToken(secureToken string) StateOption {
 	return func(s *State) {
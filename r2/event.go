@@ -48,7 +48,7 @@ type Event struct {
 // WriteText writes the event to a text writer.
 func (e *Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 	if e.Request != nil && e.Response != nil {
-		io.WriteString(wr, fmt.Sprintf("%s %s %s (%v)", e.Request.Method, e.Request.URL.String(), logger.ColorizeStatusCodeWithFormatter(tf, e.Response.StatusCode), e.GetTimestamp().Sub(e.Started)))
+		io.WriteString(wr, fmt.Sprintf("%s %s %s (%v)", e.Request.Method, e.Request.URL.String(), logger.ColorizeStatusCodeWithFormatter(tf, e.Response.StatusCode), logger.ElapsedBetween(e.Started, e.GetTimestamp())))
 	} else if e.Request != nil {
 		io.WriteString(wr, fmt.Sprintf("%s %s", e.Request.Method, e.Request.URL.String()))
 	}
@@ -67,7 +67,7 @@ func (e *Event) MarshalJSON() ([]byte, error) {
 			url = e.Request.URL.String()
 		}
 		output["req"] = map[string]interface{}{
-			"startTime": e.Started,
+			"startTime": e.Started.UTC(),
 			"method":    e.Request.Method,
 			"url":       url,
 			"headers":   e.Request.Header,
@@ -75,7 +75,7 @@ func (e *Event) MarshalJSON() ([]byte, error) {
 	}
 	if e.Response != nil {
 		output["res"] = map[string]interface{}{
-			"completeTime":    e.GetTimestamp(),
+			"completeTime":    e.GetTimestamp().UTC(),
 			"statusCode":      e.Response.StatusCode,
 			"contentLength":   e.Response.ContentLength,
 			"contentType":     tryHeader(e.Response.Header, "Content-Type", "content-type"),
@@ -0,0 +1,15 @@
+package r2
+
+import (
+	"context"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// OptTraceContext propagates the trace context and request id of a context onto the request headers,
+// i.e. `traceparent`, `tracestate` and `X-Request-Id`. It is a no-op if the context has neither.
+// The contexts of inbound requests handled with the `web.TraceContextAware` middleware have both.
+// See `webutil.OptTraceContext`.
+func OptTraceContext(ctx context.Context) Option {
+	return RequestOption(webutil.OptTraceContext(ctx))
+}
@@ -0,0 +1,32 @@
+package r2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestOptTraceContext(t *testing.T) {
+	assert := assert.New(t)
+
+	tc := webutil.TraceContext{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Flags:   webutil.TraceFlagSampled,
+		State:   "congo=t61rcWkgMzE",
+	}
+	ctx := webutil.WithRequestID(webutil.WithTraceContext(context.Background(), tc), "request-1234")
+
+	req := New("https://foo.bar.local", OptTraceContext(ctx))
+	assert.Nil(req.Err)
+	assert.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", req.Header.Get("traceparent"))
+	assert.Equal("congo=t61rcWkgMzE", req.Header.Get("tracestate"))
+	assert.Equal("request-1234", req.Header.Get("X-Request-Id"))
+
+	req = New("https://foo.bar.local", OptTraceContext(context.Background()))
+	assert.Nil(req.Err)
+	assert.Empty(req.Header.Get("traceparent"))
+	assert.Empty(req.Header.Get("X-Request-Id"))
+}
@@ -70,7 +70,7 @@ func (r Request) Do() (*http.Response, error) {
 	}
 
 	var err error
-	started := time.Now().UTC()
+	started := time.Now()
 
 	var finisher TraceFinisher
 	if r.Tracer != nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/blend/go-sdk/r2"
 	"github.com/blend/go-sdk/stats/tracing"
+	"github.com/blend/go-sdk/webutil"
 	opentracing "github.com/opentracing/opentracing-go"
 )
 
@@ -39,6 +40,13 @@ func (rt r2Tracer) Start(req *http.Request) r2.TraceFinisher {
 		req.Header = make(http.Header)
 	}
 	rt.tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	// the traceparent set by `r2.OptTraceContext` is replaced so the request is a child of its own span.
+	if tc, ok := tracing.TraceContextFromSpan(span); ok {
+		req.Header.Set(webutil.HeaderTraceParent, tc.TraceParent())
+		if tc.State != "" {
+			req.Header.Set(webutil.HeaderTraceState, tc.State)
+		}
+	}
 	return r2TraceFinisher{span: span}
 }
 
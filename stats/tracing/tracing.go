@@ -3,8 +3,10 @@ package tracing
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
 	opentracing "github.com/opentracing/opentracing-go"
 )
 
@@ -38,3 +40,52 @@ func SpanError(span opentracing.Span, err error) {
 		}
 	}
 }
+
+// TraceContextFromSpan returns the W3C trace context of a span, so it can be propagated in the `traceparent`
+// header by `r2.OptTraceContext`. It returns false if the tracer doesn't expose the trace and span ids.
+// The ids are read from the `traceparent` the tracer injects, if it does, or from 64 bit ids, e.g. datadog's.
+func TraceContextFromSpan(span opentracing.Span) (webutil.TraceContext, bool) {
+	if span == nil {
+		return webutil.TraceContext{}, false
+	}
+	carrier := opentracing.TextMapCarrier{}
+	if err := span.Tracer().Inject(span.Context(), opentracing.TextMap, carrier); err == nil {
+		var traceParent, traceState string
+		for key, value := range carrier {
+			if strings.EqualFold(key, webutil.HeaderTraceParent) {
+				traceParent = value
+			} else if strings.EqualFold(key, webutil.HeaderTraceState) {
+				traceState = value
+			}
+		}
+		if tc, err := webutil.ParseTraceContext(traceParent, traceState); err == nil {
+			return tc, true
+		}
+	}
+	ids, ok := span.Context().(spanContextIDs)
+	if !ok {
+		return webutil.TraceContext{}, false
+	}
+	tc := webutil.TraceContext{
+		TraceID: fmt.Sprintf("%032x", ids.TraceID()),
+		SpanID:  fmt.Sprintf("%016x", ids.SpanID()),
+		Flags:   webutil.TraceFlagSampled,
+	}
+	if sampling, ok := span.Context().(spanContextSamplingPriority); ok {
+		if priority, ok := sampling.SamplingPriority(); ok && priority <= 0 {
+			tc.Flags = 0
+		}
+	}
+	return tc, tc.IsValid()
+}
+
+// spanContextIDs is a span context with 64 bit trace and span ids.
+type spanContextIDs interface {
+	TraceID() uint64
+	SpanID() uint64
+}
+
+// spanContextSamplingPriority is a span context with a sampling priority, where zero or less means it's dropped.
+type spanContextSamplingPriority interface {
+	SamplingPriority() (int, bool)
+}
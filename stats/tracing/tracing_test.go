@@ -117,3 +117,45 @@ func TestSpanError(t *testing.T) {
 	assert.NotNil(mockSpan.Tags()[TagKeyErrorStack])
 	assert.Contains(mockSpan.Tags()[TagKeyErrorStack].(string), "tracing_test.go")
 }
+
+type idsTestSpanContext struct {
+	traceID, spanID uint64
+	priority        int
+}
+
+func (sc idsTestSpanContext) ForeachBaggageItem(_ func(k, v string) bool) {}
+func (sc idsTestSpanContext) TraceID() uint64                             { return sc.traceID }
+func (sc idsTestSpanContext) SpanID() uint64                              { return sc.spanID }
+func (sc idsTestSpanContext) SamplingPriority() (int, bool)               { return sc.priority, true }
+
+type idsTestSpan struct {
+	*mocktracer.MockSpan
+	spanContext idsTestSpanContext
+}
+
+func (s idsTestSpan) Context() opentracing.SpanContext { return s.spanContext }
+
+func TestTraceContextFromSpan(t *testing.T) {
+	assert := assert.New(t)
+	mockTracer := mocktracer.New()
+
+	_, ok := TraceContextFromSpan(nil)
+	assert.False(ok)
+	_, ok = TraceContextFromSpan(mockTracer.StartSpan("test.operation"))
+	assert.False(ok, "the mock tracer doesn't expose its ids")
+
+	span := idsTestSpan{
+		MockSpan:    mockTracer.StartSpan("test.operation").(*mocktracer.MockSpan),
+		spanContext: idsTestSpanContext{traceID: 1234, spanID: 5678, priority: 1},
+	}
+	tc, ok := TraceContextFromSpan(span)
+	assert.True(ok)
+	assert.Equal("000000000000000000000000000004d2", tc.TraceID)
+	assert.Equal("000000000000162e", tc.SpanID)
+	assert.True(tc.Sampled())
+
+	span.spanContext.priority = 0
+	tc, ok = TraceContextFromSpan(span)
+	assert.True(ok)
+	assert.False(tc.Sampled())
+}
@@ -45,7 +45,7 @@ func (wt webTracer) Start(ctx *web.Ctx) web.TraceFinisher {
 		opentracing.Tag{Key: tracing.TagKeySpanType, Value: tracing.SpanTypeWeb},
 		opentracing.Tag{Key: tracing.TagKeyHTTPMethod, Value: ctx.Request.Method},
 		opentracing.Tag{Key: tracing.TagKeyHTTPURL, Value: ctx.Request.URL.Path},
-		opentracing.Tag{Key: "http.remote_addr", Value: webutil.GetRemoteAddr(ctx.Request)},
+		opentracing.Tag{Key: "http.remote_addr", Value: ctx.RemoteAddr()},
 		opentracing.Tag{Key: "http.host", Value: webutil.GetHost(ctx.Request)},
 		opentracing.Tag{Key: "http.user_agent", Value: webutil.GetUserAgent(ctx.Request)},
 		opentracing.StartTime(ctx.RequestStart),
@@ -63,6 +63,10 @@ func (wt webTracer) Start(ctx *web.Ctx) web.TraceFinisher {
 	}
 	// start the span.
 	span, spanCtx := tracing.StartSpanFromContext(ctx.Context(), wt.tracer, tracing.OperationHTTPRequest, startOptions...)
+	// outbound requests made with `r2.OptTraceContext` are children of the span.
+	if tc, ok := tracing.TraceContextFromSpan(span); ok {
+		spanCtx = webutil.WithTraceContext(spanCtx, tc)
+	}
 	// inject the new context
 	ctx.Request = ctx.Request.WithContext(spanCtx)
 	ctx.WithContext(spanCtx)
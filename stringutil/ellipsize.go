@@ -0,0 +1,20 @@
+package stringutil
+
+// Ellipsis is the character used to mark elided text.
+const Ellipsis = "…"
+
+// EllipsizeMiddle shortens a string to at most `max` runes by keeping the
+// start and end of the string and replacing the middle with an ellipsis.
+func EllipsizeMiddle(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 0 {
+		return ""
+	}
+	budget := max - 1
+	tail := budget / 2
+	head := budget - tail
+	return string(runes[:head]) + Ellipsis + string(runes[len(runes)-tail:])
+}
@@ -0,0 +1,32 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestEllipsizeMiddle(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Input    string
+		Max      int
+		Expected string
+	}{
+		{Input: "", Max: 5, Expected: ""},
+		{Input: "abc", Max: 5, Expected: "abc"},
+		{Input: "abcde", Max: 5, Expected: "abcde"},
+		{Input: "abcdef", Max: 0, Expected: ""},
+		{Input: "abcdef", Max: 1, Expected: "…"},
+		{Input: "abcdef", Max: 2, Expected: "a…"},
+		{Input: "abcdefghijklmnopqrstuvwxyz", Max: 11, Expected: "abcde…vwxyz"},
+		{Input: "abcdefghijklmnopqrstuvwxyz", Max: 10, Expected: "abcde…wxyz"},
+		{Input: "da39a3ee5e6b4b0d3255bfef95601890afd80709", Max: 13, Expected: "da39a3…d80709"},
+		{Input: "日本語のテキストです", Max: 5, Expected: "日本…です"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Expected, EllipsizeMiddle(tc.Input, tc.Max), tc.Input)
+	}
+}
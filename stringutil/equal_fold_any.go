@@ -0,0 +1,30 @@
+package stringutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EqualFoldAny returns if a string is equal to any of the candidates under unicode case folding.
+// It uses the same semantics as `strings.EqualFold`.
+func EqualFoldAny(s string, candidates ...string) bool {
+	for _, candidate := range candidates {
+		if strings.EqualFold(s, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalFoldRune returns if two runes are equal under simple unicode case folding.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
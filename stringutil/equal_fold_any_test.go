@@ -0,0 +1,21 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestEqualFoldAny(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(EqualFoldAny("application/JSON", "text/html", "application/json"))
+	assert.True(EqualFoldAny("ΣΑΣ", "σας"))
+	assert.True(EqualFoldAny("Kelvin", "kelvin"), "the kelvin sign folds to k")
+
+	assert.False(EqualFoldAny("foo"))
+	assert.False(EqualFoldAny("foo", "bar", "fooo"))
+	// turkish dotted and dotless i do not fold to the ascii i.
+	assert.False(EqualFoldAny("ı", "I", "i"))
+	assert.False(EqualFoldAny("İ", "i", "I"))
+}
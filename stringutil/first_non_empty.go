@@ -0,0 +1,28 @@
+package stringutil
+
+// FirstNonEmpty returns the first non-empty value, or "" if they're all empty.
+func FirstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Coalesce is an alias to `FirstNonEmpty`.
+func Coalesce(values ...string) string {
+	return FirstNonEmpty(values...)
+}
+
+// CoalescePtr returns the first non-nil value, or nil if they're all nil.
+// Unlike `Coalesce`, a pointer to "" is returned if it's set, which lets
+// config merging tell an empty value apart from an unset one.
+func CoalescePtr(values ...*string) *string {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
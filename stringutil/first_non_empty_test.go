@@ -0,0 +1,30 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestFirstNonEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(FirstNonEmpty())
+	assert.Empty(FirstNonEmpty("", "", ""))
+	assert.Equal("foo", FirstNonEmpty("foo", "bar"))
+	assert.Equal("bar", FirstNonEmpty("", "bar", "", "baz"))
+	assert.Equal(" ", FirstNonEmpty("", " "), "whitespace isn't empty")
+
+	assert.Equal("bar", Coalesce("", "bar"))
+	assert.Empty(Coalesce("", ""))
+}
+
+func TestCoalescePtr(t *testing.T) {
+	assert := assert.New(t)
+
+	empty, bar := "", "bar"
+	assert.Nil(CoalescePtr())
+	assert.Nil(CoalescePtr(nil, nil))
+	assert.Equal(&bar, CoalescePtr(nil, &bar))
+	assert.Equal(&empty, CoalescePtr(nil, &empty, &bar), "set but empty values should be returned")
+}
@@ -0,0 +1,21 @@
+package stringutil
+
+import "unicode/utf8"
+
+// HasPrefixFold returns if a string has a prefix under unicode case folding.
+// It uses the same semantics as `strings.EqualFold`, and as a result the matched
+// portion of `s` may have a different byte length than `prefix`.
+func HasPrefixFold(s, prefix string) bool {
+	for prefix != "" {
+		if s == "" {
+			return false
+		}
+		sr, sSize := utf8.DecodeRuneInString(s)
+		pr, pSize := utf8.DecodeRuneInString(prefix)
+		if !equalFoldRune(sr, pr) {
+			return false
+		}
+		s, prefix = s[sSize:], prefix[pSize:]
+	}
+	return true
+}
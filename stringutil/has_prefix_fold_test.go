@@ -0,0 +1,22 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHasPrefixFold(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(HasPrefixFold("hello world", ""))
+	assert.True(HasPrefixFold("HELLO world", "hello"))
+	assert.True(HasPrefixFold("hello world", "HELLO WORLD"))
+	assert.True(HasPrefixFold("ΣΑΣ ΕΙΝΑΙ", "σας"))
+	assert.True(HasPrefixFold("Kelvin scale", "kelvin"), "matched prefixes can differ in byte length")
+
+	assert.False(HasPrefixFold("hello", "hello world"))
+	assert.False(HasPrefixFold("hello world", "world"))
+	assert.False(HasPrefixFold("ırmak", "ir"))
+	assert.False(HasPrefixFold("İstanbul", "is"))
+}
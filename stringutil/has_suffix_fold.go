@@ -0,0 +1,21 @@
+package stringutil
+
+import "unicode/utf8"
+
+// HasSuffixFold returns if a string has a suffix under unicode case folding.
+// It uses the same semantics as `strings.EqualFold`, and as a result the matched
+// portion of `s` may have a different byte length than `suffix`.
+func HasSuffixFold(s, suffix string) bool {
+	for suffix != "" {
+		if s == "" {
+			return false
+		}
+		sr, sSize := utf8.DecodeLastRuneInString(s)
+		pr, pSize := utf8.DecodeLastRuneInString(suffix)
+		if !equalFoldRune(sr, pr) {
+			return false
+		}
+		s, suffix = s[:len(s)-sSize], suffix[:len(suffix)-pSize]
+	}
+	return true
+}
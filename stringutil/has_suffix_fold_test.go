@@ -0,0 +1,22 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHasSuffixFold(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(HasSuffixFold("hello world", ""))
+	assert.True(HasSuffixFold("hello WORLD", "world"))
+	assert.True(HasSuffixFold("hello world", "HELLO WORLD"))
+	assert.True(HasSuffixFold("ΕΙΝΑΙ ΣΑΣ", "σας"))
+	assert.True(HasSuffixFold("degrees K", "k"))
+
+	assert.False(HasSuffixFold("world", "hello world"))
+	assert.False(HasSuffixFold("hello world", "hello"))
+	assert.False(HasSuffixFold("kapı", "pi"))
+	assert.False(HasSuffixFold("KAPI", "pı"))
+}
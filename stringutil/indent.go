@@ -2,9 +2,16 @@ package stringutil
 
 import "strings"
 
-// Indent applies an indent prefix to a given corpus.
+// Indent applies an indent prefix to each line of a given corpus.
+// Empty lines are left empty so the output does not gain trailing whitespace.
 func Indent(indent string, corpus string) string {
-	return strings.Join(IndentLines(indent, SplitLines(corpus)), "\n")
+	lines := splitLinesWithEndings(corpus)
+	for index, line := range lines {
+		if content, _ := splitLineEnding(line); content != "" {
+			lines[index] = indent + line
+		}
+	}
+	return strings.Join(lines, "")
 }
 
 // IndentLines adds a prefix to a given list of strings.
@@ -14,3 +21,76 @@ func IndentLines(indent string, corpus []string) []string {
 	}
 	return corpus
 }
+
+// Dedent removes any common leading whitespace (spaces and tabs) from every line of a given corpus.
+func Dedent(corpus string) string {
+	lines := splitLinesWithEndings(corpus)
+
+	var margin string
+	var hasMargin bool
+	for _, line := range lines {
+		content, _ := splitLineEnding(line)
+		if strings.TrimLeft(content, " \t") == "" {
+			continue
+		}
+		leading := content[:len(content)-len(strings.TrimLeft(content, " \t"))]
+		if !hasMargin {
+			margin = leading
+			hasMargin = true
+			continue
+		}
+		margin = commonPrefix(margin, leading)
+	}
+
+	for index, line := range lines {
+		content, ending := splitLineEnding(line)
+		if strings.TrimLeft(content, " \t") == "" {
+			lines[index] = ending
+			continue
+		}
+		lines[index] = strings.TrimPrefix(content, margin) + ending
+	}
+	return strings.Join(lines, "")
+}
+
+// splitLinesWithEndings splits a corpus into lines, keeping the
+// line ending (`\n`, `\r\n` or `\r`) on each line.
+func splitLinesWithEndings(corpus string) (output []string) {
+	var start int
+	for index := 0; index < len(corpus); index++ {
+		switch corpus[index] {
+		case '\n':
+			output = append(output, corpus[start:index+1])
+			start = index + 1
+		case '\r':
+			if index+1 < len(corpus) && corpus[index+1] == '\n' {
+				index++
+			}
+			output = append(output, corpus[start:index+1])
+			start = index + 1
+		}
+	}
+	if start < len(corpus) {
+		output = append(output, corpus[start:])
+	}
+	return
+}
+
+// splitLineEnding splits a line into its content and its line ending.
+func splitLineEnding(line string) (content, ending string) {
+	content = strings.TrimRight(line, "\r\n")
+	ending = line[len(content):]
+	return
+}
+
+func commonPrefix(a, b string) string {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for index := 0; index < len(a); index++ {
+		if a[index] != b[index] {
+			return a[:index]
+		}
+	}
+	return a
+}
@@ -0,0 +1,60 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestIndent(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Input    string
+		Expected string
+	}{
+		{"", ""},
+		{"this", "\tthis"},
+		{"this\nthat", "\tthis\n\tthat"},
+		{"this\nthat\n", "\tthis\n\tthat\n"},
+		{"this\n\nthat\n", "\tthis\n\n\tthat\n"},
+		{"this\r\nthat\r\n", "\tthis\r\n\tthat\r\n"},
+		{"this\rthat", "\tthis\r\tthat"},
+		{"\n", "\n"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Expected, Indent("\t", tc.Input))
+	}
+}
+
+func TestIndentLines(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"\tthis", "\tthat"}, IndentLines("\t", []string{"this", "that"}))
+}
+
+func TestDedent(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Input    string
+		Expected string
+	}{
+		{"", ""},
+		{"this", "this"},
+		{"  this", "this"},
+		{"  this\n  that", "this\nthat"},
+		{"  this\n    that\n", "this\n  that\n"},
+		{"\tthis\n\n\tthat\n", "this\n\nthat\n"},
+		{"\tthis\n \n\tthat\n", "this\n\nthat\n"},
+		{"  this\r\n  that\r\n", "this\r\nthat\r\n"},
+		{"  this\n that", " this\nthat"},
+		{"\tthis\n  that", "\tthis\n  that"},
+		{"this\n  that", "this\n  that"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Expected, Dedent(tc.Input), tc.Input)
+	}
+}
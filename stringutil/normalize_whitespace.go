@@ -0,0 +1,53 @@
+package stringutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ZeroWidthRunes are the zero width characters removed by `NormalizeWhitespaceStripZeroWidth`.
+var ZeroWidthRunes = Runeset([]rune{
+	'\u200b', // zero width space
+	'\u200c', // zero width non-joiner
+	'\u200d', // zero width joiner
+	'\u2060', // word joiner
+	'\ufeff', // zero width no-break space / byte order mark
+})
+
+// NormalizeWhitespace trims leading and trailing whitespace, and collapses
+// runs of whitespace (spaces, tabs, newlines and other unicode spaces) within
+// the text into single spaces.
+func NormalizeWhitespace(text string) string {
+	return normalizeWhitespace(text, nil)
+}
+
+// NormalizeWhitespaceStripZeroWidth normalizes whitespace as with `NormalizeWhitespace`,
+// and also removes zero width characters (see `ZeroWidthRunes`).
+func NormalizeWhitespaceStripZeroWidth(text string) string {
+	return normalizeWhitespace(text, ZeroWidthRunes.Set())
+}
+
+func normalizeWhitespace(text string, strip map[rune]bool) string {
+	if text == "" {
+		return ""
+	}
+
+	output := new(strings.Builder)
+	output.Grow(len(text))
+	var pendingSpace bool
+	for _, r := range text {
+		if strip[r] {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			pendingSpace = output.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			output.WriteRune(' ')
+			pendingSpace = false
+		}
+		output.WriteRune(r)
+	}
+	return output.String()
+}
@@ -0,0 +1,35 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestNormalizeWhitespace(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", NormalizeWhitespace(""))
+	assert.Equal("", NormalizeWhitespace(" \t\r\n "))
+
+	assert.Equal("foo", NormalizeWhitespace("  foo\n"))
+	assert.Equal("foo bar", NormalizeWhitespace("foo\tbar"))
+	assert.Equal("foo bar", NormalizeWhitespace("foo\r\n\r\nbar"))
+	assert.Equal("foo bar baz", NormalizeWhitespace("\tfoo    bar \t\n baz  "))
+
+	// unicode spaces, e.g. no-break and ideographic spaces, are collapsed.
+	assert.Equal("foo bar baz", NormalizeWhitespace("foo\u00a0\u00a0bar\u3000baz"))
+	assert.Equal("héllo wörld", NormalizeWhitespace(" héllo \n wörld "))
+
+	// zero width characters are not whitespace, and are kept.
+	assert.Equal("foo\u200bbar", NormalizeWhitespace("foo\u200bbar"))
+}
+
+func TestNormalizeWhitespaceStripZeroWidth(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", NormalizeWhitespaceStripZeroWidth(""))
+	assert.Equal("", NormalizeWhitespaceStripZeroWidth("\u200b \ufeff"))
+	assert.Equal("foobar", NormalizeWhitespaceStripZeroWidth("\ufefffoo\u200bbar\u200d"))
+	assert.Equal("foo bar", NormalizeWhitespaceStripZeroWidth("foo \u200b\t bar"))
+}
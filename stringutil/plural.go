@@ -0,0 +1,18 @@
+package stringutil
+
+import "strconv"
+
+// Pluralize returns a count with the singular or plural form of a noun, e.g. "1 item" or "2 items".
+// The plural form is used for any count other than one, including zero.
+func Pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return strconv.Itoa(count) + " " + singular
+	}
+	return strconv.Itoa(count) + " " + plural
+}
+
+// Plural returns a count with a noun, adding an "s" to the noun for any count other than one,
+// e.g. "1 item", "0 items". Use `Pluralize` for nouns with other plural forms.
+func Plural(count int, word string) string {
+	return Pluralize(count, word, word+"s")
+}
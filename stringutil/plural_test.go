@@ -0,0 +1,23 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestPluralize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0 people", Pluralize(0, "person", "people"))
+	assert.Equal("1 person", Pluralize(1, "person", "people"))
+	assert.Equal("2 people", Pluralize(2, "person", "people"))
+}
+
+func TestPlural(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0 items", Plural(0, "item"))
+	assert.Equal("1 item", Plural(1, "item"))
+	assert.Equal("1234 items", Plural(1234, "item"))
+}
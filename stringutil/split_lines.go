@@ -3,33 +3,28 @@ package stringutil
 // SplitLines splits a corpus into individual lines by end of line character(s).
 // Possible end of line characters include `\n`, `\r` and `\r\n`.
 func SplitLines(contents string) []string {
-	contentRunes := []rune(contents)
-
-	var output []string
-
 	const (
 		newline        = '\n'
 		carriageReturn = '\r'
 	)
 
-	var line []rune
-	var c rune
-	for index := 0; index < len(contentRunes); index++ {
-		c = contentRunes[index]
-		if c == newline || c == carriageReturn {
-			if len(line) > 0 {
-				output = append(output, string(line))
-				line = nil
+	var output []string
+	var start int
+	for index := 0; index < len(contents); index++ {
+		switch contents[index] {
+		case newline:
+			output = append(output, contents[start:index])
+			start = index + 1
+		case carriageReturn:
+			output = append(output, contents[start:index])
+			if index+1 < len(contents) && contents[index+1] == newline {
+				index++
 			}
-			continue
+			start = index + 1
 		}
-		line = append(line, c)
-		continue
 	}
-
-	if len(line) > 0 {
-		output = append(output, string(line))
+	if start < len(contents) {
+		output = append(output, contents[start:])
 	}
-
 	return output
 }
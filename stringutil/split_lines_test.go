@@ -25,6 +25,14 @@ func TestSplitLines(t *testing.T) {
 		{"this\r\nthat\rthose\r", []string{"this", "that", "those"}},
 		{"this\r\nthat\r\nthose\r", []string{"this", "that", "those"}},
 		{"this\r\nthat\r\nthose\r\n", []string{"this", "that", "those"}},
+		{"\n", []string{""}},
+		{"\r\n", []string{""}},
+		{"this\n\nthat", []string{"this", "", "that"}},
+		{"this\r\n\r\nthat\r\n", []string{"this", "", "that"}},
+		{"this\r\rthat\n\rthose", []string{"this", "", "that", "", "those"}},
+		{"\nthis\n", []string{"", "this"}},
+		{"this\n\n", []string{"this", ""}},
+		{"héllo\r\nwörld", []string{"héllo", "wörld"}},
 	}
 
 	for _, tc := range testCases {
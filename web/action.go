@@ -8,3 +8,7 @@ type Action func(*Ctx) Result
 
 // PanicAction is a receiver for app.PanicHandler.
 type PanicAction func(*Ctx, interface{}) Result
+
+// ErrorAction is a receiver for app.ErrorAction.
+// It translates an error returned by an action into a result.
+type ErrorAction func(*Ctx, error) Result
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// these are compile time assertions
+var (
+	_ error  = (*APIError)(nil)
+	_ Result = (*APIError)(nil)
+)
+
+// API error codes.
+const (
+	APIErrorCodeBadRequest    = "bad_request"
+	APIErrorCodeNotAuthorized = "not_authorized"
+	APIErrorCodeForbidden     = "forbidden"
+	APIErrorCodeNotFound      = "not_found"
+	APIErrorCodeConflict      = "conflict"
+	APIErrorCodeInternalError = "internal_error"
+)
+
+// NewAPIError returns a new api error.
+func NewAPIError(status int, code, message string) *APIError {
+	return &APIError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// BadRequest returns a 400 api error.
+func BadRequest(message string) *APIError {
+	return NewAPIError(http.StatusBadRequest, APIErrorCodeBadRequest, message)
+}
+
+// NotAuthorized returns a 401 api error.
+func NotAuthorized(message string) *APIError {
+	return NewAPIError(http.StatusUnauthorized, APIErrorCodeNotAuthorized, message)
+}
+
+// Forbidden returns a 403 api error.
+func Forbidden(message string) *APIError {
+	return NewAPIError(http.StatusForbidden, APIErrorCodeForbidden, message)
+}
+
+// NotFound returns a 404 api error.
+func NotFound(message string) *APIError {
+	return NewAPIError(http.StatusNotFound, APIErrorCodeNotFound, message)
+}
+
+// Conflict returns a 409 api error.
+func Conflict(message string) *APIError {
+	return NewAPIError(http.StatusConflict, APIErrorCodeConflict, message)
+}
+
+// InternalError returns a 500 api error.
+func InternalError(message string) *APIError {
+	return NewAPIError(http.StatusInternalServerError, APIErrorCodeInternalError, message)
+}
+
+// AsAPIError returns an error as an api error if it is one, or is the class or an inner error of an exception.
+func AsAPIError(err error) (*APIError, bool) {
+	for err != nil {
+		if typed, ok := err.(*APIError); ok {
+			return typed, true
+		}
+		if typed := ex.As(err); typed != nil {
+			if class, ok := typed.Class.(*APIError); ok {
+				return class, true
+			}
+		}
+		err = ex.ErrInner(err)
+	}
+	return nil, false
+}
+
+// APIError is an error with an http status code, a machine readable code, and a message
+// that is safe to show to clients. It renders as a `ProblemResult`, and its cause is not shown.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Cause   error
+}
+
+// WithCause sets the underlying cause of the error and returns the error.
+func (ae *APIError) WithCause(err error) *APIError {
+	ae.Cause = err
+	return ae
+}
+
+// Error implements error.
+func (ae *APIError) Error() string {
+	message := ae.Message
+	if message == "" {
+		message = http.StatusText(ae.Status)
+	}
+	if ae.Cause != nil {
+		return message + ": " + ae.Cause.Error()
+	}
+	return message
+}
+
+// Unwrap returns the underlying cause.
+func (ae *APIError) Unwrap() error {
+	return ae.Cause
+}
+
+// Problem returns the error as a problem details result.
+func (ae *APIError) Problem() *ProblemResult {
+	return &ProblemResult{
+		Status: ae.Status,
+		Detail: ae.Message,
+		Code:   ae.Code,
+	}
+}
+
+// Render implements Result, rendering the error as a problem details result.
+func (ae *APIError) Render(ctx *Ctx) error {
+	return ae.Problem().Render(ctx)
+}
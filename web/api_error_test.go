@@ -0,0 +1,81 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestAPIErrorStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Error  *APIError
+		Status int
+		Code   string
+	}{
+		{BadRequest("bad"), http.StatusBadRequest, APIErrorCodeBadRequest},
+		{NotAuthorized("who"), http.StatusUnauthorized, APIErrorCodeNotAuthorized},
+		{Forbidden("no"), http.StatusForbidden, APIErrorCodeForbidden},
+		{NotFound("where"), http.StatusNotFound, APIErrorCodeNotFound},
+		{Conflict("again"), http.StatusConflict, APIErrorCodeConflict},
+		{InternalError("oops"), http.StatusInternalServerError, APIErrorCodeInternalError},
+		{NewAPIError(http.StatusTeapot, "teapot", "short and stout"), http.StatusTeapot, "teapot"},
+	}
+
+	for _, tc := range testCases {
+		app := MustNew()
+		app.GET("/", func(_ *Ctx) Result { return tc.Error })
+		contents, meta, err := MockGet(app, "/").Bytes()
+		assert.Nil(err)
+		assert.Equal(tc.Status, meta.StatusCode)
+		assert.Equal(ContentTypeApplicationProblemJSON, meta.Header.Get(HeaderContentType))
+		assert.Contains(string(contents), fmt.Sprintf(`"status":%d`, tc.Status))
+		assert.Contains(string(contents), fmt.Sprintf(`"code":%q`, tc.Code))
+		assert.Contains(string(contents), fmt.Sprintf(`"detail":%q`, tc.Error.Message))
+		assert.Contains(string(contents), fmt.Sprintf(`"title":%q`, http.StatusText(tc.Status)))
+	}
+}
+
+func TestAPIErrorCause(t *testing.T) {
+	assert := assert.New(t)
+
+	cause := fmt.Errorf("connection refused")
+	err := InternalError("could not fetch user").WithCause(cause)
+	assert.Equal("could not fetch user: connection refused", err.Error())
+	assert.Equal(cause, err.Unwrap())
+
+	typed, ok := AsAPIError(ex.New(err))
+	assert.True(ok)
+	assert.Equal(http.StatusInternalServerError, typed.Status)
+
+	typed, ok = AsAPIError(ex.New("wrapped", ex.OptInner(ex.New(err))))
+	assert.True(ok)
+	assert.Equal(http.StatusInternalServerError, typed.Status)
+
+	_, ok = AsAPIError(cause)
+	assert.False(ok)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return err })
+	contents, _, getErr := MockGet(app, "/").Bytes()
+	assert.Nil(getErr)
+	assert.False(strings.Contains(string(contents), "connection refused"), "the cause should not be shown to clients")
+}
+
+func TestAPIErrorTypedNil(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		var err *APIError
+		return err
+	})
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode, "a typed nil api error should be treated as a nil result")
+}
@@ -5,11 +5,16 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+	"runtime/debug"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/certutil"
 	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
 )
 
 // MustNew creates a new app and panics if there is an error.
@@ -39,6 +44,11 @@ func New(options ...Option) (*App, error) {
 			return nil, err
 		}
 	}
+	if a.Views != nil && a.Views.FuncMap != nil {
+		if _, ok := a.Views.FuncMap[ViewFuncRoutePath]; !ok {
+			a.Views.FuncMap[ViewFuncRoutePath] = a.viewRoutePath
+		}
+	}
 	return &a, nil
 }
 
@@ -50,6 +60,7 @@ type App struct {
 	Log                     logger.Log
 	Views                   *ViewCache
 	TLSConfig               *tls.Config
+	TLSCertFileWatcher      *certutil.CertFileWatcher
 	Server                  *http.Server
 	Listener                *net.TCPListener
 	DefaultHeaders          http.Header
@@ -58,10 +69,25 @@ type App struct {
 	NotFoundHandler         Handler
 	MethodNotAllowedHandler Handler
 	PanicAction             PanicAction
+	ErrorAction             ErrorAction
 	DefaultMiddleware       []Middleware
 	Tracer                  Tracer
 	DefaultProvider         ResultProvider
 	State                   *SyncState
+	// RouteMiddlewares are the middleware chains of the registered routes, in nesting order, by `Route.StringWithMethod()`.
+	RouteMiddlewares map[string][]Middleware
+	// AccessLogFields are the standard fields written in the json form of http response events.
+	// If unset, `logger.DefaultHTTPResponseFields` are written.
+	AccessLogFields []string
+	// AccessLogExtra returns custom fields written in the json form of http response events.
+	AccessLogExtra func(*Ctx) map[string]interface{}
+	// AccessLogSlowThreshold only writes the http response events of requests that take at least the threshold if it is positive,
+	// and logs a warning with their span breakdown if the `logger.Warning` flag is enabled.
+	AccessLogSlowThreshold time.Duration
+	// TrustedProxies are the proxies whose forwarding headers are trusted by `Ctx.RealIP()`.
+	TrustedProxies *webutil.IPAllowlist
+	// NamedRoutes are the routes named with `RouteRegistration.Name(...)`, by name.
+	NamedRoutes map[string]*Route
 }
 
 // CreateServer creates a new http.Server for the app.
@@ -84,6 +110,19 @@ func (a *App) Use(middleware Middleware) {
 	a.DefaultMiddleware = append(a.DefaultMiddleware, middleware)
 }
 
+// SetNotFoundHandler sets the action used to render requests that don't match a route.
+// The action is wrapped with the given middleware and the app default middleware, as with routes.
+func (a *App) SetNotFoundHandler(action Action, middleware ...Middleware) {
+	a.NotFoundHandler = a.RenderAction(a.NestMiddleware(action, middleware...))
+}
+
+// SetMethodNotAllowedHandler sets the action used to render requests that match a route for a different method.
+// The action is wrapped with the given middleware and the app default middleware, as with routes.
+// It is only used if `Config.HandleMethodNotAllowed` is set, and the `Allow` header is set before the action is called.
+func (a *App) SetMethodNotAllowedHandler(action Action, middleware ...Middleware) {
+	a.MethodNotAllowedHandler = a.RenderAction(a.NestMiddleware(action, middleware...))
+}
+
 // StartupTasks runs common startup tasks.
 func (a *App) StartupTasks() error {
 	return a.Views.Initialize()
@@ -111,22 +150,19 @@ func (a *App) Start() (err error) {
 		logger.MaybeInfof(a.Log, "%s using client cert pool with (%d) client certs", serverProtocol, len(a.Server.TLSConfig.ClientCAs.Subjects()))
 	}
 
-	var listener net.Listener
-	listener, err = net.Listen("tcp", a.Config.BindAddrOrDefault())
+	a.Listener, err = a.ListenInherited()
 	if err != nil {
-		err = ex.New(err)
-		return
-	}
-	var ok bool
-	a.Listener, ok = listener.(*net.TCPListener)
-	if !ok {
-		err = ex.New("listener returned was not a net.TCPListener")
 		return
 	}
 
 	keepAliveListener := TCPKeepAliveListener{a.Listener}
 	var shutdownErr error
 
+	if a.TLSCertFileWatcher != nil {
+		go a.TLSCertFileWatcher.Start()
+		<-a.TLSCertFileWatcher.NotifyStarted()
+	}
+
 	a.Started()
 	if a.Server.TLSConfig != nil {
 		shutdownErr = a.Server.Serve(tls.NewListener(keepAliveListener, a.Server.TLSConfig))
@@ -161,6 +197,12 @@ func (a *App) Stop() error {
 		return ex.New(err)
 	}
 
+	if a.TLSCertFileWatcher != nil && a.TLSCertFileWatcher.CanStop() {
+		if err := a.TLSCertFileWatcher.Stop(); err != nil {
+			return ex.New(err)
+		}
+	}
+
 	a.Server = nil
 	a.Listener = nil
 	logger.MaybeInfof(a.Log, "server shutdown complete")
@@ -215,7 +257,7 @@ func (a *App) ServeStatic(route string, searchPaths []string, middleware ...Midd
 	)
 	mountedRoute := a.formatStaticMountRoute(route)
 	a.Statics[mountedRoute] = sfs
-	a.Handle("GET", mountedRoute, a.RenderAction(a.NestMiddleware(sfs.Action, middleware...)))
+	a.handleAction("GET", mountedRoute, sfs.Action, middleware...)
 }
 
 // ServeStaticCached serves files from the given file system root(s).
@@ -230,7 +272,7 @@ func (a *App) ServeStaticCached(route string, searchPaths []string, middleware .
 	)
 	mountedRoute := a.formatStaticMountRoute(route)
 	a.Statics[mountedRoute] = sfs
-	a.Handle("GET", mountedRoute, a.RenderAction(a.NestMiddleware(sfs.Action, middleware...)))
+	a.handleAction("GET", mountedRoute, sfs.Action, middleware...)
 }
 
 func (a *App) formatStaticMountRoute(route string) string {
@@ -258,38 +300,38 @@ Routes should be registered in the form:
 It is important to note that routes are registered in order and
 cannot have any wildcards inside the routes.
 */
-func (a *App) GET(path string, action Action, middleware ...Middleware) {
-	a.Handle("GET", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) GET(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("GET", path, action, middleware...)
 }
 
 // OPTIONS registers a OPTIONS request handler.
-func (a *App) OPTIONS(path string, action Action, middleware ...Middleware) {
-	a.Handle("OPTIONS", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) OPTIONS(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("OPTIONS", path, action, middleware...)
 }
 
 // HEAD registers a HEAD request handler.
-func (a *App) HEAD(path string, action Action, middleware ...Middleware) {
-	a.Handle("HEAD", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) HEAD(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("HEAD", path, action, middleware...)
 }
 
 // PUT registers a PUT request handler.
-func (a *App) PUT(path string, action Action, middleware ...Middleware) {
-	a.Handle("PUT", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) PUT(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("PUT", path, action, middleware...)
 }
 
 // PATCH registers a PATCH request handler.
-func (a *App) PATCH(path string, action Action, middleware ...Middleware) {
-	a.Handle("PATCH", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) PATCH(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("PATCH", path, action, middleware...)
 }
 
 // POST registers a POST request actions.
-func (a *App) POST(path string, action Action, middleware ...Middleware) {
-	a.Handle("POST", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) POST(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("POST", path, action, middleware...)
 }
 
 // DELETE registers a DELETE request handler.
-func (a *App) DELETE(path string, action Action, middleware ...Middleware) {
-	a.Handle("DELETE", path, a.RenderAction(a.NestMiddleware(action, middleware...)))
+func (a *App) DELETE(path string, action Action, middleware ...Middleware) RouteRegistration {
+	return a.handleAction("DELETE", path, action, middleware...)
 }
 
 // Handle adds a raw handler at a given method and path.
@@ -330,9 +372,29 @@ func (a *App) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		defer a.recover(w, req)
 	}
 
+	if a.isDisallowedMethod(req.Method) {
+		if allow := a.allowed(req.URL.Path, req.Method); len(allow) > 0 {
+			w.Header().Set(HeaderAllow, allow)
+		}
+		if a.MethodNotAllowedHandler != nil {
+			a.MethodNotAllowedHandler(w, req, nil, nil)
+		} else {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
 	path := req.URL.Path
 	if root := a.Routes[req.Method]; root != nil {
-		if route, params, tsr := root.getValue(path); route != nil {
+		route, params, tsr := root.getValue(path)
+		if route == nil && a.Config.CaseInsensitiveRouting {
+			// the corrected path keeps the casing of the request path
+			// for parameters, and the request itself is left as is.
+			if ciPath, found := root.findCaseInsensitivePath(path, false); found {
+				route, params, tsr = root.getValue(string(ciPath))
+			}
+		}
+		if route != nil {
 			route.Handler(w, req, route, params)
 			return
 		} else if req.Method != MethodConnect && path != "/" {
@@ -358,6 +420,7 @@ func (a *App) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		if a.Config.HandleOptions {
 			if allow := a.allowed(path, req.Method); len(allow) > 0 {
 				w.Header().Set(HeaderAllow, allow)
+				w.WriteHeader(http.StatusNoContent)
 				return
 			}
 		}
@@ -405,7 +468,7 @@ func (a *App) RenderAction(action Action) Handler {
 				ctx.Response.Header()[key] = value
 			}
 		}
-		result := action(ctx)
+		result := a.resolveResult(ctx, action(ctx))
 		if result != nil {
 			// check for a prerender step
 			if typed, ok := result.(ResultPreRender); ok {
@@ -436,7 +499,7 @@ func (a *App) RenderAction(action Action) Handler {
 			a.logFatal(err, r)
 		}
 		if a.Log != nil {
-			a.Log.Trigger(r.Context(), a.httpResponseEvent(ctx))
+			a.logResponse(r.Context(), ctx)
 		}
 		if tf != nil {
 			tf.Finish(ctx, err)
@@ -449,7 +512,45 @@ func (a *App) NestMiddleware(action Action, middleware ...Middleware) Action {
 	if len(middleware) == 0 && len(a.DefaultMiddleware) == 0 {
 		return action
 	}
+	return NestMiddleware(action, a.middlewareChain(middleware...)...)
+}
+
+// RouteMiddleware returns the names of the middleware for a given route in the order they execute.
+// The path can either be the registered route path or a request path that matches the route.
+// Middleware registered with `Named` report the given name, and others their function name (see `MiddlewareName`).
+// Routes added with `Handle` report no middleware, as raw handlers aren't wrapped with any.
+func (a *App) RouteMiddleware(method, path string) []string {
+	route, _, _ := a.Lookup(method, path)
+	if route == nil {
+		return nil
+	}
+	chain := a.RouteMiddlewares[route.StringWithMethod()]
+	output := make([]string, len(chain))
+	for index := range chain {
+		// the chain is stored in nesting order, the last middleware runs first.
+		output[len(chain)-1-index] = MiddlewareName(chain[index])
+	}
+	return output
+}
 
+//
+// internal helpers
+//
+
+// handleAction registers an action with a given set of middleware, and records the middleware chain for `RouteMiddleware`.
+func (a *App) handleAction(method, path string, action Action, middleware ...Middleware) RouteRegistration {
+	chain := a.middlewareChain(middleware...)
+	a.Handle(method, path, a.RenderAction(NestMiddleware(action, chain...)))
+	if a.RouteMiddlewares == nil {
+		a.RouteMiddlewares = make(map[string][]Middleware)
+	}
+	a.RouteMiddlewares[Route{Method: method, Path: path}.StringWithMethod()] = chain
+	return RouteRegistration{App: a, Method: method, Path: path}
+}
+
+// middlewareChain returns the route middleware combined with the app default middleware
+// in nesting order, that is the default middleware last (and as a result outermost).
+func (a *App) middlewareChain(middleware ...Middleware) []Middleware {
 	finalMiddleware := make([]Middleware, len(middleware)+len(a.DefaultMiddleware))
 	cursor := len(finalMiddleware) - 1
 	for i := len(a.DefaultMiddleware) - 1; i >= 0; i-- {
@@ -461,14 +562,9 @@ func (a *App) NestMiddleware(action Action, middleware ...Middleware) Action {
 		finalMiddleware[cursor] = middleware[i]
 		cursor--
 	}
-
-	return NestMiddleware(action, finalMiddleware...)
+	return finalMiddleware
 }
 
-//
-// internal helpers
-//
-
 func (a *App) createCtx(w ResponseWriter, r *http.Request, route *Route, p RouteParameters, extra ...CtxOption) *Ctx {
 	options := []CtxOption{
 		OptCtxApp(a),
@@ -483,42 +579,56 @@ func (a *App) createCtx(w ResponseWriter, r *http.Request, route *Route, p Route
 	return NewCtx(w, r, append(options, extra...)...)
 }
 
-func (a *App) allowed(path, reqMethod string) (allow string) {
+// allowed returns the value of the `Allow` header for a path, i.e. the sorted list of methods
+// with a route that matches the path, excluding the request method.
+// `OPTIONS` is listed last if options requests for the path are handled.
+func (a *App) allowed(path, reqMethod string) string {
+	var methods []string
 	if path == "*" { // server-wide
 		for method := range a.Routes {
-			if method == "OPTIONS" {
+			if method == MethodOptions {
 				continue
 			}
-
-			// add request method to list of allowed methods
-			if allow == "" {
-				allow = method
-			} else {
-				allow += ", " + method
-			}
+			methods = append(methods, method)
 		}
-		return
+		sort.Strings(methods)
+		return strings.Join(methods, ", ")
 	}
 	for method := range a.Routes {
 		// Skip the requested method - we already tried this one
-		if method == reqMethod || method == "OPTIONS" {
+		if method == reqMethod || method == MethodOptions {
 			continue
 		}
-
-		handle, _, _ := a.Routes[method].getValue(path)
-		if handle != nil {
-			// add request method to list of allowed methods
-			if allow == "" {
-				allow = method
-			} else {
-				allow += ", " + method
-			}
+		if handle, _, _ := a.Routes[method].getValue(path); handle != nil {
+			methods = append(methods, method)
 		}
 	}
-	if allow != "" {
-		allow += ", OPTIONS"
+	if len(methods) == 0 {
+		return ""
 	}
-	return
+	sort.Strings(methods)
+	if a.Config.HandleOptions || a.hasRoute(MethodOptions, path) {
+		methods = append(methods, MethodOptions)
+	}
+	return strings.Join(methods, ", ")
+}
+
+// hasRoute returns if a route is registered for a method that matches a path.
+func (a *App) hasRoute(method, path string) bool {
+	if root := a.Routes[method]; root != nil {
+		handle, _, _ := root.getValue(path)
+		return handle != nil
+	}
+	return false
+}
+
+func (a *App) isDisallowedMethod(method string) bool {
+	for _, disallowed := range a.Config.DisallowedMethodsOrDefault() {
+		if strings.EqualFold(method, disallowed) {
+			return true
+		}
+	}
+	return false
 }
 
 func (a *App) httpRequestEvent(ctx *Ctx) *logger.HTTPRequestEvent {
@@ -529,13 +639,46 @@ func (a *App) httpRequestEvent(ctx *Ctx) *logger.HTTPRequestEvent {
 	return event
 }
 
+// logResponse triggers the http response event for a request, and a warning if the request was slow.
+func (a *App) logResponse(triggerCtx context.Context, ctx *Ctx) {
+	log := ctx.withLogLabels(a.Log)
+	if a.AccessLogSlowThreshold <= 0 {
+		log.Trigger(triggerCtx, a.httpResponseEvent(ctx))
+		return
+	}
+	elapsed := ctx.Elapsed()
+	if elapsed < a.AccessLogSlowThreshold {
+		// listeners still receive the event, e.g. for metrics.
+		log.Trigger(logger.WithSkipWrite(triggerCtx), a.httpResponseEvent(ctx))
+		return
+	}
+	log.Trigger(triggerCtx, a.httpResponseEvent(ctx))
+
+	fields := map[string]interface{}{
+		"elapsed":   elapsed.String(),
+		"threshold": a.AccessLogSlowThreshold.String(),
+	}
+	for name, spanElapsed := range ctx.Spans() {
+		fields["span."+name] = spanElapsed.String()
+	}
+	err := ex.New(ErrSlowRequest, ex.OptMessagef("%s %s took %v", ctx.Request.Method, ctx.Request.URL.Path, elapsed))
+	warning := logger.NewErrorEvent(logger.Warning, err, logger.OptErrorEventState(ctx.Request), logger.OptErrorEventFields(fields))
+	log.Trigger(triggerCtx, warning)
+}
+
 func (a *App) httpResponseEvent(ctx *Ctx) *logger.HTTPResponseEvent {
 	event := logger.NewHTTPResponseEvent(ctx.Request,
 		logger.OptHTTPResponseStatusCode(ctx.Response.StatusCode()),
 		logger.OptHTTPResponseContentLength(ctx.Response.ContentLength()),
 		logger.OptHTTPResponseHeader(ctx.Response.Header()), // caveat: these do not get written out in text or json ever.
 		logger.OptHTTPResponseElapsed(ctx.Elapsed()),
+		logger.OptHTTPResponseSpans(ctx.Spans()),
+		logger.OptHTTPResponseFields(a.AccessLogFields...),
 	)
+	event.ClientIP = ctx.RemoteAddr()
+	if a.AccessLogExtra != nil {
+		event.Extra = a.AccessLogExtra(ctx)
+	}
 
 	if ctx.Route != nil {
 		event.Route = ctx.Route.String()
@@ -549,8 +692,8 @@ func (a *App) httpResponseEvent(ctx *Ctx) *logger.HTTPResponseEvent {
 
 func (a *App) recover(w http.ResponseWriter, req *http.Request) {
 	if rcv := recover(); rcv != nil {
-		err := ex.New(rcv)
-		a.logFatal(err, req)
+		err := panicError(rcv, debug.Stack())
+		a.logFatal(err, req, logger.OptErrorEventFields(panicFields(rcv)))
 		if a.PanicAction != nil {
 			a.handlePanic(w, req, rcv)
 		} else {
@@ -568,11 +711,41 @@ func (a *App) handlePanic(w http.ResponseWriter, r *http.Request, err interface{
 	})(w, r, nil, nil)
 }
 
-func (a *App) logFatal(err error, req *http.Request) {
+// resolveResult translates an error returned by an action, including as the inner result of a decorated result, with `handleError`.
+// A typed nil, e.g. a nil `*APIError`, would otherwise pass as a non-nil error or result, so it's resolved to nil.
+func (a *App) resolveResult(ctx *Ctx, result Result) Result {
+	if isNilResult(result) {
+		return nil
+	}
+	if typed, ok := result.(*DecoratedResult); ok {
+		typed.Inner = a.resolveResult(ctx, typed.Inner)
+		return typed
+	}
+	if typed, ok := result.(error); ok {
+		return a.handleError(ctx, typed)
+	}
+	return result
+}
+
+// handleError translates an error returned by an action into a result with the app error action.
+func (a *App) handleError(ctx *Ctx, err error) Result {
+	if typed, ok := err.(*ErrorResult); ok {
+		err = typed.Err
+	}
+	if a.ErrorAction != nil {
+		if result := a.ErrorAction(ctx, err); result != nil {
+			return result
+		}
+	}
+	return DefaultErrorHandler(ctx, err)
+}
+
+func (a *App) logFatal(err error, req *http.Request, options ...logger.ErrorEventOption) {
 	if a.Log == nil {
 		return
 	}
 	if err != nil {
-		a.Log.Trigger(req.Context(), logger.NewErrorEvent(logger.Fatal, err, logger.OptErrorEventState(req)))
+		options = append([]logger.ErrorEventOption{logger.OptErrorEventState(req)}, options...)
+		a.Log.Trigger(req.Context(), logger.NewErrorEvent(logger.Fatal, err, options...))
 	}
 }
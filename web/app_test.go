@@ -2,10 +2,13 @@ package web
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/blend/go-sdk/env"
 	"github.com/blend/go-sdk/graceful"
 	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/r2"
 )
 
 // assert an app is graceful
@@ -668,7 +672,7 @@ func TestAppViewTracerError(t *testing.T) {
 	app, err := New()
 	assert.Nil(err)
 
-	app.Views.AddLiterals("{{ define \"ok\" }}{{template \"fake\"}}ok{{end}}")
+	app.Views.AddLiterals("{{ define \"ok\" }}{{ .ViewModel.Fake }}ok{{end}}")
 	app.GET("/view", viewOK)
 	app.Tracer = mockTracer{
 		OnStart: func(_ *Ctx) { wg.Done() },
@@ -721,6 +725,9 @@ func TestAppAllowed(t *testing.T) {
 	assert.Nil(err)
 
 	app.GET("/hello", controllerNoOp)
+	assert.Equal("GET", app.allowed("/hello", ""), "options shouldn't be listed unless options requests are handled")
+
+	app.Config.HandleOptions = true
 	allowed = strings.Split(app.allowed("/hello", ""), ", ")
 	assert.Len(allowed, 2)
 	assert.Any(allowed, func(i interface{}) bool {
@@ -749,3 +756,357 @@ func TestAppAllowed(t *testing.T) {
 	allowed = strings.Split(app.allowed("/hello", ""), ", ")
 	assert.Len(allowed, 7)
 }
+
+func TestAppRouteMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	tracked := func(name string) Middleware {
+		return Named(name, func(action Action) Action {
+			return func(ctx *Ctx) Result {
+				calls = append(calls, name)
+				return action(ctx)
+			}
+		})
+	}
+	// middleware that never call their action are named all the same.
+	denyAll := Named("deny", func(_ Action) Action {
+		return func(_ *Ctx) Result { return NoContent }
+	})
+
+	app := MustNew(OptUse(tracked("logging")), OptUse(JSONProviderAsDefault), OptUse(tracked("recover")))
+	app.GET("/users/:id", func(_ *Ctx) Result {
+		calls = append(calls, "action")
+		return NoContent
+	}, tracked("auth"), tracked("session"))
+	app.GET("/denied", controllerNoOp, denyAll)
+	app.GET("/unnamed", controllerNoOp, SessionAware)
+	app.Handle("GET", "/raw", func(w http.ResponseWriter, _ *http.Request, _ *Route, _ RouteParameters) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	middleware := app.RouteMiddleware("GET", "/users/:id")
+	assert.Equal([]string{"recover", "github.com/blend/go-sdk/web.JSONProviderAsDefault", "logging", "session", "auth"}, middleware)
+	assert.Equal(middleware, app.RouteMiddleware("GET", "/users/1234"))
+
+	_, err := MockGet(app, "/users/1234").Discard()
+	assert.Nil(err)
+	assert.Equal([]string{"recover", "logging", "session", "auth", "action"}, calls)
+
+	assert.Equal("deny", app.RouteMiddleware("GET", "/denied")[3])
+	assert.Equal("github.com/blend/go-sdk/web.SessionAware", app.RouteMiddleware("GET", "/unnamed")[3])
+	assert.Empty(app.RouteMiddleware("GET", "/raw"), "raw handlers aren't wrapped with middleware")
+	assert.Empty(app.RouteMiddleware("POST", "/users/1234"))
+	assert.Empty(app.RouteMiddleware("GET", "/not-a-route"))
+}
+
+func TestAppRedirectTrailingSlash(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptRedirectTrailingSlash(true))
+	app.GET("/users", func(_ *Ctx) Result { return NoContent })
+	app.POST("/users", func(_ *Ctx) Result { return NoContent })
+	app.GET("/groups/", func(_ *Ctx) Result { return NoContent })
+
+	res, err := MockGet(app, "/users/", r2.OptNoFollow(), r2.OptQueryValue("limit", "10")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMovedPermanently, res.StatusCode)
+	assert.Equal("/users?limit=10", res.Header.Get("Location"))
+
+	res, err = MockGet(app, "/groups", r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMovedPermanently, res.StatusCode)
+	assert.Equal("/groups/", res.Header.Get("Location"))
+
+	res, err = MockMethod(app, "POST", "/users/", r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusTemporaryRedirect, res.StatusCode, "non-GET requests should preserve the method")
+	assert.Equal("/users", res.Header.Get("Location"))
+}
+
+func TestAppStrictSlash(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptStrictSlash())
+	app.GET("/users", func(_ *Ctx) Result { return NoContent })
+
+	res, err := MockGet(app, "/users/", r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+
+	res, err = MockGet(app, "/users", r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+}
+
+func TestAppCaseInsensitiveRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := func(r *Ctx) Result {
+		name, _ := r.RouteParam("name")
+		return Text.Result(r.Request.URL.Path + " " + name)
+	}
+
+	app := MustNew()
+	app.GET("/users/:name", handler)
+	res, err := MockGet(app, "/Users/Bailey").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode, "case insensitive routing should be disabled by default")
+
+	app = MustNew(OptCaseInsensitiveRouting(true))
+	app.GET("/users/:name", handler)
+	contents, meta, err := MockGet(app, "/Users/Bailey").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("/Users/Bailey Bailey", string(contents))
+
+	contents, meta, err = MockGet(app, "/users/Bailey").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("/users/Bailey Bailey", string(contents))
+
+	res, err = MockGet(app, "/Groups/Bailey").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func TestAppMethodNotAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/users", controllerNoOp)
+	res, err := MockPost(app, "/users", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode, "method not allowed handling should be disabled by default")
+
+	app = MustNew(OptHandleMethodNotAllowed(true))
+	app.GET("/users", controllerNoOp)
+	res, err = MockPost(app, "/users", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal("GET", res.Header.Get(HeaderAllow))
+
+	app.PUT("/users", controllerNoOp)
+	res, err = MockPost(app, "/users", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal("GET, PUT", res.Header.Get(HeaderAllow))
+
+	app.Config.HandleOptions = true
+	res, err = MockPost(app, "/users", nil).Discard()
+	assert.Nil(err)
+	assert.Equal("GET, PUT, OPTIONS", res.Header.Get(HeaderAllow), "options should be listed if options requests are handled")
+
+	res, err = MockPost(app, "/groups", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode, "paths without any routes should still 404")
+}
+
+func TestAppHandleOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/users", controllerNoOp)
+	res, err := MockMethod(app, MethodOptions, "/users").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode, "options handling should be disabled by default")
+
+	app = MustNew(OptHandleOptions(true))
+	app.GET("/users", controllerNoOp)
+	app.POST("/users", controllerNoOp)
+	res, err = MockMethod(app, MethodOptions, "/users").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+	assert.Equal("GET, POST, OPTIONS", res.Header.Get(HeaderAllow))
+
+	res, err = MockMethod(app, MethodOptions, "/groups").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+
+	app.OPTIONS("/users", func(r *Ctx) Result {
+		return Text.Result("explicit")
+	})
+	contents, meta, err := MockMethod(app, MethodOptions, "/users").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode, "explicit options routes should take precedence")
+	assert.Equal("explicit", string(contents))
+	assert.Empty(meta.Header.Get(HeaderAllow))
+}
+
+func TestAppSetNotFoundHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.Use(func(action Action) Action {
+		return func(r *Ctx) Result {
+			r.Response.Header().Set("X-Default-Middleware", "true")
+			return action(r)
+		}
+	})
+	app.GET("/", controllerNoOp)
+	app.SetNotFoundHandler(func(r *Ctx) Result {
+		assert.NotNil(r.App)
+		return JSON.Status(http.StatusNotFound, map[string]string{"path": r.Request.URL.Path})
+	})
+
+	contents, meta, err := MockGet(app, "/doesntexist").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+	assert.Equal(ContentTypeApplicationJSON, meta.Header.Get(HeaderContentType))
+	assert.Equal("true", meta.Header.Get("X-Default-Middleware"))
+	assert.Equal(`{"path":"/doesntexist"}`, strings.TrimSpace(string(contents)))
+}
+
+func TestAppSetMethodNotAllowedHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptHandleMethodNotAllowed(true))
+	app.GET("/users", controllerNoOp)
+	app.SetMethodNotAllowedHandler(func(r *Ctx) Result {
+		return JSON.Status(http.StatusMethodNotAllowed, map[string]string{"allow": r.Response.Header().Get(HeaderAllow)})
+	})
+
+	contents, meta, err := MockPost(app, "/users", nil).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, meta.StatusCode)
+	assert.Equal("GET", meta.Header.Get(HeaderAllow))
+	assert.Equal(`{"allow":"GET"}`, strings.TrimSpace(string(contents)))
+}
+
+func TestAppSpansLogged(t *testing.T) {
+	assert := assert.New(t)
+
+	events := make(chan *logger.HTTPResponseEvent, 1)
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(nil))
+	defer log.Close()
+	log.Listen(logger.HTTPResponse, "test", logger.NewHTTPResponseEventListener(func(_ context.Context, e *logger.HTTPResponseEvent) {
+		events <- e
+	}))
+
+	app := MustNew(OptLog(log))
+	app.GET("/", func(r *Ctx) Result {
+		stop := r.StartSpan("auth")
+		time.Sleep(time.Millisecond)
+		stop()
+		stop = r.StartSpan("db")
+		time.Sleep(2 * time.Millisecond)
+		stop()
+		return Text.Result("ok")
+	})
+	_, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+
+	e := <-events
+	assert.Len(e.Spans, 2)
+	assert.True(e.Spans["auth"] >= time.Millisecond)
+	assert.True(e.Spans["db"] >= 2*time.Millisecond)
+
+	contents, err := json.Marshal(e)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"spans":{`)
+}
+
+func TestAppAccessLogFields(t *testing.T) {
+	assert := assert.New(t)
+
+	events := make(chan *logger.HTTPResponseEvent, 1)
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(nil))
+	defer log.Close()
+	log.Listen(logger.HTTPResponse, "test", logger.NewHTTPResponseEventListener(func(_ context.Context, e *logger.HTTPResponseEvent) {
+		events <- e
+	}))
+
+	app := MustNew(
+		OptLog(log),
+		OptAccessLogFields(logger.HTTPResponseFieldVerb, logger.HTTPResponseFieldPath, logger.HTTPResponseFieldReferer, logger.HTTPResponseFieldStatusCode),
+		OptAccessLogExtra(func(r *Ctx) map[string]interface{} {
+			return map[string]interface{}{"tenant": r.Request.Header.Get("X-Tenant")}
+		}),
+	)
+	app.GET("/", func(r *Ctx) Result {
+		return Text.Result("ok")
+	})
+	_, err := MockGet(app, "/",
+		r2.OptHeaderValue("User-Agent", "go-sdk test"),
+		r2.OptHeaderValue("Referer", "https://example.com/"),
+		r2.OptHeaderValue("X-Tenant", "blend"),
+	).Discard()
+	assert.Nil(err)
+
+	contents, err := json.Marshal(<-events)
+	assert.Nil(err)
+	var fields map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &fields))
+	assert.Equal("GET", fields[logger.HTTPResponseFieldVerb])
+	assert.Equal("https://example.com/", fields[logger.HTTPResponseFieldReferer])
+	assert.Equal("blend", fields["tenant"])
+	_, hasUserAgent := fields[logger.HTTPResponseFieldUserAgent]
+	assert.False(hasUserAgent, "excluded fields should be absent")
+}
+
+func TestAppDisallowedMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.Handle(MethodTrace, "/", app.RenderAction(func(_ *Ctx) Result { return NoContent }))
+	app.GET("/", func(_ *Ctx) Result { return NoContent })
+
+	meta, err := MockMethod(app, MethodTrace, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, meta.StatusCode, "TRACE should be disallowed by default")
+	assert.Equal("GET", meta.Header.Get(HeaderAllow), "the allow header shouldn't list the disallowed method")
+
+	meta, err = MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+
+	app = MustNew(OptDisallowedMethods())
+	app.Handle(MethodTrace, "/", app.RenderAction(func(_ *Ctx) Result { return NoContent }))
+	meta, err = MockMethod(app, MethodTrace, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode, "TRACE should be allowed if overridden")
+
+	app = MustNew(OptDisallowedMethods("trace", MethodConnect))
+	app.GET("/", func(_ *Ctx) Result { return NoContent })
+	meta, err = MockMethod(app, MethodConnect, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, meta.StatusCode)
+	assert.Equal("GET", meta.Header.Get(HeaderAllow))
+	meta, err = MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+}
+
+func TestAppAccessLogSlowThreshold(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(buffer), logger.OptText(logger.OptTextNoColor(), logger.OptTextHideTimestamp()))
+	var responses int32
+	log.Listen(logger.HTTPResponse, "test", logger.NewHTTPResponseEventListener(func(_ context.Context, _ *logger.HTTPResponseEvent) {
+		atomic.AddInt32(&responses, 1)
+	}))
+
+	app := MustNew(OptLog(log), OptAccessLogSlowThreshold(50*time.Millisecond))
+	app.GET("/fast", func(_ *Ctx) Result { return NoContent })
+	app.GET("/slow", func(r *Ctx) Result {
+		finish := r.StartSpan("query")
+		time.Sleep(75 * time.Millisecond)
+		finish()
+		return NoContent
+	})
+
+	_, err := MockGet(app, "/fast").Discard()
+	assert.Nil(err)
+	assert.Nil(log.Drain())
+	assert.NotContains(buffer.String(), "["+logger.HTTPResponse+"]", "fast requests should not be written")
+	assert.Equal(1, atomic.LoadInt32(&responses), "listeners should receive fast requests")
+
+	_, err = MockGet(app, "/slow").Discard()
+	assert.Nil(err)
+	assert.Nil(log.Drain())
+	assert.Contains(buffer.String(), "["+logger.HTTPResponse+"]")
+	assert.Contains(buffer.String(), "["+logger.Warning+"]")
+	assert.Contains(buffer.String(), "GET /slow took")
+	assert.Contains(buffer.String(), "span.query")
+}
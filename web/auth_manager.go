@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/webutil"
 )
 
@@ -259,26 +260,37 @@ func (am AuthManager) Login(userID string, ctx *Ctx) (session *Session, err erro
 		session.ExpiresUTC = am.SessionTimeoutProvider(session)
 	}
 	session.UserAgent = webutil.GetUserAgent(ctx.Request)
-	session.RemoteAddr = webutil.GetRemoteAddr(ctx.Request)
+	session.RemoteAddr = ctx.RemoteAddr()
 
-	// call the perist handler if one's been provided
-	if am.PersistHandler != nil {
-		err = am.PersistHandler(ctx.Context(), session)
-		if err != nil {
-			return nil, err
-		}
+	if err = am.issue(ctx, session); err != nil {
+		return nil, err
 	}
+	return session, nil
+}
 
-	// if we're in jwt mode, serialize the jwt.
-	if am.SerializeSessionValueHandler != nil {
-		sessionValue, err = am.SerializeSessionValueHandler(ctx.Context(), session)
-		if err != nil {
+// RegenerateSession moves the request session to a new session id, keeping its user and state, and removes the old one.
+// It should be called when the user's privileges change to prevent session fixation.
+func (am AuthManager) RegenerateSession(ctx *Ctx) (*Session, error) {
+	if ctx.Session == nil {
+		return nil, ex.New(ErrSessionUnset)
+	}
+	previousValue := am.readSessionValue(ctx)
+
+	session := ctx.Session
+	session.SessionID = NewSessionID()
+	session.CreatedUTC = time.Now().UTC()
+	if am.SessionTimeoutProvider != nil {
+		session.ExpiresUTC = am.SessionTimeoutProvider(session)
+	}
+	if err := am.issue(ctx, session); err != nil {
+		return nil, err
+	}
+	if am.RemoveHandler != nil && len(previousValue) > 0 {
+		if err := am.RemoveHandler(ctx.Context(), previousValue); err != nil {
 			return nil, err
 		}
 	}
-
-	// inject cookies into the response
-	am.injectCookie(ctx, sessionValue, session.ExpiresUTC)
+	ctx.WithContext(WithSession(ctx.Context(), session))
 	return session, nil
 }
 
@@ -399,6 +411,31 @@ func (am AuthManager) expire(ctx *Ctx, sessionValue string) error {
 	return nil
 }
 
+// issue persists a session and writes its session value cookie to the response.
+func (am AuthManager) issue(ctx *Ctx, session *Session) (err error) {
+	sessionValue := session.SessionID
+
+	// call the perist handler if one's been provided
+	if am.PersistHandler != nil {
+		err = am.PersistHandler(ctx.Context(), session)
+		if err != nil {
+			return
+		}
+	}
+
+	// if we're in jwt mode, serialize the jwt.
+	if am.SerializeSessionValueHandler != nil {
+		sessionValue, err = am.SerializeSessionValueHandler(ctx.Context(), session)
+		if err != nil {
+			return
+		}
+	}
+
+	// inject cookies into the response
+	am.injectCookie(ctx, sessionValue, session.ExpiresUTC)
+	return
+}
+
 func (am AuthManager) shouldUpdateSessionExpiry() bool {
 	return am.SessionTimeoutProvider != nil
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/uuid"
 	"github.com/blend/go-sdk/webutil"
 )
@@ -210,6 +211,40 @@ func TestAuthManagerLogout(t *testing.T) {
 	assert.True(time.Now().UTC().After(cookie.Expires))
 }
 
+func TestAuthManagerRegenerateSession(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := NewLocalSessionCache()
+	am, err := NewLocalAuthManagerFromCache(cache)
+	assert.Nil(err)
+
+	r := NewCtx(webutil.NewMockResponse(new(bytes.Buffer)), webutil.NewMockRequest("GET", "/"))
+	_, err = am.RegenerateSession(r)
+	assert.True(ex.Is(err, ErrSessionUnset))
+
+	session, err := am.Login("bailey@blend.com", r)
+	assert.Nil(err)
+	previousID := session.SessionID
+	session.State["cart"] = "socks"
+
+	res := webutil.NewMockResponse(new(bytes.Buffer))
+	r = NewCtx(res, webutil.NewMockRequestWithCookie("GET", "/", am.CookieDefaults.Name, previousID))
+	r.Session = session
+
+	regenerated, err := am.RegenerateSession(r)
+	assert.Nil(err)
+	assert.NotEqual(previousID, regenerated.SessionID)
+	assert.Equal("bailey@blend.com", regenerated.UserID)
+	assert.Equal("socks", regenerated.State["cart"])
+
+	assert.Nil(cache.Get(previousID), "the previous session should be removed")
+	assert.NotNil(cache.Get(regenerated.SessionID))
+
+	cookies := ReadSetCookies(res.Header())
+	assert.NotEmpty(cookies)
+	assert.Equal(regenerated.SessionID, cookies[0].Value)
+}
+
 func TestAuthManagerVerifySessionParsed(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,187 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// these are compile time assertions
+var (
+	_ ResponseWriter      = (*CaptureResponseWriter)(nil)
+	_ logger.Event        = (*CapturedBodiesEvent)(nil)
+	_ logger.TextWritable = (*CapturedBodiesEvent)(nil)
+	_ json.Marshaler      = (*CapturedBodiesEvent)(nil)
+)
+
+// CaptureBodiesTruncated is the marker appended to captured bodies that exceeded the capture limit.
+const CaptureBodiesTruncated = "...(truncated)"
+
+// CaptureBodies returns a middleware that captures up to `maxBytes` of the request and response bodies
+// and triggers a `debug` flagged `CapturedBodiesEvent` once the response is complete.
+func CaptureBodies(maxBytes int) Middleware {
+	return func(action Action) Action {
+		return func(r *Ctx) Result {
+			if !captureBodiesEnabled(r) {
+				return action(r)
+			}
+
+			event := &CapturedBodiesEvent{
+				EventMeta: logger.NewEventMeta(logger.Debug),
+				Request:   r.Request,
+			}
+			if r.Request.Body != nil {
+				body, err := ioutil.ReadAll(io.LimitReader(r.Request.Body, int64(maxBytes)+1))
+				if err != nil {
+					return r.DefaultProvider.InternalError(ex.New(err))
+				}
+				r.Request.Body = readCloser{
+					Reader: io.MultiReader(bytes.NewReader(body), r.Request.Body),
+					Closer: r.Request.Body,
+				}
+				event.RequestBody = truncateCapturedBody(body, maxBytes)
+			}
+
+			r.Response = NewCaptureResponseWriter(r.Response, maxBytes, func(crw *CaptureResponseWriter) {
+				event.StatusCode = crw.StatusCode()
+				event.ResponseBody = crw.Body()
+				r.App.Log.Trigger(r.Context(), event)
+			})
+			return action(r)
+		}
+	}
+}
+
+// NewCaptureResponseWriter returns a new capture response writer.
+// The `onClose` handler is called once when the writer is closed.
+func NewCaptureResponseWriter(w ResponseWriter, maxBytes int, onClose func(*CaptureResponseWriter)) *CaptureResponseWriter {
+	return &CaptureResponseWriter{
+		innerResponse: w,
+		maxBytes:      maxBytes,
+		onClose:       onClose,
+	}
+}
+
+// CaptureResponseWriter is a response writer that copies up to a given number of bytes
+// written to the response into a buffer.
+type CaptureResponseWriter struct {
+	innerResponse ResponseWriter
+	maxBytes      int
+	body          bytes.Buffer
+	truncated     bool
+	onClose       func(*CaptureResponseWriter)
+}
+
+// Write writes the data to the response, capturing it if there is room.
+func (crw *CaptureResponseWriter) Write(b []byte) (int, error) {
+	if remaining := crw.maxBytes - crw.body.Len(); remaining < len(b) {
+		crw.truncated = true
+		if remaining > 0 {
+			crw.body.Write(b[:remaining])
+		}
+	} else {
+		crw.body.Write(b)
+	}
+	return crw.innerResponse.Write(b)
+}
+
+// Header returns the response headers.
+func (crw *CaptureResponseWriter) Header() http.Header {
+	return crw.innerResponse.Header()
+}
+
+// WriteHeader writes the status code.
+func (crw *CaptureResponseWriter) WriteHeader(code int) {
+	crw.innerResponse.WriteHeader(code)
+}
+
+// StatusCode returns the status code.
+func (crw *CaptureResponseWriter) StatusCode() int {
+	return crw.innerResponse.StatusCode()
+}
+
+// ContentLength returns the content length.
+func (crw *CaptureResponseWriter) ContentLength() int {
+	return crw.innerResponse.ContentLength()
+}
+
+// Body returns the captured body, with the truncation marker if the response exceeded the capture limit.
+func (crw *CaptureResponseWriter) Body() string {
+	if crw.truncated {
+		return crw.body.String() + CaptureBodiesTruncated
+	}
+	return crw.body.String()
+}
+
+// Flush implements http.Flusher.
+func (crw *CaptureResponseWriter) Flush() {
+	crw.innerResponse.Flush()
+}
+
+// Close closes the inner response and calls the close handler.
+func (crw *CaptureResponseWriter) Close() error {
+	err := crw.innerResponse.Close()
+	if crw.onClose != nil {
+		crw.onClose(crw)
+		crw.onClose = nil
+	}
+	return err
+}
+
+// CapturedBodiesEvent is an event with the captured bodies of a request and response.
+type CapturedBodiesEvent struct {
+	*logger.EventMeta
+	Request      *http.Request
+	RequestBody  string
+	StatusCode   int
+	ResponseBody string
+}
+
+// WriteText implements logger.TextWritable.
+func (e CapturedBodiesEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	fmt.Fprintf(wr, "%s %s %s", e.Request.Method, e.Request.URL.String(), logger.ColorizeStatusCodeWithFormatter(tf, e.StatusCode))
+	fmt.Fprintf(wr, "\nrequest: %q", e.RequestBody)
+	fmt.Fprintf(wr, "\nresponse: %q", e.ResponseBody)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e CapturedBodiesEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(logger.MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
+		"verb":         e.Request.Method,
+		"path":         e.Request.URL.Path,
+		"query":        e.Request.URL.RawQuery,
+		"statusCode":   e.StatusCode,
+		"requestBody":  e.RequestBody,
+		"responseBody": e.ResponseBody,
+	}))
+}
+
+// captureBodiesEnabled returns if captured bodies would be logged for the request.
+func captureBodiesEnabled(r *Ctx) bool {
+	if r.App == nil || r.App.Log == nil {
+		return false
+	}
+	if typed, ok := r.App.Log.(interface{ IsEnabled(string) bool }); ok {
+		return typed.IsEnabled(logger.Debug)
+	}
+	return true
+}
+
+func truncateCapturedBody(body []byte, maxBytes int) string {
+	if len(body) > maxBytes {
+		return string(body[:maxBytes]) + CaptureBodiesTruncated
+	}
+	return string(body)
+}
+
+// readCloser pairs a reader with the closer of the original body it reads from.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
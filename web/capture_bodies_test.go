@@ -0,0 +1,111 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestCaptureBodies(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.MustNew(logger.OptOutput(ioutil.Discard), logger.OptEnabled(logger.Debug))
+	defer log.Close()
+
+	events := make(chan *CapturedBodiesEvent, 1)
+	log.Listen(logger.Debug, "test", func(_ context.Context, e logger.Event) {
+		if typed, ok := e.(*CapturedBodiesEvent); ok {
+			events <- typed
+		}
+	})
+
+	app := MustNew(OptLog(log))
+	app.POST("/echo", func(r *Ctx) Result {
+		body, err := r.PostBody()
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		return Text.Result(string(body))
+	}, CaptureBodies(5))
+
+	contents, meta, err := MockPost(app, "/echo", ioutil.NopCloser(bytes.NewBufferString("hello world"))).Bytes()
+	assert.Nil(err)
+	assert.Equal(200, meta.StatusCode)
+	assert.Equal("hello world", string(contents), "the handler should see the full request body")
+
+	event := <-events
+	assert.Equal(logger.Debug, event.GetFlag())
+	assert.Equal("/echo", event.Request.URL.Path)
+	assert.Equal(200, event.StatusCode)
+	assert.Equal("hello"+CaptureBodiesTruncated, event.RequestBody)
+	assert.Equal("hello"+CaptureBodiesTruncated, event.ResponseBody)
+}
+
+func TestCaptureBodiesUnderLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.MustNew(logger.OptOutput(ioutil.Discard), logger.OptEnabled(logger.Debug))
+	defer log.Close()
+
+	events := make(chan *CapturedBodiesEvent, 1)
+	log.Listen(logger.Debug, "test", func(_ context.Context, e logger.Event) {
+		if typed, ok := e.(*CapturedBodiesEvent); ok {
+			events <- typed
+		}
+	})
+
+	app := MustNew(OptLog(log))
+	app.POST("/echo", func(r *Ctx) Result {
+		body, _ := r.PostBody()
+		return Text.Result(string(body))
+	}, CaptureBodies(1024))
+
+	_, err := MockPost(app, "/echo", ioutil.NopCloser(bytes.NewBufferString("hello"))).Discard()
+	assert.Nil(err)
+
+	event := <-events
+	assert.Equal("hello", event.RequestBody)
+	assert.Equal("hello", event.ResponseBody)
+}
+
+func TestCaptureBodiesDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.MustNew(logger.OptOutput(ioutil.Discard), logger.OptEnabled(logger.Info))
+	defer log.Close()
+
+	app := MustNew(OptLog(log))
+	app.POST("/echo", func(r *Ctx) Result {
+		if _, ok := r.Response.(*CaptureResponseWriter); ok {
+			return Text.BadRequest(nil)
+		}
+		body, _ := r.PostBody()
+		return Text.Result(string(body))
+	}, CaptureBodies(1024))
+
+	contents, meta, err := MockPost(app, "/echo", ioutil.NopCloser(bytes.NewBufferString("hello"))).Bytes()
+	assert.Nil(err)
+	assert.Equal(200, meta.StatusCode)
+	assert.Equal("hello", string(contents))
+}
+
+func TestCapturedBodiesEventMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	req := webutil.NewMockRequest("POST", "/echo")
+	e := CapturedBodiesEvent{EventMeta: logger.NewEventMeta(logger.Debug), Request: req, StatusCode: 200, RequestBody: "foo", ResponseBody: "bar"}
+	contents, err := e.MarshalJSON()
+	assert.Nil(err)
+	assert.Contains(string(contents), `"requestBody":"foo"`)
+	assert.Contains(string(contents), `"responseBody":"bar"`)
+
+	buffer := new(bytes.Buffer)
+	e.WriteText(logger.NewTextOutputFormatter(logger.OptTextNoColor()), buffer)
+	assert.Contains(buffer.String(), `request: "foo"`)
+	assert.Contains(buffer.String(), `response: "bar"`)
+}
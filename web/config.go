@@ -16,8 +16,10 @@ type Config struct {
 	BindAddr                  string        `json:"bindAddr,omitempty" yaml:"bindAddr,omitempty" env:"BIND_ADDR"`
 	BaseURL                   string        `json:"baseURL,omitempty" yaml:"baseURL,omitempty" env:"BASE_URL"`
 	SkipRedirectTrailingSlash bool          `json:"skipRedirectTrailingSlash,omitempty" yaml:"skipRedirectTrailingSlash,omitempty"`
+	CaseInsensitiveRouting    bool          `json:"caseInsensitiveRouting,omitempty" yaml:"caseInsensitiveRouting,omitempty"`
 	HandleOptions             bool          `json:"handleOptions,omitempty" yaml:"handleOptions,omitempty"`
 	HandleMethodNotAllowed    bool          `json:"handleMethodNotAllowed,omitempty" yaml:"handleMethodNotAllowed,omitempty"`
+	DisallowedMethods         []string      `json:"disallowedMethods,omitempty" yaml:"disallowedMethods,omitempty"`
 	DisablePanicRecovery      bool          `json:"disablePanicRecovery,omitempty" yaml:"disablePanicRecovery,omitempty"`
 	SessionTimeout            time.Duration `json:"sessionTimeout,omitempty" yaml:"sessionTimeout,omitempty" env:"SESSION_TIMEOUT"`
 	SessionTimeoutIsRelative  bool          `json:"sessionTimeoutIsRelative,omitempty" yaml:"sessionTimeoutIsRelative,omitempty" env:"SESSION_TIMEOUT_RELATIVE"`
@@ -31,11 +33,18 @@ type Config struct {
 
 	DefaultHeaders      map[string]string `json:"defaultHeaders,omitempty" yaml:"defaultHeaders,omitempty"`
 	MaxHeaderBytes      int               `json:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" env:"MAX_HEADER_BYTES"`
+	MaxBodyBytes        int64             `json:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" env:"MAX_BODY_BYTES"`
 	ReadTimeout         time.Duration     `json:"readTimeout,omitempty" yaml:"readTimeout,omitempty" env:"READ_HEADER_TIMEOUT"`
 	ReadHeaderTimeout   time.Duration     `json:"readHeaderTimeout,omitempty" yaml:"readHeaderTimeout,omitempty" env:"READ_HEADER_TIMEOUT"`
 	WriteTimeout        time.Duration     `json:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" env:"WRITE_TIMEOUT"`
 	IdleTimeout         time.Duration     `json:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" env:"IDLE_TIMEOUT"`
 	ShutdownGracePeriod time.Duration     `json:"shutdownGracePeriod" yaml:"shutdownGracePeriod" env:"SHUTDOWN_GRACE_PERIOD"`
+	// ReusePort sets `SO_REUSEPORT` on the listener, on platforms that support it, so
+	// multiple processes can listen on the same port.
+	ReusePort bool `json:"reusePort,omitempty" yaml:"reusePort,omitempty" env:"REUSE_PORT"`
+	// InheritListener uses the listener passed by the parent process with `LISTEN_FDS`, if any,
+	// instead of binding `BindAddr`.
+	InheritListener bool `json:"inheritListener,omitempty" yaml:"inheritListener,omitempty" env:"INHERIT_LISTENER"`
 
 	Views ViewCacheConfig `json:"views,omitempty" yaml:"views,omitempty"`
 }
@@ -153,6 +162,23 @@ func (c Config) MaxHeaderBytesOrDefault() int {
 	return DefaultMaxHeaderBytes
 }
 
+// DisallowedMethodsOrDefault returns the methods rejected with a 405 before routing or a default.
+// An empty, non-nil list allows every method.
+func (c Config) DisallowedMethodsOrDefault() []string {
+	if c.DisallowedMethods != nil {
+		return c.DisallowedMethods
+	}
+	return DefaultDisallowedMethods
+}
+
+// MaxBodyBytesOrDefault returns the maximum body size in bytes read by `Ctx.PostBody()` or a default.
+func (c Config) MaxBodyBytesOrDefault() int64 {
+	if c.MaxBodyBytes > 0 {
+		return c.MaxBodyBytes
+	}
+	return DefaultMaxBodyBytes
+}
+
 // ReadTimeoutOrDefault gets a property.
 func (c Config) ReadTimeoutOrDefault() time.Duration {
 	if c.ReadTimeout > 0 {
@@ -39,6 +39,11 @@ const (
 	// Typical values for this include "no-cache", "max-age", "min-fresh", and "max-stale" variants.
 	HeaderCacheControl = "Cache-Control"
 
+	// HeaderExpires is the "Expires" header.
+	// It indicates the time after which the response is considered stale.
+	// It is superseded by the `max-age` directive of "Cache-Control" for clients that support it.
+	HeaderExpires = "Expires"
+
 	// HeaderConnection is the "Connection" header.
 	// It is used to indicate if the connection should remain open by the server
 	// after the final response bytes are sent.
@@ -46,6 +51,10 @@ const (
 	// penalites in making requests.
 	HeaderConnection = "Connection"
 
+	// HeaderContentDisposition is the "Content-Disposition" header.
+	// It indicates if the response should be displayed inline or downloaded as an attachment.
+	HeaderContentDisposition = "Content-Disposition"
+
 	// HeaderContentEncoding is the "Content-Encoding" header.
 	// It is used to indicate what the response encoding is.
 	// Typical values are "gzip", "deflate", "compress", "br", and "identity" indicating no compression.
@@ -89,10 +98,34 @@ const (
 	// HeaderStrictTransportSecurity is the hsts header.
 	HeaderStrictTransportSecurity = "Strict-Transport-Security"
 
+	// HeaderReferrerPolicy is the "Referrer-Policy" header.
+	HeaderReferrerPolicy = "Referrer-Policy"
+
+	// HeaderContentSecurityPolicy is the "Content-Security-Policy" header.
+	HeaderContentSecurityPolicy = "Content-Security-Policy"
+
+	// HeaderDeprecation is the "Deprecation" header.
+	// It is set on responses from deprecated endpoints.
+	HeaderDeprecation = "Deprecation"
+
+	// HeaderSunset is the "Sunset" header (RFC 8594).
+	// It is the date a deprecated endpoint will stop responding.
+	HeaderSunset = "Sunset"
+
+	// HeaderXRequestTimeout is the "X-Request-Timeout" header.
+	// It is used by callers to indicate how long they will wait for a response.
+	HeaderXRequestTimeout = "X-Request-Timeout"
+
 	// ContentTypeApplicationJSON is a content type for JSON responses.
 	// We specify chartset=utf-8 so that clients know to use the UTF-8 string encoding.
 	ContentTypeApplicationJSON = "application/json; charset=UTF-8"
 
+	// ContentTypeApplicationOctetStream is a content type for binary responses of an unknown type.
+	ContentTypeApplicationOctetStream = "application/octet-stream"
+
+	// ContentTypeApplicationProblemJSON is a content type for RFC 7807 problem details responses.
+	ContentTypeApplicationProblemJSON = "application/problem+json; charset=UTF-8"
+
 	// ContentTypeHTML is a content type for html responses.
 	// We specify chartset=utf-8 so that clients know to use the UTF-8 string encoding.
 	ContentTypeHTML = "text/html; charset=utf-8"
@@ -143,6 +176,9 @@ const (
 	// MethodConnect is an http verb.
 	MethodConnect = "CONNECT"
 
+	// MethodTrace is an http verb.
+	MethodTrace = "TRACE"
+
 	// MethodOptions is an http verb.
 	MethodOptions = "OPTIONS"
 )
@@ -156,6 +192,18 @@ const (
 
 	// HSTSPreload is a header value token.
 	HSTSPreload = "preload"
+
+	// XContentTypeOptionsNoSniff is a header value for the content type options header.
+	XContentTypeOptionsNoSniff = "nosniff"
+
+	// XFrameOptionsDeny is a header value for the frame options header.
+	XFrameOptionsDeny = "DENY"
+
+	// XFrameOptionsSameOrigin is a header value for the frame options header.
+	XFrameOptionsSameOrigin = "SAMEORIGIN"
+
+	// ReferrerPolicyStrictOriginWhenCrossOrigin is a header value for the referrer policy header.
+	ReferrerPolicyStrictOriginWhenCrossOrigin = "strict-origin-when-cross-origin"
 )
 
 // Environment Variables
@@ -189,8 +237,17 @@ const (
 
 	// EnvironmentVariableTLSKeyFile is an env var that contains the file path to the TLS key.
 	EnvironmentVariableTLSKeyFile = "TLS_KEY_FILE"
+
+	// EnvironmentVariableListenFDs is an env var that contains the number of listener fds passed to the process, starting at `ListenFDsStart`.
+	EnvironmentVariableListenFDs = "LISTEN_FDS"
+
+	// EnvironmentVariableListenPID is an env var that contains the pid of the process the listener fds were passed to.
+	EnvironmentVariableListenPID = "LISTEN_PID"
 )
 
+// ListenFDsStart is the first fd passed listeners start at, i.e. after stdin, stdout and stderr.
+const ListenFDsStart = 3
+
 // Defaults
 const (
 	// DefaultBindAddr is the default bind address.
@@ -210,6 +267,8 @@ const (
 
 	// DefaultMaxHeaderBytes is a default that is unset.
 	DefaultMaxHeaderBytes = 0
+	// DefaultMaxBodyBytes is a default that is unset, i.e. bodies are not limited.
+	DefaultMaxBodyBytes int64 = 0
 	// DefaultReadTimeout is a default.
 	DefaultReadTimeout = 5 * time.Second
 	// DefaultReadHeaderTimeout is a default.
@@ -238,6 +297,9 @@ const (
 	// DefaultHTTPSUpgradeTargetPort is the default upgrade target port.
 	DefaultHTTPSUpgradeTargetPort = 443
 
+	// DefaultHSTSMaxAge is the default max age for the hsts header (one year).
+	DefaultHSTSMaxAge = 365 * 24 * time.Hour
+
 	// DefaultShutdownGracePeriod is the default shutdown grace period.
 	DefaultShutdownGracePeriod = 30 * time.Second
 
@@ -246,6 +308,9 @@ const (
 
 	// DefaultBufferPoolSize is the default buffer pool size.
 	DefaultViewBufferPoolSize = 256
+
+	// DefaultPprofPrefix is the default path prefix for the pprof routes.
+	DefaultPprofPrefix = "/debug/pprof"
 )
 
 // DefaultHeaders are the default headers added by go-web.
@@ -253,6 +318,10 @@ var DefaultHeaders = http.Header{
 	HeaderServer: []string{PackageName},
 }
 
+// DefaultDisallowedMethods are the methods rejected with a 405 before routing by default.
+// `TRACE` is disallowed because it can be used for cross site tracing (XST).
+var DefaultDisallowedMethods = []string{MethodTrace}
+
 // SessionLockPolicy is a lock policy.
 type SessionLockPolicy int
 
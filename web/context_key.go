@@ -0,0 +1,64 @@
+package web
+
+import (
+	"context"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// NewContextKey returns a new context key.
+// Keys are compared by identity, not by name, so two keys created with
+// the same name (e.g. in separate packages) will not collide.
+func NewContextKey(name string) ContextKey {
+	return ContextKey{key: &contextKey{name: name}}
+}
+
+// ContextKey is a typed key for values stored on a request context.
+type ContextKey struct {
+	key *contextKey
+}
+
+// String returns the name of the key.
+func (ck ContextKey) String() string {
+	if ck.key == nil {
+		return ""
+	}
+	return ck.key.name
+}
+
+// contextKey is the unexported type that backs context keys.
+type contextKey struct {
+	name string
+}
+
+// keys used by the web package.
+var (
+	contextKeySession = NewContextKey("session")
+)
+
+// WithRequestID adds a request id to a context.
+// It is the same request id as `webutil.WithRequestID`, so it's propagated on outbound requests by `r2.OptTraceContext`.
+// The `TraceContextAware` middleware sets it from the inbound `X-Request-Id` header.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return webutil.WithRequestID(ctx, requestID)
+}
+
+// GetRequestID gets a request id off a context.
+func GetRequestID(ctx context.Context) string {
+	return webutil.GetRequestID(ctx)
+}
+
+// WithSession adds a session to a context.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, contextKeySession, session)
+}
+
+// GetSession gets a session off a context.
+func GetSession(ctx context.Context) *Session {
+	if value := ctx.Value(contextKeySession); value != nil {
+		if typed, ok := value.(*Session); ok {
+			return typed
+		}
+	}
+	return nil
+}
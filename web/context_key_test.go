@@ -0,0 +1,43 @@
+package web
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestContextKey(t *testing.T) {
+	assert := assert.New(t)
+
+	first := NewContextKey("foo")
+	second := NewContextKey("foo")
+	assert.Equal("foo", first.String())
+	assert.Empty(ContextKey{}.String())
+
+	ctx := MockCtx("GET", "/")
+	ctx.WithValue(first, "bar")
+	assert.Equal("bar", ctx.Value(first))
+	assert.Nil(ctx.Value(second), "keys with the same name should not collide")
+	assert.Nil(ctx.Context().Value("foo"), "keys should not collide with string keys")
+
+	ctx.WithValue(second, "baz")
+	assert.Equal("bar", ctx.Value(first))
+	assert.Equal("baz", ctx.Value(second))
+}
+
+func TestContextRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(GetRequestID(context.Background()))
+	requestID := NewRequestID()
+	assert.Equal(requestID, GetRequestID(WithRequestID(context.Background(), requestID)))
+}
+
+func TestContextSession(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(GetSession(context.Background()))
+	session := NewSession("test user", NewSessionID())
+	assert.Equal(session, GetSession(WithSession(context.Background(), session)))
+}
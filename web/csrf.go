@@ -0,0 +1,181 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// CSRF defaults.
+const (
+	// DefaultCSRFCookieName is the default name of the cookie that holds the csrf token.
+	DefaultCSRFCookieName = "_csrf"
+	// DefaultCSRFHeaderName is the default header unsafe requests echo the csrf token in.
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+	// DefaultCSRFFormField is the default form field unsafe requests echo the csrf token in.
+	DefaultCSRFFormField = "_csrf"
+	// DefaultCSRFTokenBytes is the default number of random bytes in a csrf token.
+	DefaultCSRFTokenBytes = 32
+)
+
+// NewCSRFConfig returns a new csrf config with defaults.
+func NewCSRFConfig(options ...CSRFOption) CSRFConfig {
+	cfg := CSRFConfig{
+		CookieName: DefaultCSRFCookieName,
+		CookiePath: "/",
+		HeaderName: DefaultCSRFHeaderName,
+		FormField:  DefaultCSRFFormField,
+		SameSite:   http.SameSiteLaxMode,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// CSRFOption mutates a csrf config.
+type CSRFOption func(*CSRFConfig)
+
+// OptCSRFCookieName sets the name of the cookie that holds the csrf token.
+func OptCSRFCookieName(name string) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.CookieName = name }
+}
+
+// OptCSRFCookiePath sets the path of the csrf cookie.
+func OptCSRFCookiePath(path string) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.CookiePath = path }
+}
+
+// OptCSRFCookieSecure sets if the csrf cookie should only be sent over https.
+func OptCSRFCookieSecure(secure bool) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.Secure = secure }
+}
+
+// OptCSRFCookieSameSite sets the same site mode of the csrf cookie.
+func OptCSRFCookieSameSite(sameSite http.SameSite) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.SameSite = sameSite }
+}
+
+// OptCSRFHeaderName sets the header unsafe requests can echo the csrf token in.
+func OptCSRFHeaderName(name string) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.HeaderName = name }
+}
+
+// OptCSRFFormField sets the form field unsafe requests can echo the csrf token in.
+func OptCSRFFormField(field string) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.FormField = field }
+}
+
+// OptCSRFExemptPaths adds paths that aren't checked for a csrf token, e.g. webhooks verified with `VerifySignature`.
+// Paths ending in `*` exempt any path with that prefix, e.g. `/webhooks/*`.
+func OptCSRFExemptPaths(paths ...string) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.ExemptPaths = append(cfg.ExemptPaths, paths...) }
+}
+
+// OptCSRFForbidden sets the action called for requests with a missing or mismatched token.
+func OptCSRFForbidden(forbidden Action) CSRFOption {
+	return func(cfg *CSRFConfig) { cfg.Forbidden = forbidden }
+}
+
+// CSRFConfig is the configuration for the csrf middleware.
+type CSRFConfig struct {
+	CookieName  string
+	CookiePath  string
+	Secure      bool
+	SameSite    http.SameSite
+	HeaderName  string
+	FormField   string
+	ExemptPaths []string
+	Forbidden   Action
+}
+
+// IsExempt returns if a path is exempt from csrf checks.
+func (cfg CSRFConfig) IsExempt(path string) bool {
+	for _, exempt := range cfg.ExemptPaths {
+		if strings.HasSuffix(exempt, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(exempt, "*")) {
+				return true
+			}
+		} else if path == exempt {
+			return true
+		}
+	}
+	return false
+}
+
+// CSRF returns a middleware that protects unsafe requests with a double submit csrf token,
+// sent back in the `X-CSRF-Token` header or the `_csrf` form field.
+func CSRF(options ...CSRFOption) Middleware {
+	cfg := NewCSRFConfig(options...)
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			if cfg.IsExempt(ctx.Request.URL.Path) {
+				return action(ctx)
+			}
+
+			ctx.csrf = &cfg
+			if cookie := ctx.Cookie(cfg.CookieName); cookie != nil && cookie.Value != "" {
+				ctx.csrfToken = cookie.Value
+			}
+			if isSafeMethod(ctx.Request.Method) {
+				if ctx.csrfToken == "" {
+					if _, err := ctx.RotateCSRFToken(); err != nil {
+						return ctx.DefaultProvider.InternalError(err)
+					}
+				}
+				return action(ctx)
+			}
+
+			if ctx.csrfToken == "" || !csrfTokensEqual(ctx.csrfToken, cfg.submittedToken(ctx)) {
+				if cfg.Forbidden != nil {
+					return cfg.Forbidden(ctx)
+				}
+				return ctx.DefaultProvider.Status(http.StatusForbidden, ErrCSRFTokenInvalid.Error())
+			}
+			return action(ctx)
+		}
+	}
+}
+
+// submittedToken returns the token echoed by the request in the header or the form field.
+func (cfg CSRFConfig) submittedToken(ctx *Ctx) string {
+	if cfg.HeaderName != "" {
+		if token := ctx.Request.Header.Get(cfg.HeaderName); token != "" {
+			return token
+		}
+	}
+	if cfg.FormField != "" {
+		if token, err := ctx.FormValue(cfg.FormField); err == nil {
+			return token
+		}
+	}
+	return ""
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func csrfTokensEqual(expected, actual string) bool {
+	if actual == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+}
+
+func newCSRFToken() (string, error) {
+	token := make([]byte, DefaultCSRFTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", ex.New(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,109 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func csrfTestApp() *App {
+	app := MustNew()
+	app.GET("/form", func(r *Ctx) Result {
+		return Text.Result(r.CSRFToken())
+	}, CSRF(OptCSRFExemptPaths("/webhooks/*")))
+	app.POST("/form", func(r *Ctx) Result {
+		return NoContent
+	}, CSRF(OptCSRFExemptPaths("/webhooks/*")))
+	app.POST("/webhooks/github", func(r *Ctx) Result {
+		return NoContent
+	}, CSRF(OptCSRFExemptPaths("/webhooks/*")))
+	app.POST("/login", func(r *Ctx) Result {
+		token, err := r.RotateCSRFToken()
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		return Text.Result(token)
+	}, CSRF())
+	return app
+}
+
+func TestCSRF(t *testing.T) {
+	assert := assert.New(t)
+
+	app := csrfTestApp()
+
+	contents, meta, err := MockGet(app, "/form").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	cookies := ReadSetCookies(meta.Header)
+	assert.Len(cookies, 1)
+	assert.Equal(DefaultCSRFCookieName, cookies[0].Name)
+	assert.Equal(string(contents), cookies[0].Value, "the token should be available to render forms")
+	token := cookies[0].Value
+	assert.NotEmpty(token)
+
+	// safe requests with a token keep it.
+	contents, meta, err = MockGet(app, "/form", r2.OptCookieValue(DefaultCSRFCookieName, token)).Bytes()
+	assert.Nil(err)
+	assert.Equal(token, string(contents))
+	assert.Empty(ReadSetCookies(meta.Header))
+
+	// valid tokens in the header or form field.
+	meta, err = MockPost(app, "/form", nil, r2.OptCookieValue(DefaultCSRFCookieName, token), r2.OptHeaderValue(DefaultCSRFHeaderName, token)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	meta, err = MockPost(app, "/form", nil, r2.OptCookieValue(DefaultCSRFCookieName, token), r2.OptPostFormValue(DefaultCSRFFormField, token)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+
+	// missing tokens.
+	meta, err = MockPost(app, "/form", nil, r2.OptCookieValue(DefaultCSRFCookieName, token)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+	meta, err = MockPost(app, "/form", nil, r2.OptHeaderValue(DefaultCSRFHeaderName, token)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode, "the cookie is required")
+
+	// mismatched tokens.
+	meta, err = MockPost(app, "/form", nil, r2.OptCookieValue(DefaultCSRFCookieName, token), r2.OptHeaderValue(DefaultCSRFHeaderName, token+"x")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+
+	// exempt paths.
+	meta, err = MockPost(app, "/webhooks/github", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+}
+
+func TestCtxRotateCSRFToken(t *testing.T) {
+	assert := assert.New(t)
+
+	app := csrfTestApp()
+	_, meta, err := MockGet(app, "/form").Bytes()
+	assert.Nil(err)
+	token := ReadSetCookies(meta.Header)[0].Value
+
+	contents, meta, err := MockPost(app, "/login", nil, r2.OptCookieValue(DefaultCSRFCookieName, token), r2.OptHeaderValue(DefaultCSRFHeaderName, token)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	rotated := ReadSetCookies(meta.Header)
+	assert.Len(rotated, 1)
+	assert.Equal(string(contents), rotated[0].Value)
+	assert.NotEqual(token, rotated[0].Value)
+
+	meta, err = MockPost(app, "/form", nil, r2.OptCookieValue(DefaultCSRFCookieName, rotated[0].Value), r2.OptHeaderValue(DefaultCSRFHeaderName, token)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode, "the old token should no longer be valid")
+}
+
+func TestCSRFConfigIsExempt(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := NewCSRFConfig(OptCSRFExemptPaths("/hooks/stripe", "/webhooks/*"))
+	assert.True(cfg.IsExempt("/hooks/stripe"))
+	assert.False(cfg.IsExempt("/hooks/stripe/extra"))
+	assert.True(cfg.IsExempt("/webhooks/github"))
+	assert.False(cfg.IsExempt("/form"))
+}
@@ -5,12 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/jwt"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/oauth"
 	"github.com/blend/go-sdk/webutil"
 )
 
@@ -70,6 +76,25 @@ type Ctx struct {
 	// RequestEnd is the time the request is finished processing.
 	// It is used to compute elapsed time (with RequestStart).
 	RequestEnd time.Time
+
+	cacheMu sync.Mutex
+	cache   map[string]interface{}
+
+	spansMu sync.Mutex
+	spans   map[string]time.Duration
+
+	csrf      *CSRFConfig
+	csrfToken string
+
+	claims *jwt.StandardClaims
+
+	oauth *OAuthConfig
+
+	realIPOnce sync.Once
+	realIP     net.IP
+
+	logLabelsMu sync.Mutex
+	logLabels   logger.Fields
 }
 
 // WithContext sets the background context for the request.
@@ -79,10 +104,48 @@ func (rc *Ctx) WithContext(context context.Context) *Ctx {
 }
 
 // Context returns the context.
+// It is the request context, including any values or deadlines set with `WithContext`
+// or `WithValue` by middleware.
 func (rc *Ctx) Context() context.Context {
+	if rc.Request == nil {
+		return context.Background()
+	}
 	return rc.Request.Context()
 }
 
+// Deadline returns the deadline of the request context, if one is set.
+func (rc *Ctx) Deadline() (time.Time, bool) {
+	return rc.Context().Deadline()
+}
+
+// IsCancelled returns if the request context is done, i.e. the client
+// went away or a deadline passed. Long running actions should check
+// it periodically and stop work if it returns true.
+func (rc *Ctx) IsCancelled() bool {
+	select {
+	case <-rc.Context().Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// DetachedContext returns a context with the values of the request context, e.g. the
+// request id and logger fields, but without its deadline or cancellation.
+func (rc *Ctx) DetachedContext() context.Context {
+	return detachedContext{parent: rc.Context()}
+}
+
+// WithValue sets a value on the request context for a given key.
+func (rc *Ctx) WithValue(key ContextKey, value interface{}) *Ctx {
+	return rc.WithContext(context.WithValue(rc.Context(), key, value))
+}
+
+// Value returns a value from the request context for a given key.
+func (rc *Ctx) Value(key ContextKey) interface{} {
+	return rc.Context().Value(key)
+}
+
 // WithStateValue sets the state for a key to an object.
 func (rc *Ctx) WithStateValue(key string, value interface{}) *Ctx {
 	rc.State.Set(key, value)
@@ -94,6 +157,24 @@ func (rc *Ctx) StateValue(key string) interface{} {
 	return rc.State.Get(key)
 }
 
+// CacheGet returns a value from the request scoped cache, and if it was set.
+func (rc *Ctx) CacheGet(key string) (interface{}, bool) {
+	rc.cacheMu.Lock()
+	defer rc.cacheMu.Unlock()
+	value, ok := rc.cache[key]
+	return value, ok
+}
+
+// CacheSet sets a value in the request scoped cache, which is cleared when the request finishes.
+func (rc *Ctx) CacheSet(key string, value interface{}) {
+	rc.cacheMu.Lock()
+	defer rc.cacheMu.Unlock()
+	if rc.cache == nil {
+		rc.cache = make(map[string]interface{})
+	}
+	rc.cache[key] = value
+}
+
 // Param returns a parameter from the request.
 /*
 It checks, in order:
@@ -197,19 +278,21 @@ func (rc *Ctx) HeaderValue(key string) (value string, err error) {
 
 // PostBody reads, caches and returns the bytes on a request post body.
 // It will store those bytes for re-use on this context object.
+// If the app config sets `MaxBodyBytes`, bodies larger than the limit return `ErrBodyTooLarge`.
 // If you're expecting a large post body, or a large post body is even possible
 // use a stream reader on `.Request.Body` instead of this method.
 func (rc *Ctx) PostBody() ([]byte, error) {
-	var err error
-	if len(rc.Body) == 0 {
-		if rc.Request != nil && rc.Request.Body != nil {
-			defer rc.Request.Body.Close()
-			rc.Body, err = ioutil.ReadAll(rc.Request.Body)
-		}
+	if rc.Request == nil || rc.Request.Body == nil {
+		return rc.Body, nil
+	}
+	if rc.Body == nil {
+		body, err := rc.readBody()
 		if err != nil {
-			return nil, ex.New(err)
+			return nil, err
 		}
+		rc.Body = body
 	}
+	rc.Request.Body = ioutil.NopCloser(bytes.NewReader(rc.Body))
 	return rc.Body, nil
 }
 
@@ -223,12 +306,18 @@ func (rc *Ctx) PostBodyAsString() (string, error) {
 }
 
 // PostBodyAsJSON reads the incoming post body (closing it) and marshals it to the target object as json.
+// Types registered with `RegisterBindType` are decoded with the cached decoder (see `DecodeJSON`).
 func (rc *Ctx) PostBodyAsJSON(response interface{}) error {
 	body, err := rc.PostBody()
 	if err != nil {
 		return err
 	}
-	if err = json.Unmarshal(body, response); err != nil {
+	if isBindType(response) {
+		err = DecodeJSON(body, response)
+	} else {
+		err = json.Unmarshal(body, response)
+	}
+	if err != nil {
 		return ex.New(err)
 	}
 	return nil
@@ -246,6 +335,16 @@ func (rc *Ctx) PostBodyAsXML(response interface{}) error {
 	return nil
 }
 
+// JSONCached returns a 200 json result that can be cached by clients and shared caches for the given duration.
+// It sets "Cache-Control: public, max-age=<seconds>" and the corresponding "Expires" header.
+func (rc *Ctx) JSONCached(response interface{}, maxAge time.Duration) Result {
+	return &JSONResult{
+		StatusCode: http.StatusOK,
+		Response:   response,
+		MaxAge:     maxAge,
+	}
+}
+
 // CookieDomain returns the cookie domain for a request.
 func (rc *Ctx) CookieDomain() string {
 	if rc.App != nil && rc.App.Config.BaseURL != "" {
@@ -271,6 +370,127 @@ func (rc *Ctx) WriteNewCookie(cookie *http.Cookie) {
 	http.SetCookie(rc.Response, cookie)
 }
 
+// SessionValue returns a value from the session state, or nil if there isn't a session.
+func (rc *Ctx) SessionValue(key string) interface{} {
+	if rc.Session == nil || rc.Session.State == nil {
+		return nil
+	}
+	return rc.Session.State[key]
+}
+
+// SetSessionValue sets a value on the session state and persists the session with the app's auth manager.
+// It returns `ErrSessionUnset` if the request doesn't have a session, e.g. from the `SessionAware` middleware.
+func (rc *Ctx) SetSessionValue(key string, value interface{}) error {
+	if rc.Session == nil {
+		return ex.New(ErrSessionUnset)
+	}
+	if rc.Session.State == nil {
+		rc.Session.State = make(map[string]interface{})
+	}
+	rc.Session.State[key] = value
+	if rc.App != nil && rc.App.Auth.PersistHandler != nil {
+		return rc.App.Auth.PersistHandler(rc.Context(), rc.Session)
+	}
+	return nil
+}
+
+// RegenerateSession moves the session to a new session id with the app's auth manager, e.g. on a privilege change.
+// It returns `ErrSessionUnset` if the request doesn't have a session.
+func (rc *Ctx) RegenerateSession() error {
+	if rc.App == nil || rc.Session == nil {
+		return ex.New(ErrSessionUnset)
+	}
+	_, err := rc.App.Auth.RegenerateSession(rc)
+	return err
+}
+
+// DestroySession logs the session out with the app's auth manager, removing it and expiring its cookie.
+// It returns `ErrSessionUnset` if the request doesn't have a session.
+func (rc *Ctx) DestroySession() error {
+	if rc.App == nil || rc.Session == nil {
+		return ex.New(ErrSessionUnset)
+	}
+	return rc.App.Auth.Logout(rc)
+}
+
+// Claims returns the verified jwt claims for the request, as set by the `JWTAuth` middleware.
+// It is nil if the middleware isn't applied to the route.
+func (rc *Ctx) Claims() *jwt.StandardClaims {
+	return rc.claims
+}
+
+// Redirect returns a result that redirects to a url with a given status code, e.g. `http.StatusSeeOther`.
+func (rc *Ctx) Redirect(url string, code int) Result {
+	return &RedirectResult{RedirectURI: url, StatusCode: code}
+}
+
+// RedirectRoute returns a result that redirects to a named route with its parameters substituted (see `App.Path(...)`).
+// The redirect has a 302 status code. It returns an internal error if the route name or a parameter is missing.
+func (rc *Ctx) RedirectRoute(routeName string, params map[string]string) Result {
+	if rc.App == nil {
+		return rc.DefaultProvider.InternalError(ex.New(ErrRouteNameUnknown, ex.OptMessagef("route name: %s", routeName)))
+	}
+	path, err := rc.App.Path(routeName, params)
+	if err != nil {
+		return rc.DefaultProvider.InternalError(err)
+	}
+	return rc.Redirect(path, http.StatusFound)
+}
+
+// OAuthRedirect returns a redirect to the oauth provider to start a login.
+// The state is returned from `OAuthCallback()` in `Result.State.RedirectURI`, e.g. to send the user back where they started.
+// It returns an internal error if the `OAuthAware` middleware isn't applied to the route.
+func (rc *Ctx) OAuthRedirect(state string) Result {
+	if rc.oauth == nil {
+		return rc.DefaultProvider.InternalError(ex.New(ErrOAuthUnset))
+	}
+	result, err := rc.oauth.redirect(rc, state)
+	if err != nil {
+		return rc.DefaultProvider.InternalError(err)
+	}
+	return result
+}
+
+// OAuthCallback finishes a login started with `OAuthRedirect()`.
+// It validates the state against the signed oauth cookie, exchanges the code and returns the result with the user profile.
+// It returns `ErrOAuthStateInvalid` if the state doesn't match, and `ErrOAuthUnset` if the `OAuthAware` middleware isn't applied to the route.
+func (rc *Ctx) OAuthCallback() (*oauth.Result, error) {
+	if rc.oauth == nil {
+		return nil, ex.New(ErrOAuthUnset)
+	}
+	return rc.oauth.callback(rc)
+}
+
+// CSRFToken returns the csrf token for the request, as issued by the `CSRF` middleware.
+// It is empty if the middleware isn't applied to the route.
+func (rc *Ctx) CSRFToken() string {
+	return rc.csrfToken
+}
+
+// RotateCSRFToken issues a new csrf token in the csrf cookie and returns it, e.g. on login.
+// Forms rendered after it's called should use the new token.
+// If the `CSRF` middleware isn't applied to the route, the default csrf config is used.
+func (rc *Ctx) RotateCSRFToken() (string, error) {
+	cfg := rc.csrf
+	if cfg == nil {
+		defaults := NewCSRFConfig()
+		cfg = &defaults
+	}
+	token, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	rc.WriteNewCookie(&http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    token,
+		Path:     cfg.CookiePath,
+		Secure:   cfg.Secure,
+		SameSite: cfg.SameSite,
+	})
+	rc.csrfToken = token
+	return token, nil
+}
+
 // ExtendCookieByDuration extends a cookie by a time duration (on the order of nanoseconds to hours).
 func (rc *Ctx) ExtendCookieByDuration(name string, path string, duration time.Duration) {
 	c := rc.Cookie(name)
@@ -318,11 +538,45 @@ func (rc *Ctx) ExpireCookie(name string, path string) {
 }
 
 // Elapsed is the time delta between start and end.
+// It uses the monotonic clock readings of the start and end times
+// and as a result will never be negative.
 func (rc *Ctx) Elapsed() time.Duration {
 	if !rc.RequestEnd.IsZero() {
-		return rc.RequestEnd.Sub(rc.RequestStart)
+		return logger.ElapsedBetween(rc.RequestStart, rc.RequestEnd)
+	}
+	return logger.ElapsedSince(rc.RequestStart)
+}
+
+// StartSpan starts timing a named phase of the request, e.g. "auth" or "db", and returns
+// a function that stops the span. The totals are included in the http response event as `spans`.
+func (rc *Ctx) StartSpan(name string) func() {
+	started := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			elapsed := time.Since(started)
+			rc.spansMu.Lock()
+			defer rc.spansMu.Unlock()
+			if rc.spans == nil {
+				rc.spans = make(map[string]time.Duration)
+			}
+			rc.spans[name] += elapsed
+		})
+	}
+}
+
+// Spans returns a copy of the total elapsed time for each span name recorded with `StartSpan`.
+func (rc *Ctx) Spans() map[string]time.Duration {
+	rc.spansMu.Lock()
+	defer rc.spansMu.Unlock()
+	if len(rc.spans) == 0 {
+		return nil
+	}
+	output := make(map[string]time.Duration, len(rc.spans))
+	for name, elapsed := range rc.spans {
+		output[name] = elapsed
 	}
-	return time.Now().UTC().Sub(rc.RequestStart)
+	return output
 }
 
 // --------------------------------------------------------------------------------
@@ -355,10 +609,38 @@ func (rc *Ctx) ensureForm() error {
 	return nil
 }
 
+// readBody reads and closes the request body, enforcing the app max body size if it's set.
+func (rc *Ctx) readBody() ([]byte, error) {
+	defer rc.Request.Body.Close()
+
+	var maxBytes int64
+	if rc.App != nil {
+		maxBytes = rc.App.Config.MaxBodyBytesOrDefault()
+	}
+	if maxBytes <= 0 {
+		body, err := ioutil.ReadAll(rc.Request.Body)
+		if err != nil {
+			return nil, ex.New(err)
+		}
+		return body, nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(rc.Request.Body, maxBytes+1))
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, ex.New(ErrBodyTooLarge, ex.OptMessagef("max bytes: %d", maxBytes))
+	}
+	return body, nil
+}
+
 func (rc *Ctx) onRequestStart() {
-	rc.RequestStart = time.Now().UTC()
+	rc.RequestStart = time.Now()
 }
 
 func (rc *Ctx) onRequestFinish() {
-	rc.RequestEnd = time.Now().UTC()
+	rc.RequestEnd = time.Now()
+	rc.cacheMu.Lock()
+	rc.cache = nil
+	rc.cacheMu.Unlock()
 }
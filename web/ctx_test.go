@@ -1,11 +1,19 @@
 package web
 
 import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/r2"
 	"github.com/blend/go-sdk/webutil"
 )
 
@@ -17,6 +25,94 @@ func TestCtxGetState(t *testing.T) {
 	assert.Equal("bar", context.StateValue("foo"))
 }
 
+func TestCtxContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NotNil(NewCtx(nil, nil).Context())
+
+	rc := MockCtx("GET", "/")
+	_, ok := rc.Deadline()
+	assert.False(ok)
+	assert.False(rc.IsCancelled())
+
+	rc.WithContext(WithRequestID(rc.Context(), "request-1234"))
+	assert.Equal("request-1234", GetRequestID(rc.Context()), "the context should include values set on the ctx")
+
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(rc.Context(), deadline)
+	rc.WithContext(ctx)
+	actual, ok := rc.Deadline()
+	assert.True(ok)
+	assert.Equal(deadline, actual)
+	assert.Equal("request-1234", GetRequestID(rc.Context()))
+
+	cancel()
+	assert.True(rc.IsCancelled())
+}
+
+func TestCtxDetachedContext(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	requestContext, cancel := context.WithTimeout(WithRequestID(rc.Context(), "request-1234"), time.Minute)
+	requestContext = logger.WithSubContextMeta(requestContext, []string{"handler"}, logger.Fields{"user": "bailey"})
+	rc.WithContext(requestContext)
+
+	detached := rc.DetachedContext()
+	cancel()
+	assert.True(rc.IsCancelled())
+
+	_, ok := detached.Deadline()
+	assert.False(ok)
+	assert.Nil(detached.Done(), "the detached context should never be cancelled")
+	assert.Nil(detached.Err())
+	assert.Equal("request-1234", GetRequestID(detached))
+	path, fields := logger.GetSubContextMeta(detached)
+	assert.Equal([]string{"handler"}, path)
+	assert.Equal("bailey", fields["user"])
+
+	// derived contexts can still be cancelled on their own.
+	child, cancelChild := context.WithCancel(detached)
+	cancelChild()
+	assert.NotNil(child.Err())
+	assert.Equal("request-1234", GetRequestID(child))
+}
+
+func TestCtxCache(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	_, ok := rc.CacheGet("foo")
+	assert.False(ok)
+
+	rc.CacheSet("foo", "bar")
+	rc.CacheSet("nil", nil)
+	value, ok := rc.CacheGet("foo")
+	assert.True(ok)
+	assert.Equal("bar", value)
+	value, ok = rc.CacheGet("nil")
+	assert.True(ok, "nil values should still be reported as set")
+	assert.Nil(value)
+
+	var wg sync.WaitGroup
+	for index := 0; index < 8; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			rc.CacheSet(strconv.Itoa(index), index)
+			rc.CacheGet("foo")
+		}(index)
+	}
+	wg.Wait()
+	value, ok = rc.CacheGet("7")
+	assert.True(ok)
+	assert.Equal(7, value)
+
+	rc.onRequestFinish()
+	_, ok = rc.CacheGet("foo")
+	assert.False(ok, "the cache should be cleared when the request finishes")
+}
+
 func TestCtxParamQuery(t *testing.T) {
 	assert := assert.New(t)
 
@@ -79,6 +175,43 @@ func TestCtxPostBodyAsString(t *testing.T) {
 	assert.Empty(body)
 }
 
+func TestCtxPostBodyCached(t *testing.T) {
+	assert := assert.New(t)
+
+	context := MockCtx("POST", "/", OptCtxBodyBytes([]byte(`{"foo":"bar"}`)))
+	first, err := context.PostBody()
+	assert.Nil(err)
+	second, err := context.PostBody()
+	assert.Nil(err)
+	assert.Equal(string(first), string(second))
+
+	// the request body should still be readable by downstream consumers.
+	var contents map[string]string
+	assert.Nil(json.NewDecoder(context.Request.Body).Decode(&contents))
+	assert.Equal("bar", contents["foo"])
+
+	// and again after it's been consumed.
+	_, err = context.PostBody()
+	assert.Nil(err)
+	raw, err := ioutil.ReadAll(context.Request.Body)
+	assert.Nil(err)
+	assert.Equal(`{"foo":"bar"}`, string(raw))
+}
+
+func TestCtxPostBodyMaxBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptMaxBodyBytes(4))
+	context := MockCtx("POST", "/", OptCtxApp(app), OptCtxBodyBytes([]byte("test payload")))
+	_, err := context.PostBody()
+	assert.True(ex.Is(err, ErrBodyTooLarge))
+
+	context = MockCtx("POST", "/", OptCtxApp(app), OptCtxBodyBytes([]byte("test")))
+	body, err := context.PostBody()
+	assert.Nil(err)
+	assert.Equal("test", string(body))
+}
+
 func TestCtxPostBodyAsJSON(t *testing.T) {
 	assert := assert.New(t)
 
@@ -185,3 +318,62 @@ func TestCtxExtendCookieByDuration(t *testing.T) {
 	cookie := cookies[0]
 	assert.False(cookie.Expires.IsZero())
 }
+
+func TestCtxStartSpan(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	assert.Nil(rc.Spans())
+
+	stopOuter := rc.StartSpan("outer")
+	stopInner := rc.StartSpan("inner")
+	time.Sleep(time.Millisecond)
+	stopInner()
+	stopInner()
+	stopOuter()
+
+	spans := rc.Spans()
+	assert.Len(spans, 2)
+	assert.True(spans["inner"] >= time.Millisecond)
+	assert.True(spans["outer"] >= spans["inner"], "nested spans should be allowed")
+	assert.Equal(spans["inner"], rc.Spans()["inner"], "stopping a span more than once should only record it once")
+
+	rc.StartSpan("inner")()
+	assert.True(rc.Spans()["inner"] >= spans["inner"], "spans with the same name should accumulate")
+}
+
+func TestCtxRedirect(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.POST("/users", func(r *Ctx) Result {
+		return r.Redirect("/users/1234", http.StatusSeeOther)
+	})
+
+	meta, err := MockPost(app, "/users", nil, r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusSeeOther, meta.StatusCode)
+	assert.Equal("/users/1234", meta.Header.Get("Location"))
+}
+
+func TestCtxRedirectRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/users/:id/posts/:post", func(_ *Ctx) Result { return NoContent }).Name("user.post")
+	app.GET("/", func(r *Ctx) Result {
+		return r.RedirectRoute("user.post", map[string]string{"id": "1234", "post": "hello world"})
+	})
+	app.GET("/missing", func(r *Ctx) Result {
+		return r.RedirectRoute("user.post", map[string]string{"id": "1234"})
+	})
+
+	meta, err := MockGet(app, "/", r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusFound, meta.StatusCode)
+	assert.Equal("/users/1234/posts/hello%20world", meta.Header.Get("Location"))
+
+	meta, err = MockGet(app, "/missing", r2.OptNoFollow()).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, meta.StatusCode)
+}
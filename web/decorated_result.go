@@ -0,0 +1,50 @@
+package web
+
+// these are compile time assertions
+var (
+	_ ResultPreRender  = (*DecoratedResult)(nil)
+	_ ResultPostRender = (*DecoratedResult)(nil)
+)
+
+// Decorate returns a result that calls hooks before and after an inner result renders.
+func Decorate(inner Result, before, after func(*Ctx)) *DecoratedResult {
+	return &DecoratedResult{Inner: inner, Before: before, After: after}
+}
+
+// DecoratedResult wraps another result with hooks, e.g. for cross cutting response headers in a middleware.
+// `Before` is called before the inner result renders, and `After` once it has rendered.
+type DecoratedResult struct {
+	Inner  Result
+	Before func(*Ctx)
+	After  func(*Ctx)
+}
+
+// PreRender calls the inner result's pre render step.
+func (dr *DecoratedResult) PreRender(ctx *Ctx) error {
+	if typed, ok := dr.Inner.(ResultPreRender); ok {
+		return typed.PreRender(ctx)
+	}
+	return nil
+}
+
+// Render calls the before hook, renders the inner result, then calls the after hook.
+func (dr *DecoratedResult) Render(ctx *Ctx) (err error) {
+	if dr.Before != nil {
+		dr.Before(ctx)
+	}
+	if dr.Inner != nil {
+		err = dr.Inner.Render(ctx)
+	}
+	if dr.After != nil {
+		dr.After(ctx)
+	}
+	return
+}
+
+// PostRender calls the inner result's post render step.
+func (dr *DecoratedResult) PostRender(ctx *Ctx) error {
+	if typed, ok := dr.Inner.(ResultPostRender); ok {
+		return typed.PostRender(ctx)
+	}
+	return nil
+}
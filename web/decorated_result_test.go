@@ -0,0 +1,69 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type decoratedResultTestInner struct {
+	calls *[]string
+}
+
+func (dri decoratedResultTestInner) PreRender(_ *Ctx) error {
+	*dri.calls = append(*dri.calls, "pre render")
+	return nil
+}
+
+func (dri decoratedResultTestInner) Render(ctx *Ctx) error {
+	*dri.calls = append(*dri.calls, "render")
+	ctx.Response.WriteHeader(http.StatusAccepted)
+	return fmt.Errorf("only a test")
+}
+
+func TestDecoratedResult(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return Decorate(decoratedResultTestInner{calls: &calls}, func(r *Ctx) {
+			calls = append(calls, "before")
+			r.Response.Header().Set("X-Decorated", "true")
+		}, func(r *Ctx) {
+			calls = append(calls, fmt.Sprintf("after %d", r.Response.StatusCode()))
+		})
+	})
+
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusAccepted, meta.StatusCode)
+	assert.Equal("true", meta.Header.Get("X-Decorated"), "the before hook should run before the status is written")
+	assert.Equal([]string{"pre render", "before", "render", "after 202"}, calls)
+
+	rc := MockCtx("GET", "/")
+	assert.Nil(Decorate(nil, nil, nil).Render(rc))
+	assert.Nil(Decorate(NoContent, nil, nil).PreRender(rc))
+}
+
+func TestDecoratedResultError(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.ErrorAction = func(_ *Ctx, err error) Result {
+		return Text.Status(http.StatusTeapot, err.Error())
+	}
+	app.GET("/", func(_ *Ctx) Result {
+		return Decorate(NotFound("user not found"), func(r *Ctx) {
+			r.Response.Header().Set("X-Decorated", "true")
+		}, nil)
+	})
+
+	contents, meta, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusTeapot, meta.StatusCode, "decorated errors should be handled by the error action")
+	assert.Equal("true", meta.Header.Get("X-Decorated"))
+	assert.Equal("user not found", string(contents))
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// Deprecate sets the `Deprecation` and `Sunset` headers, and logs a `logger.Deprecation` event.
+// Call it before the result is rendered, as headers set after the status is written are ignored.
+func (rc *Ctx) Deprecate(message string, sunset time.Time) {
+	if rc.Response != nil {
+		rc.Response.Header().Set(HeaderDeprecation, "true")
+		if !sunset.IsZero() {
+			rc.Response.Header().Set(HeaderSunset, sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+	var route string
+	if rc.Route != nil {
+		route = rc.Route.String()
+	}
+	var clientIP string
+	if realIP := rc.RealIP(); realIP != nil {
+		clientIP = realIP.String()
+	}
+	rc.Logger().Trigger(rc.Context(), logger.NewDeprecationEvent(rc.Request, message,
+		logger.OptDeprecationRoute(route),
+		logger.OptDeprecationSunset(sunset),
+		logger.OptDeprecationClientIP(clientIP),
+	))
+}
@@ -0,0 +1,56 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestCtxDeprecate(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.MustNew(logger.OptEnabled(logger.Deprecation), logger.OptOutput(buffer), logger.OptText(logger.OptTextNoColor(), logger.OptTextHideTimestamp()))
+	app := MustNew(OptLog(log))
+	sunset := time.Date(2021, 06, 01, 0, 0, 0, 0, time.UTC)
+	app.GET("/v1/users/:id", func(r *Ctx) Result {
+		r.Deprecate("use /v2/users", sunset)
+		return NoContent
+	})
+
+	meta, err := MockGet(app, "/v1/users/1234").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Equal("true", meta.Header.Get(HeaderDeprecation))
+	assert.Equal("Tue, 01 Jun 2021 00:00:00 GMT", meta.Header.Get(HeaderSunset))
+	assert.Nil(log.Drain())
+
+	assert.Contains(buffer.String(), "["+logger.Deprecation+"]")
+	assert.Contains(buffer.String(), "GET /v1/users/1234 use /v2/users sunset 2021-06-01T00:00:00Z")
+
+	var deprecations []*logger.DeprecationEvent
+	log.Tap(logger.Deprecation, func(e logger.Event) {
+		deprecations = append(deprecations, e.(*logger.DeprecationEvent))
+	})
+	_, err = MockGet(app, "/v1/users/5678", r2.OptHeaderValue(webutil.HeaderXForwardedFor, "10.1.2.3")).Discard()
+	assert.Nil(err)
+	assert.Len(deprecations, 1)
+	assert.Equal("/v1/users/:id", deprecations[0].Route)
+	assert.Equal("use /v2/users", deprecations[0].Message)
+	assert.Equal("127.0.0.1", deprecations[0].ClientIP, "forwarding headers from untrusted peers should be ignored")
+}
+
+func TestCtxDeprecateWithoutSunset(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	rc.Deprecate("going away", time.Time{})
+	assert.Equal("true", rc.Response.Header().Get(HeaderDeprecation))
+	assert.Empty(rc.Response.Header().Get(HeaderSunset))
+}
@@ -0,0 +1,45 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// DescribeJSONError returns a user friendly description of a json decode error, with its line and column.
+// Errors that are not json decode errors are returned as is.
+func DescribeJSONError(err error, body []byte) string {
+	if err == nil {
+		return ""
+	}
+	switch typed := ex.ErrClass(err).(type) {
+	case *json.SyntaxError:
+		line, column := jsonErrorPosition(body, typed.Offset)
+		return fmt.Sprintf("invalid json at line %d, column %d: %s", line, column, typed.Error())
+	case *json.UnmarshalTypeError:
+		line, column := jsonErrorPosition(body, typed.Offset)
+		if typed.Field != "" {
+			return fmt.Sprintf("invalid value for field %q at line %d, column %d: expected %v, got %s", typed.Field, line, column, typed.Type, typed.Value)
+		}
+		return fmt.Sprintf("invalid value at line %d, column %d: expected %v, got %s", line, column, typed.Type, typed.Value)
+	default:
+		return err.Error()
+	}
+}
+
+// jsonErrorPosition returns the (1 based) line and column of the
+// character that precedes a given offset.
+func jsonErrorPosition(body []byte, offset int64) (line, column int) {
+	if offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+	if offset < 1 {
+		return 1, 1
+	}
+	preceding := body[:offset-1]
+	line = bytes.Count(preceding, []byte("\n")) + 1
+	column = len(preceding) - bytes.LastIndexByte(preceding, '\n')
+	return
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestDescribeJSONErrorSyntax(t *testing.T) {
+	assert := assert.New(t)
+
+	body := []byte("{\n\t\"foo\": \"bar\",\n\t\"baz\" 1\n}")
+	var output map[string]interface{}
+	err := json.Unmarshal(body, &output)
+	assert.NotNil(err)
+
+	assert.Equal("invalid json at line 3, column 8: invalid character '1' after object key", DescribeJSONError(err, body))
+	assert.Equal("invalid json at line 3, column 8: invalid character '1' after object key", DescribeJSONError(ex.New(err), body), "it should unwrap exceptions")
+}
+
+func TestDescribeJSONErrorUnexpectedEnd(t *testing.T) {
+	assert := assert.New(t)
+
+	body := []byte(`{"foo":`)
+	var output map[string]interface{}
+	err := json.Unmarshal(body, &output)
+	assert.NotNil(err)
+	assert.Equal("unexpected end of JSON input", err.Error())
+	assert.Contains(DescribeJSONError(err, body), "unexpected end of JSON input")
+}
+
+func TestDescribeJSONErrorType(t *testing.T) {
+	assert := assert.New(t)
+
+	type inner struct {
+		Count int `json:"count"`
+	}
+	type outer struct {
+		Name  string `json:"name"`
+		Inner inner  `json:"inner"`
+	}
+
+	body := []byte("{\n\"name\": \"foo\",\n\"inner\": {\"count\": \"bar\"}\n}")
+	var output outer
+	err := json.Unmarshal(body, &output)
+	assert.NotNil(err)
+	assert.Equal(`invalid value for field "inner.count" at line 3, column 24: expected int, got string`, DescribeJSONError(err, body))
+
+	var number int
+	err = json.Unmarshal([]byte(`"foo"`), &number)
+	assert.NotNil(err)
+	assert.Equal(`invalid value at line 1, column 5: expected int, got string`, DescribeJSONError(err, []byte(`"foo"`)))
+}
+
+func TestDescribeJSONErrorOther(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(DescribeJSONError(nil, nil))
+	assert.Equal("not json", DescribeJSONError(fmt.Errorf("not json"), nil))
+}
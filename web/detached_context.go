@@ -0,0 +1,21 @@
+package web
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	_ context.Context = (*detachedContext)(nil)
+)
+
+// detachedContext is a context that has the values of a parent context,
+// but not its deadline or cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) { return }
+func (detachedContext) Done() <-chan struct{}                   { return nil }
+func (detachedContext) Err() error                              { return nil }
+func (dc detachedContext) Value(key interface{}) interface{}    { return dc.parent.Value(key) }
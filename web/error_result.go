@@ -0,0 +1,47 @@
+package web
+
+// these are compile time assertions
+var (
+	_ error  = (*ErrorResult)(nil)
+	_ Result = (*ErrorResult)(nil)
+)
+
+// NewErrorResult returns a result for an error that is translated into a response by the app error handler.
+func NewErrorResult(err error) *ErrorResult {
+	return &ErrorResult{Err: err}
+}
+
+// ErrorResult is a result for an error returned by an action.
+type ErrorResult struct {
+	Err error
+}
+
+// Error implements error.
+func (er *ErrorResult) Error() string {
+	if er.Err == nil {
+		return ""
+	}
+	return er.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (er *ErrorResult) Unwrap() error {
+	return er.Err
+}
+
+// Render renders the error with the default error handler.
+// It is only called if the result is rendered outside of an app.
+func (er *ErrorResult) Render(ctx *Ctx) error {
+	return DefaultErrorHandler(ctx, er.Err).Render(ctx)
+}
+
+// DefaultErrorHandler renders `*APIError`s as problem details, and other errors as a json 500.
+func DefaultErrorHandler(_ *Ctx, err error) Result {
+	if typed, ok := AsAPIError(err); ok {
+		if typed.Status >= 500 {
+			return ResultWithLoggedError(typed, err)
+		}
+		return typed
+	}
+	return JSON.InternalError(err)
+}
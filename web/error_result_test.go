@@ -0,0 +1,78 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+var errTestNoRows = errors.New("no rows in result set")
+
+func TestAppErrorHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	var handled []error
+	app := MustNew(OptErrorHandler(func(r *Ctx, err error) Result {
+		handled = append(handled, err)
+		if ex.Is(ex.ErrInner(err), errTestNoRows) {
+			return NotFound("not found")
+		}
+		return nil
+	}))
+	app.GET("/missing", func(_ *Ctx) Result {
+		return NewErrorResult(ex.New("fetching user", ex.OptInner(errTestNoRows)))
+	})
+	app.GET("/broken", func(_ *Ctx) Result {
+		return NewErrorResult(fmt.Errorf("something broke"))
+	})
+	app.GET("/conflict", func(_ *Ctx) Result {
+		return Conflict("already exists")
+	})
+
+	contents, meta, err := MockGet(app, "/missing").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+	assert.Equal(ContentTypeApplicationProblemJSON, meta.Header.Get(HeaderContentType))
+	assert.Contains(string(contents), `"detail":"not found"`)
+	assert.Len(handled, 1)
+	assert.True(ex.Is(ex.ErrInner(handled[0]), errTestNoRows), "the handler should receive the underlying error")
+
+	meta, err = MockGet(app, "/broken").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, meta.StatusCode, "a nil result should fall back to the default handler")
+	assert.Equal(ContentTypeApplicationJSON, meta.Header.Get(HeaderContentType))
+
+	meta, err = MockGet(app, "/conflict").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusConflict, meta.StatusCode)
+	assert.Len(handled, 3, "api errors should also be passed to the handler")
+}
+
+func TestAppDefaultErrorHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	logged := make(chan error, 1)
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(nil))
+	defer log.Close()
+	log.Listen(logger.Fatal, "test", logger.NewErrorEventListener(func(_ context.Context, e *logger.ErrorEvent) {
+		logged <- e.Err
+	}))
+
+	app := MustNew(OptLog(log))
+	app.GET("/", func(_ *Ctx) Result {
+		return NewErrorResult(fmt.Errorf("something broke"))
+	})
+
+	contents, meta, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, meta.StatusCode)
+	assert.Equal(ContentTypeApplicationJSON, meta.Header.Get(HeaderContentType))
+	assert.Contains(string(contents), "something broke")
+	assert.Contains((<-logged).Error(), "something broke", "the error should be logged")
+}
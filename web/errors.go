@@ -14,6 +14,60 @@ const (
 	ErrUnsetViewTemplate ex.Class = "view result template is unset"
 	// ErrParameterMissing is an error on request validation.
 	ErrParameterMissing ex.Class = "parameter is missing"
+	// ErrRequestTimeout is the class of errors for requests that timed out waiting for the client to send the request body.
+	ErrRequestTimeout ex.Class = "request timeout"
+	// ErrHandlerTimeout is the class of errors for requests that timed out waiting for the action to return.
+	ErrHandlerTimeout ex.Class = "handler timeout"
+	// ErrInvalidTimeout is an error returned when parsing a malformed or non-positive timeout.
+	ErrInvalidTimeout ex.Class = "invalid timeout"
+	// ErrBodyTooLarge is an error returned when a request body exceeds the max body size.
+	ErrBodyTooLarge ex.Class = "request body too large"
+	// ErrInvalidSignature is an error returned when a request signature is missing, stale or doesn't match.
+	ErrInvalidSignature ex.Class = "invalid request signature"
+	// ErrPprofAuthUnset is an error returned when enabling the pprof routes without an auth middleware.
+	ErrPprofAuthUnset ex.Class = "pprof auth middleware is unset"
+	// ErrViewTemplateUndefined is an error returned when parsing views that include a template that isn't defined.
+	ErrViewTemplateUndefined ex.Class = "view includes an undefined template"
+	// ErrCSRFTokenInvalid is an error returned when an unsafe request has a missing or mismatched csrf token.
+	ErrCSRFTokenInvalid ex.Class = "csrf token missing or invalid"
+	// ErrSessionUnset is an error returned when using the session helpers on a request without a session.
+	ErrSessionUnset ex.Class = "session is unset"
+	// ErrJWTValidMethodsUnset is an error returned by `JWTAuth` when the valid signing methods aren't set.
+	ErrJWTValidMethodsUnset ex.Class = "jwt auth valid methods unset; use `OptJWTAuthValidMethods(...)`"
+	// ErrJWTBearerTokenMissing is an error returned by the `JWTAuth` middleware when a request doesn't have a bearer token.
+	ErrJWTBearerTokenMissing ex.Class = "jwt bearer token missing"
+	// ErrJWTInvalid is an error returned by the `JWTAuth` middleware when a bearer token is malformed or its signature doesn't verify.
+	ErrJWTInvalid ex.Class = "jwt invalid"
+	// ErrJWTExpired is an error returned by the `JWTAuth` middleware when a bearer token is expired.
+	ErrJWTExpired ex.Class = "jwt expired"
+	// ErrJWTExpirationMissing is an error returned by the `JWTAuth` middleware when a bearer token has no expiration.
+	ErrJWTExpirationMissing ex.Class = "jwt expiration missing"
+	// ErrJWTNotYetValid is an error returned by the `JWTAuth` middleware when a bearer token is used before its not before or issued at time.
+	ErrJWTNotYetValid ex.Class = "jwt not yet valid"
+	// ErrJWTIssuerInvalid is an error returned by the `JWTAuth` middleware when a bearer token has the wrong issuer.
+	ErrJWTIssuerInvalid ex.Class = "jwt issuer invalid"
+	// ErrJWTAudienceInvalid is an error returned by the `JWTAuth` middleware when a bearer token has the wrong audience.
+	ErrJWTAudienceInvalid ex.Class = "jwt audience invalid"
+	// ErrOAuthUnset is an error returned when using the oauth helpers on a route without the `OAuthAware` middleware.
+	ErrOAuthUnset ex.Class = "oauth middleware is unset"
+	// ErrOAuthStateInvalid is an error returned when an oauth callback's state doesn't match the signed oauth cookie.
+	ErrOAuthStateInvalid ex.Class = "oauth state missing or invalid"
+	// ErrStreamJSONDecode is an error returned by `Ctx.StreamJSON` when an item in the request body can't be decoded.
+	ErrStreamJSONDecode ex.Class = "json stream decode failed"
+	// ErrInheritedListenerInvalid is an error returned when the inherited listener env vars or fd are malformed.
+	ErrInheritedListenerInvalid ex.Class = "inherited listener invalid"
+	// ErrRouteNameUnknown is an error returned when building the path of a route name that isn't registered.
+	ErrRouteNameUnknown ex.Class = "route name unknown"
+	// ErrRouteParamMissing is an error returned when building the path of a route without a value for one of its parameters.
+	ErrRouteParamMissing ex.Class = "route parameter missing"
+	// ErrSlowRequest is the class of the warnings logged for requests slower than the access log slow threshold.
+	ErrSlowRequest ex.Class = "slow request"
+	// ErrFilePathInvalid is an error returned when a file result's path has `..` elements.
+	ErrFilePathInvalid ex.Class = "file path invalid"
+	// ErrMaxConcurrentLimitInvalid is an error returned by `MaxConcurrent` when the limit isn't positive.
+	ErrMaxConcurrentLimitInvalid ex.Class = "max concurrent limit must be positive"
+	// ErrPanic is the class of errors logged for panics recovered while handling a request.
+	ErrPanic ex.Class = "panic recovered"
 )
 
 // NewParameterMissingError returns a new parameter missing error.
@@ -0,0 +1,121 @@
+package web
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ServeFile returns a result that streams a file under a root directory from disk, with support for resuming
+// downloads through range requests. See `FileResult`.
+func (rc *Ctx) ServeFile(root, filePath string) Result {
+	return &FileResult{Root: root, FilePath: filePath}
+}
+
+// FileResult streams a single file from disk with support for range and conditional requests.
+// Paths containing `..` are rejected; if `Root` is unset, absolute paths are also rejected.
+type FileResult struct {
+	FilePath string
+	// Root is an optional directory the file path is relative to.
+	Root string
+	// DownloadName, if set, adds a `Content-Disposition: attachment` header with the name, so browsers save the file.
+	DownloadName string
+	// Headers are optional headers set on the response before the status is written.
+	Headers http.Header
+}
+
+// Render renders the result.
+func (fr *FileResult) Render(ctx *Ctx) error {
+	filePath, err := fr.resolve()
+	if err != nil {
+		return fr.renderStatus(ctx, http.StatusBadRequest, err)
+	}
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return fr.renderStatus(ctx, http.StatusNotFound, nil)
+	}
+	if err != nil {
+		return fr.renderStatus(ctx, http.StatusInternalServerError, ex.New(err))
+	}
+	defer f.Close()
+
+	finfo, err := f.Stat()
+	if err != nil {
+		return fr.renderStatus(ctx, http.StatusInternalServerError, ex.New(err))
+	}
+	if finfo.IsDir() {
+		return fr.renderStatus(ctx, http.StatusNotFound, nil)
+	}
+
+	writeResultHeaders(ctx, fr.Headers)
+	if fr.DownloadName != "" {
+		ctx.Response.Header().Set(HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": fr.DownloadName}))
+	}
+	name := finfo.Name()
+	if fr.DownloadName != "" {
+		name = fr.DownloadName
+	}
+	http.ServeContent(ctx.Response, ctx.Request, name, finfo.ModTime(), f)
+	return nil
+}
+
+// resolve returns the path of the file on disk, or an error if the file path would traverse outside its directory.
+func (fr *FileResult) resolve() (string, error) {
+	if containsDotDot(fr.FilePath) {
+		return "", ex.New(ErrFilePathInvalid, ex.OptMessagef("path: %s", fr.FilePath))
+	}
+	if fr.Root == "" {
+		if isAbsolutePath(fr.FilePath) {
+			return "", ex.New(ErrFilePathInvalid, ex.OptMessagef("path: %s", fr.FilePath))
+		}
+		return fr.FilePath, nil
+	}
+	return filepath.Join(fr.Root, filepath.FromSlash(path.Clean("/"+fr.FilePath))), nil
+}
+
+// renderStatus renders a status result with the default provider, and returns the error to be logged.
+// Not found and bad request errors are the client's, so they aren't returned.
+func (fr *FileResult) renderStatus(ctx *Ctx, statusCode int, err error) error {
+	var result Result
+	if ctx.DefaultProvider != nil {
+		switch statusCode {
+		case http.StatusNotFound:
+			result = ctx.DefaultProvider.NotFound()
+		case http.StatusBadRequest:
+			result = ctx.DefaultProvider.BadRequest(err)
+		default:
+			result = ctx.DefaultProvider.InternalError(err)
+		}
+	}
+	if result != nil {
+		if renderErr := result.Render(ctx); renderErr != nil {
+			return renderErr
+		}
+	} else {
+		http.Error(ctx.Response, http.StatusText(statusCode), statusCode)
+	}
+	if statusCode == http.StatusInternalServerError {
+		return err
+	}
+	return nil
+}
+
+// containsDotDot returns if a slash or backslash separated path has a `..` element.
+func containsDotDot(filePath string) bool {
+	for _, element := range strings.FieldsFunc(filePath, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if element == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// isAbsolutePath returns if a path is absolute, or starts with a slash or backslash, e.g. a windows path without a volume.
+func isAbsolutePath(filePath string) bool {
+	return filepath.IsAbs(filePath) || strings.HasPrefix(filePath, "/") || strings.HasPrefix(filePath, "\\")
+}
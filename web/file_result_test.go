@@ -0,0 +1,114 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func fileResultTestApp(t *testing.T) (*App, string) {
+	root, err := ioutil.TempDir("", "file_result")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(root, "data.txt"), []byte("0123456789abcdefghij"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := MustNew()
+	app.GET("/download/*filepath", func(r *Ctx) Result {
+		return &FileResult{Root: root, FilePath: StringValue(r.RouteParam("filepath")), DownloadName: "data export.txt"}
+	})
+	app.GET("/file", func(r *Ctx) Result {
+		return r.ServeFile(root, StringValue(r.QueryValue("f")))
+	})
+	return app, root
+}
+
+func TestFileResult(t *testing.T) {
+	assert := assert.New(t)
+
+	app, root := fileResultTestApp(t)
+	defer os.RemoveAll(root)
+
+	contents, meta, err := MockGet(app, "/download/data.txt").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("0123456789abcdefghij", string(contents))
+	assert.Equal("bytes", meta.Header.Get("Accept-Ranges"))
+	assert.Equal(ContentTypeText, meta.Header.Get(HeaderContentType))
+	assert.NotEmpty(meta.Header.Get("Last-Modified"))
+	assert.Equal(`attachment; filename="data export.txt"`, meta.Header.Get(HeaderContentDisposition))
+
+	contents, meta, err = MockGet(app, "/file", r2.OptQueryValue("f", "data.txt")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("0123456789abcdefghij", string(contents))
+	assert.Empty(meta.Header.Get(HeaderContentDisposition))
+}
+
+func TestFileResultRange(t *testing.T) {
+	assert := assert.New(t)
+
+	app, root := fileResultTestApp(t)
+	defer os.RemoveAll(root)
+
+	contents, meta, err := MockGet(app, "/download/data.txt", r2.OptHeaderValue("Range", "bytes=5-9")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusPartialContent, meta.StatusCode)
+	assert.Equal("56789", string(contents))
+	assert.Equal("bytes 5-9/20", meta.Header.Get("Content-Range"))
+	assert.Equal("5", meta.Header.Get(HeaderContentLength))
+
+	// resuming from an offset.
+	contents, meta, err = MockGet(app, "/download/data.txt", r2.OptHeaderValue("Range", "bytes=15-")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusPartialContent, meta.StatusCode)
+	assert.Equal("fghij", string(contents))
+
+	// the range is ignored if the file changed since the client started the download.
+	lastModified := time.Now().UTC().Add(-time.Hour).Format(http.TimeFormat)
+	contents, meta, err = MockGet(app, "/download/data.txt",
+		r2.OptHeaderValue("Range", "bytes=5-9"),
+		r2.OptHeaderValue("If-Range", lastModified),
+	).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("0123456789abcdefghij", string(contents))
+
+	_, meta, err = MockGet(app, "/download/data.txt", r2.OptHeaderValue("Range", "bytes=50-60")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestedRangeNotSatisfiable, meta.StatusCode)
+}
+
+func TestFileResultInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	app, root := fileResultTestApp(t)
+	defer os.RemoveAll(root)
+
+	meta, err := MockGet(app, "/download/missing.txt").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+
+	rc := MockCtx("GET", "/")
+	assert.Nil(rc.ServeFile(root, "").Render(rc))
+	assert.Equal(http.StatusNotFound, rc.Response.StatusCode(), "directories should not be served")
+
+	rc = MockCtx("GET", "/")
+	assert.Nil((&FileResult{Root: root, FilePath: "../" + filepath.Base(root) + "/data.txt"}).Render(rc))
+	assert.Equal(http.StatusBadRequest, rc.Response.StatusCode())
+
+	rc = MockCtx("GET", "/")
+	assert.Nil((&FileResult{FilePath: filepath.Join(root, "data.txt")}).Render(rc))
+	assert.Equal(http.StatusBadRequest, rc.Response.StatusCode(), "absolute paths should be rejected without a root")
+
+	meta, err = MockGet(app, "/file", r2.OptQueryValue("f", "/etc/passwd")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode, "absolute paths should be resolved relative to the root")
+}
@@ -0,0 +1,74 @@
+package web
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// IPFilterOption mutates an ip filter config.
+type IPFilterOption func(*IPFilterConfig)
+
+// OptIPFilterTrustedProxies sets the proxies whose forwarding headers are trusted
+// when determining the client ip, instead of the app trusted proxies used by `Ctx.RealIP()`.
+func OptIPFilterTrustedProxies(trustedProxies *webutil.IPAllowlist) IPFilterOption {
+	return func(ipf *IPFilterConfig) { ipf.TrustedProxies = trustedProxies }
+}
+
+// OptIPFilterForbidden sets the action called for requests that are denied.
+func OptIPFilterForbidden(forbidden Action) IPFilterOption {
+	return func(ipf *IPFilterConfig) { ipf.Forbidden = forbidden }
+}
+
+// IPFilterConfig is the configuration for the ip filter middleware.
+type IPFilterConfig struct {
+	Allow          *webutil.IPAllowlist
+	Deny           *webutil.IPAllowlist
+	TrustedProxies *webutil.IPAllowlist
+	Forbidden      Action
+}
+
+// Allowed returns if a request is allowed by the filter.
+func (ipf IPFilterConfig) Allowed(r *http.Request) bool {
+	return ipf.allowed(webutil.GetClientIP(r, ipf.TrustedProxies))
+}
+
+func (ipf IPFilterConfig) allowed(clientIP net.IP) bool {
+	if clientIP == nil {
+		return false
+	}
+	if ipf.Deny.Contains(clientIP) {
+		return false
+	}
+	if !ipf.Allow.IsEmpty() && !ipf.Allow.Contains(clientIP) {
+		return false
+	}
+	return true
+}
+
+// IPFilter returns a middleware that denies requests from the deny list, or not in a non-empty allow list.
+func IPFilter(allow, deny *webutil.IPAllowlist, options ...IPFilterOption) Middleware {
+	cfg := IPFilterConfig{
+		Allow: allow,
+		Deny:  deny,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			clientIP := ctx.RealIP()
+			if cfg.TrustedProxies != nil {
+				clientIP = webutil.GetClientIP(ctx.Request, cfg.TrustedProxies)
+			}
+			if !cfg.allowed(clientIP) {
+				if cfg.Forbidden != nil {
+					return cfg.Forbidden(ctx)
+				}
+				return ctx.DefaultProvider.Status(http.StatusForbidden)
+			}
+			return action(ctx)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func ipFilterStatus(app *App, path, forwardedFor string) int {
+	var options []r2.Option
+	if forwardedFor != "" {
+		options = append(options, r2.OptHeaderValue(webutil.HeaderXForwardedFor, forwardedFor))
+	}
+	res, err := MockGet(app, path, options...).Discard()
+	if err != nil {
+		return 0
+	}
+	return res.StatusCode
+}
+
+func TestIPFilterAllowOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	trusted := webutil.MustIPAllowlist([]string{"127.0.0.1", "::1"})
+	allow := webutil.MustIPAllowlist([]string{"10.0.0.0/8"})
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, IPFilter(allow, nil, OptIPFilterTrustedProxies(trusted)))
+
+	assert.Equal(http.StatusNoContent, ipFilterStatus(app, "/", "10.1.2.3"))
+	assert.Equal(http.StatusForbidden, ipFilterStatus(app, "/", "11.1.2.3"))
+	assert.Equal(http.StatusForbidden, ipFilterStatus(app, "/", ""), "the proxy itself is not in the allowlist")
+}
+
+func TestIPFilterDenyOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	trusted := webutil.MustIPAllowlist([]string{"127.0.0.1", "::1"})
+	deny := webutil.MustIPAllowlist([]string{"10.0.0.0/8"})
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, IPFilter(nil, deny, OptIPFilterTrustedProxies(trusted)))
+
+	assert.Equal(http.StatusForbidden, ipFilterStatus(app, "/", "10.1.2.3"))
+	assert.Equal(http.StatusNoContent, ipFilterStatus(app, "/", "11.1.2.3"))
+	assert.Equal(http.StatusNoContent, ipFilterStatus(app, "/", ""))
+}
+
+func TestIPFilterCombined(t *testing.T) {
+	assert := assert.New(t)
+
+	trusted := webutil.MustIPAllowlist([]string{"127.0.0.1", "::1"})
+	allow := webutil.MustIPAllowlist([]string{"10.0.0.0/8"})
+	deny := webutil.MustIPAllowlist([]string{"10.0.0.0/24"})
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, IPFilter(allow, deny, OptIPFilterTrustedProxies(trusted)))
+	app.GET("/custom", func(_ *Ctx) Result { return NoContent }, IPFilter(allow, deny,
+		OptIPFilterTrustedProxies(trusted),
+		OptIPFilterForbidden(func(_ *Ctx) Result { return Text.Status(http.StatusTeapot) }),
+	))
+
+	assert.Equal(http.StatusNoContent, ipFilterStatus(app, "/", "10.1.2.3"))
+	assert.Equal(http.StatusForbidden, ipFilterStatus(app, "/", "10.0.0.3"), "deny is evaluated before allow")
+	assert.Equal(http.StatusForbidden, ipFilterStatus(app, "/", "11.1.2.3"))
+	assert.Equal(http.StatusTeapot, ipFilterStatus(app, "/custom", "10.0.0.3"))
+}
+
+func TestIPFilterUntrustedForwardedFor(t *testing.T) {
+	assert := assert.New(t)
+
+	allow := webutil.MustIPAllowlist([]string{"10.0.0.0/8"})
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, IPFilter(allow, nil))
+
+	// without trusted proxies the forwarding headers are ignored.
+	assert.Equal(http.StatusForbidden, ipFilterStatus(app, "/", "10.1.2.3"))
+}
@@ -0,0 +1,793 @@
+package web
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// errJSONFallback is returned by the cached decoder for input it can't decode with `encoding/json` semantics.
+const errJSONFallback ex.Class = "cached json decoder; fall back to encoding/json"
+
+var (
+	jsonPlans     sync.Map // reflect.Type => *jsonPlan, nil if the type is unsupported
+	jsonPlansMu   sync.Mutex
+	jsonBindTypes sync.Map // reflect.Type => bool
+
+	jsonNumberType          = reflect.TypeOf(json.Number(""))
+	jsonUnmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	jsonTextUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterBindType precomputes the json decoding plan for a struct type, and has `Ctx.PostBodyAsJSON`
+// decode into it with the cached decoder (see `DecodeJSON`) rather than `json.Unmarshal`.
+func RegisterBindType(t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	jsonPlanFor(t)
+	jsonBindTypes.Store(t, true)
+}
+
+// isBindType returns if a value is a pointer to a type registered with `RegisterBindType`.
+func isBindType(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	_, ok := jsonBindTypes.Load(t.Elem())
+	return ok
+}
+
+// DecodeJSON decodes a json body into a value, with the same results (and errors) as `json.Unmarshal`.
+// Field mappings are cached per type; unsupported types fall back to `json.Unmarshal`.
+func DecodeJSON(body []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || !json.Valid(body) {
+		return json.Unmarshal(body, v)
+	}
+	plan := jsonPlanFor(rv.Type().Elem())
+	if plan == nil || !isZeroJSONValue(rv.Elem()) {
+		return json.Unmarshal(body, v)
+	}
+	d := jsonDecodeState{data: body}
+	d.skipSpace()
+	if err := plan.decode(&d, rv.Elem()); err != nil {
+		// the value was zero, so resetting it undoes the partial decode, and
+		// the value is only ever decoded by one of the decoders.
+		rv.Elem().Set(reflect.Zero(rv.Type().Elem()))
+		return json.Unmarshal(body, v)
+	}
+	return nil
+}
+
+// isZeroJSONValue returns if a value is the zero value of its type, for the kinds the cached decoder supports.
+// It returns false for any other kind.
+func isZeroJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return math.Float64bits(v.Float()) == 0
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		for index := 0; index < v.NumField(); index++ {
+			if !isZeroJSONValue(v.Field(index)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// jsonDecodeFunc decodes the json value at the current position into a value.
+type jsonDecodeFunc func(*jsonDecodeState, reflect.Value) error
+
+// jsonPlan is the cached decoder for a type.
+type jsonPlan struct {
+	decode jsonDecodeFunc
+}
+
+// jsonField is a struct field the cached decoder can set.
+type jsonField struct {
+	name      string
+	nameBytes []byte
+	index     int
+	plan      *jsonPlan
+}
+
+// jsonPlanFor returns the cached plan for a type, building it if it's not cached yet.
+// It returns nil if the type (or a type it contains) isn't supported.
+func jsonPlanFor(t reflect.Type) *jsonPlan {
+	if cached, ok := jsonPlans.Load(t); ok {
+		return cached.(*jsonPlan)
+	}
+
+	jsonPlansMu.Lock()
+	defer jsonPlansMu.Unlock()
+	if cached, ok := jsonPlans.Load(t); ok {
+		return cached.(*jsonPlan)
+	}
+	building := make(map[reflect.Type]*jsonPlan)
+	plan, err := buildJSONPlan(t, building)
+	if err != nil {
+		jsonPlans.Store(t, (*jsonPlan)(nil))
+		return nil
+	}
+	for builtType, builtPlan := range building {
+		jsonPlans.Store(builtType, builtPlan)
+	}
+	return plan
+}
+
+// buildJSONPlan builds the plan for a type; plans under construction are tracked
+// in `building` so recursive types refer to the same plan.
+func buildJSONPlan(t reflect.Type, building map[reflect.Type]*jsonPlan) (*jsonPlan, error) {
+	if plan, ok := building[t]; ok {
+		return plan, nil
+	}
+	if cached, ok := jsonPlans.Load(t); ok {
+		if plan := cached.(*jsonPlan); plan != nil {
+			return plan, nil
+		}
+		return nil, errJSONFallback
+	}
+	if hasJSONUnmarshaler(t) || t == jsonNumberType {
+		return nil, errJSONFallback
+	}
+
+	plan := new(jsonPlan)
+	building[t] = plan
+	switch t.Kind() {
+	case reflect.Bool:
+		plan.decode = decodeJSONBool
+	case reflect.String:
+		plan.decode = decodeJSONString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		plan.decode = decodeJSONInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		plan.decode = decodeJSONUint
+	case reflect.Float32, reflect.Float64:
+		plan.decode = decodeJSONFloat
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return nil, errJSONFallback
+		}
+		plan.decode = decodeJSONInterface
+	case reflect.Ptr:
+		elem, err := buildJSONPlan(t.Elem(), building)
+		if err != nil {
+			return nil, err
+		}
+		plan.decode = jsonPtrDecoder(elem)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil, errJSONFallback
+		}
+		elem, err := buildJSONPlan(t.Elem(), building)
+		if err != nil {
+			return nil, err
+		}
+		plan.decode = jsonSliceDecoder(elem)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || hasJSONUnmarshaler(t.Key()) {
+			return nil, errJSONFallback
+		}
+		elem, err := buildJSONPlan(t.Elem(), building)
+		if err != nil {
+			return nil, err
+		}
+		plan.decode = jsonMapDecoder(t, elem)
+	case reflect.Struct:
+		fields, err := buildJSONFields(t, building)
+		if err != nil {
+			return nil, err
+		}
+		plan.decode = jsonStructDecoder(fields)
+	default:
+		return nil, errJSONFallback
+	}
+	return plan, nil
+}
+
+// buildJSONFields returns the fields of a struct type the decoder sets, in field order.
+func buildJSONFields(t reflect.Type, building map[reflect.Type]*jsonPlan) ([]jsonField, error) {
+	var fields []jsonField
+	names := make(map[string]bool)
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if field.Anonymous {
+			return nil, errJSONFallback
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		for _, option := range strings.Split(options, ",") {
+			if option == "string" {
+				return nil, errJSONFallback
+			}
+		}
+		if !isValidJSONTag(name) {
+			name = field.Name
+		}
+		if names[name] {
+			return nil, errJSONFallback
+		}
+		names[name] = true
+
+		plan, err := buildJSONPlan(field.Type, building)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, jsonField{name: name, nameBytes: []byte(name), index: index, plan: plan})
+	}
+	return fields, nil
+}
+
+// hasJSONUnmarshaler returns if a type (or a pointer to it) decodes itself.
+func hasJSONUnmarshaler(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return t.Implements(jsonUnmarshalerType) || ptr.Implements(jsonUnmarshalerType) ||
+		t.Implements(jsonTextUnmarshalerType) || ptr.Implements(jsonTextUnmarshalerType)
+}
+
+// isValidJSONTag returns if a tag name is used by `encoding/json`; other names fall back to the field name.
+func isValidJSONTag(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+func decodeJSONBool(d *jsonDecodeState, v reflect.Value) error {
+	switch {
+	case d.consumeLiteral("null"):
+	case d.consumeLiteral("true"):
+		v.SetBool(true)
+	case d.consumeLiteral("false"):
+		v.SetBool(false)
+	default:
+		return errJSONFallback
+	}
+	return nil
+}
+
+func decodeJSONString(d *jsonDecodeState, v reflect.Value) error {
+	if d.consumeLiteral("null") {
+		return nil
+	}
+	value, err := d.readString()
+	if err != nil {
+		return err
+	}
+	v.SetString(value)
+	return nil
+}
+
+func decodeJSONInt(d *jsonDecodeState, v reflect.Value) error {
+	if d.consumeLiteral("null") {
+		return nil
+	}
+	number, err := d.readNumber()
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseInt(string(number), 10, 64)
+	if err != nil || v.OverflowInt(value) {
+		return errJSONFallback
+	}
+	v.SetInt(value)
+	return nil
+}
+
+func decodeJSONUint(d *jsonDecodeState, v reflect.Value) error {
+	if d.consumeLiteral("null") {
+		return nil
+	}
+	number, err := d.readNumber()
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil || v.OverflowUint(value) {
+		return errJSONFallback
+	}
+	v.SetUint(value)
+	return nil
+}
+
+func decodeJSONFloat(d *jsonDecodeState, v reflect.Value) error {
+	if d.consumeLiteral("null") {
+		return nil
+	}
+	number, err := d.readNumber()
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseFloat(string(number), v.Type().Bits())
+	if err != nil || v.OverflowFloat(value) {
+		return errJSONFallback
+	}
+	v.SetFloat(value)
+	return nil
+}
+
+func decodeJSONInterface(d *jsonDecodeState, v reflect.Value) error {
+	// encoding/json decodes into pointers held by interfaces.
+	if !v.IsNil() && v.Elem().Kind() == reflect.Ptr && !v.Elem().IsNil() {
+		return errJSONFallback
+	}
+	value, err := d.readInterface()
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	v.Set(reflect.ValueOf(value))
+	return nil
+}
+
+func jsonPtrDecoder(elem *jsonPlan) jsonDecodeFunc {
+	return func(d *jsonDecodeState, v reflect.Value) error {
+		if d.consumeLiteral("null") {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return elem.decode(d, v.Elem())
+	}
+}
+
+func jsonSliceDecoder(elem *jsonPlan) jsonDecodeFunc {
+	return func(d *jsonDecodeState, v reflect.Value) error {
+		if d.consumeLiteral("null") {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if !d.consume('[') {
+			return errJSONFallback
+		}
+		d.skipSpace()
+		var index int
+		if !d.consume(']') {
+			for {
+				// as with encoding/json, existing elements are decoded into rather than replaced.
+				if index >= v.Cap() {
+					grown := reflect.MakeSlice(v.Type(), v.Len(), v.Cap()+jsonSliceGrowth(v.Cap()))
+					reflect.Copy(grown, v)
+					v.Set(grown)
+				}
+				if index >= v.Len() {
+					v.SetLen(index + 1)
+				}
+				if err := elem.decode(d, v.Index(index)); err != nil {
+					return err
+				}
+				index++
+				d.skipSpace()
+				if d.consume(',') {
+					d.skipSpace()
+					continue
+				}
+				if d.consume(']') {
+					break
+				}
+				return errJSONFallback
+			}
+		}
+		if index < v.Len() {
+			v.SetLen(index)
+		}
+		if index == 0 && v.IsNil() {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+		}
+		return nil
+	}
+}
+
+func jsonMapDecoder(t reflect.Type, elem *jsonPlan) jsonDecodeFunc {
+	keyType, elemType := t.Key(), t.Elem()
+	return func(d *jsonDecodeState, v reflect.Value) error {
+		if d.consumeLiteral("null") {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if !d.consume('{') {
+			return errJSONFallback
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+		d.skipSpace()
+		if d.consume('}') {
+			return nil
+		}
+		keyValue := reflect.New(keyType).Elem()
+		elemValue := reflect.New(elemType).Elem()
+		zero := reflect.Zero(elemType)
+		for {
+			key, err := d.readString()
+			if err != nil {
+				return err
+			}
+			if err = d.consumeColon(); err != nil {
+				return err
+			}
+			elemValue.Set(zero)
+			if err = elem.decode(d, elemValue); err != nil {
+				return err
+			}
+			keyValue.SetString(key)
+			v.SetMapIndex(keyValue, elemValue)
+
+			d.skipSpace()
+			if d.consume(',') {
+				d.skipSpace()
+				continue
+			}
+			if d.consume('}') {
+				return nil
+			}
+			return errJSONFallback
+		}
+	}
+}
+
+func jsonStructDecoder(fields []jsonField) jsonDecodeFunc {
+	byName := make(map[string]*jsonField, len(fields))
+	for index := range fields {
+		byName[fields[index].name] = &fields[index]
+	}
+	lookup := func(key []byte) *jsonField {
+		if field, ok := byName[string(key)]; ok {
+			return field
+		}
+		for index := range fields {
+			if bytes.EqualFold(key, fields[index].nameBytes) {
+				return &fields[index]
+			}
+		}
+		return nil
+	}
+
+	return func(d *jsonDecodeState, v reflect.Value) error {
+		if d.consumeLiteral("null") {
+			return nil
+		}
+		if !d.consume('{') {
+			return errJSONFallback
+		}
+		d.skipSpace()
+		if d.consume('}') {
+			return nil
+		}
+		for {
+			key, err := d.readKey()
+			if err != nil {
+				return err
+			}
+			if err = d.consumeColon(); err != nil {
+				return err
+			}
+			if field := lookup(key); field != nil {
+				err = field.plan.decode(d, v.Field(field.index))
+			} else {
+				err = d.skipValue()
+			}
+			if err != nil {
+				return err
+			}
+
+			d.skipSpace()
+			if d.consume(',') {
+				d.skipSpace()
+				continue
+			}
+			if d.consume('}') {
+				return nil
+			}
+			return errJSONFallback
+		}
+	}
+}
+
+// jsonSliceGrowth returns how many elements to grow a slice by when it's full.
+func jsonSliceGrowth(capacity int) int {
+	if capacity < 4 {
+		return 4
+	}
+	return capacity
+}
+
+// jsonDecodeState is the position of the cached decoder in a json body.
+// The body is checked with `json.Valid` before decoding, but the decoder
+// still returns `errJSONFallback` rather than panicking on malformed input.
+type jsonDecodeState struct {
+	data []byte
+	pos  int
+	// str is a copy of the body that strings without escapes are sliced from,
+	// so decoding allocates once for them rather than once per string.
+	str string
+}
+
+// substring returns a string for a range of the body.
+func (d *jsonDecodeState) substring(start, end int) string {
+	if d.str == "" {
+		d.str = string(d.data)
+	}
+	return d.str[start:end]
+}
+
+func (d *jsonDecodeState) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\r', '\n':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *jsonDecodeState) consume(c byte) bool {
+	if d.pos < len(d.data) && d.data[d.pos] == c {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+func (d *jsonDecodeState) consumeColon() error {
+	d.skipSpace()
+	if !d.consume(':') {
+		return errJSONFallback
+	}
+	d.skipSpace()
+	return nil
+}
+
+func (d *jsonDecodeState) consumeLiteral(literal string) bool {
+	if len(d.data)-d.pos >= len(literal) && string(d.data[d.pos:d.pos+len(literal)]) == literal {
+		d.pos += len(literal)
+		return true
+	}
+	return false
+}
+
+// scanString moves past the string at the current position, returning its contents
+// and if it has to be unquoted by `encoding/json`, i.e. it has escapes or invalid utf-8.
+func (d *jsonDecodeState) scanString() (contents []byte, unquote bool, err error) {
+	if !d.consume('"') {
+		return nil, false, errJSONFallback
+	}
+	start := d.pos
+	var nonASCII bool
+	for d.pos < len(d.data) {
+		switch c := d.data[d.pos]; {
+		case c == '\\':
+			unquote = true
+			d.pos += 2
+		case c == '"':
+			contents = d.data[start:d.pos]
+			d.pos++
+			if nonASCII && !utf8.Valid(contents) {
+				unquote = true
+			}
+			return
+		default:
+			if c >= utf8.RuneSelf {
+				nonASCII = true
+			}
+			d.pos++
+		}
+	}
+	return nil, false, errJSONFallback
+}
+
+// readKey returns an object key, without copying it unless it has to be unquoted.
+func (d *jsonDecodeState) readKey() ([]byte, error) {
+	start := d.pos
+	contents, unquote, err := d.scanString()
+	if err != nil || !unquote {
+		return contents, err
+	}
+	var key string
+	if err = json.Unmarshal(d.data[start:d.pos], &key); err != nil {
+		return nil, errJSONFallback
+	}
+	return []byte(key), nil
+}
+
+func (d *jsonDecodeState) readString() (string, error) {
+	start := d.pos
+	contents, unquote, err := d.scanString()
+	if err != nil {
+		return "", err
+	}
+	if !unquote {
+		return d.substring(start+1, start+1+len(contents)), nil
+	}
+	var value string
+	if err = json.Unmarshal(d.data[start:d.pos], &value); err != nil {
+		return "", errJSONFallback
+	}
+	return value, nil
+}
+
+func (d *jsonDecodeState) readNumber() ([]byte, error) {
+	start := d.pos
+	for d.pos < len(d.data) {
+		if c := d.data[d.pos]; (c < '0' || c > '9') && c != '-' && c != '+' && c != '.' && c != 'e' && c != 'E' {
+			break
+		}
+		d.pos++
+	}
+	if d.pos == start {
+		return nil, errJSONFallback
+	}
+	return d.data[start:d.pos], nil
+}
+
+// readInterface returns the value at the current position as `json.Unmarshal` would decode it into an empty interface.
+func (d *jsonDecodeState) readInterface() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, errJSONFallback
+	}
+	switch d.data[d.pos] {
+	case '{':
+		d.pos++
+		object := make(map[string]interface{})
+		d.skipSpace()
+		if d.consume('}') {
+			return object, nil
+		}
+		for {
+			key, err := d.readString()
+			if err != nil {
+				return nil, err
+			}
+			if err = d.consumeColon(); err != nil {
+				return nil, err
+			}
+			if object[key], err = d.readInterface(); err != nil {
+				return nil, err
+			}
+			d.skipSpace()
+			if d.consume(',') {
+				d.skipSpace()
+				continue
+			}
+			if d.consume('}') {
+				return object, nil
+			}
+			return nil, errJSONFallback
+		}
+	case '[':
+		d.pos++
+		array := make([]interface{}, 0)
+		d.skipSpace()
+		if d.consume(']') {
+			return array, nil
+		}
+		for {
+			value, err := d.readInterface()
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+			d.skipSpace()
+			if d.consume(',') {
+				d.skipSpace()
+				continue
+			}
+			if d.consume(']') {
+				return array, nil
+			}
+			return nil, errJSONFallback
+		}
+	case '"':
+		return d.readString()
+	case 't', 'f', 'n':
+		switch {
+		case d.consumeLiteral("true"):
+			return true, nil
+		case d.consumeLiteral("false"):
+			return false, nil
+		case d.consumeLiteral("null"):
+			return nil, nil
+		}
+		return nil, errJSONFallback
+	default:
+		number, err := d.readNumber()
+		if err != nil {
+			return nil, err
+		}
+		value, err := strconv.ParseFloat(string(number), 64)
+		if err != nil {
+			return nil, errJSONFallback
+		}
+		return value, nil
+	}
+}
+
+// skipValue moves past the value at the current position, e.g. for fields the struct doesn't have.
+func (d *jsonDecodeState) skipValue() error {
+	if d.pos >= len(d.data) {
+		return errJSONFallback
+	}
+	switch d.data[d.pos] {
+	case '"':
+		_, _, err := d.scanString()
+		return err
+	case '{', '[':
+		var depth int
+		for d.pos < len(d.data) {
+			switch d.data[d.pos] {
+			case '"':
+				if _, _, err := d.scanString(); err != nil {
+					return err
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					d.pos++
+					return nil
+				}
+			}
+			d.pos++
+		}
+		return errJSONFallback
+	default:
+		start := d.pos
+		for d.pos < len(d.data) {
+			switch d.data[d.pos] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				if d.pos == start {
+					return errJSONFallback
+				}
+				return nil
+			}
+			d.pos++
+		}
+		return nil
+	}
+}
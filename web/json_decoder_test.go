@@ -0,0 +1,195 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type jsonDecoderAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type jsonDecoderUser struct {
+	ID        int64                  `json:"id"`
+	Name      string                 `json:"name"`
+	Email     *string                `json:"email"`
+	Admin     bool                   `json:"admin"`
+	Score     float64                `json:"score"`
+	Age       uint8                  `json:"age"`
+	Tags      []string               `json:"tags"`
+	Labels    map[string]string      `json:"labels"`
+	Addresses []jsonDecoderAddress   `json:"addresses"`
+	Extra     interface{}            `json:"extra"`
+	Meta      map[string]interface{} `json:"meta"`
+	Manager   *jsonDecoderUser       `json:"manager"`
+	Ignored   string                 `json:"-"`
+	Untagged  string
+	internal  string
+}
+
+type jsonDecoderEvent struct {
+	Name string    `json:"name"`
+	At   time.Time `json:"at"`
+}
+
+func TestDecodeJSONMatchesUnmarshal(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []string{
+		`{"id":1,"name":"bailey","email":"bailey@example.com","admin":true,"score":9.5,"age":7}`,
+		` { "ID" : 2 , "NAME" : "case insensitive", "untagged": "yes", "Ignored": "no", "internal": "no" } `,
+		`{"tags":["a","b"],"labels":{"team":"dogs"},"addresses":[{"street":"1 Main","city":"Bend"},{"street":"2 Main"}]}`,
+		`{"extra":{"nested":[1,"two",true,null,{"three":3.5}]},"meta":{"a":[],"b":{}}}`,
+		`{"manager":{"name":"root","manager":{"id":3}},"unknown":{"skip":["me",{"}":"]"}]},"other":-1.5e3}`,
+		`{"name":"escapes \"quoted\" é 😀 \n","labels":{"kéy":"v"},"name":"escaped key"}`,
+		`{"name":"unicode é 😀"}`,
+		`{"email":null,"tags":null,"labels":null,"manager":null,"extra":null,"id":null,"name":null}`,
+		`{"tags":[],"labels":{},"addresses":[]}`,
+		`null`,
+		// type errors and invalid json fall back to encoding/json.
+		`{"id":"not a number","name":"after the error"}`,
+		`{"age":300}`,
+		`{"age":-1}`,
+		`{"id":1.5}`,
+		`{"name":1}`,
+		`{"tags":"not an array"}`,
+		`{"id":1,`,
+		`{"id":1} trailing`,
+		``,
+	}
+
+	for _, testCase := range testCases {
+		var expected, actual jsonDecoderUser
+		expectedErr := json.Unmarshal([]byte(testCase), &expected)
+		actualErr := DecodeJSON([]byte(testCase), &actual)
+		assert.Equal(expected, actual, testCase)
+		assert.Equal(expectedErr, actualErr, testCase)
+	}
+}
+
+func TestDecodeJSONExistingValues(t *testing.T) {
+	assert := assert.New(t)
+
+	email := "existing@example.com"
+	newUser := func() jsonDecoderUser {
+		return jsonDecoderUser{
+			Name:      "existing",
+			Email:     &email,
+			Tags:      []string{"a", "b", "c"},
+			Labels:    map[string]string{"existing": "true"},
+			Addresses: []jsonDecoderAddress{{Street: "1 Main", City: "Bend"}},
+		}
+	}
+
+	body := []byte(`{"email":"new@example.com","tags":["d"],"labels":{"new":"true"},"addresses":[{"street":"2 Main"},{"street":"3 Main"}]}`)
+	expected, actual := newUser(), newUser()
+	assert.Nil(json.Unmarshal(body, &expected))
+	assert.Nil(DecodeJSON(body, &actual))
+	assert.Equal(expected, actual)
+	assert.Equal("new@example.com", email, "existing pointers are decoded into")
+}
+
+func TestDecodeJSONFallbackLeavesNoPartialValues(t *testing.T) {
+	assert := assert.New(t)
+
+	// the cached decoder fails on the last field, after decoding the others.
+	body := []byte(`{"name":"bailey","tags":["a","b","c","d","e"],"labels":{"team":"dogs"},"manager":{"name":"root"},"age":300}`)
+	var expected, actual jsonDecoderUser
+	expectedErr := json.Unmarshal(body, &expected)
+	actualErr := DecodeJSON(body, &actual)
+	assert.NotNil(actualErr)
+	assert.Equal(expectedErr, actualErr)
+	assert.Equal(expected, actual)
+
+	assert.True(isZeroJSONValue(reflect.ValueOf(jsonDecoderUser{})))
+	assert.False(isZeroJSONValue(reflect.ValueOf(jsonDecoderUser{Tags: []string{}})))
+	assert.False(isZeroJSONValue(reflect.ValueOf(jsonDecoderUser{internal: "set"})))
+}
+
+func TestDecodeJSONFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(jsonPlanFor(reflect.TypeOf(jsonDecoderEvent{})), "types with unmarshalers aren't supported")
+	assert.NotNil(jsonPlanFor(reflect.TypeOf(jsonDecoderUser{})))
+
+	body := []byte(`{"name":"launch","at":"2020-01-02T03:04:05Z"}`)
+	var event jsonDecoderEvent
+	assert.Nil(DecodeJSON(body, &event))
+	assert.Equal("launch", event.Name)
+	assert.Equal(2020, event.At.Year())
+
+	var values map[string]int
+	assert.Nil(DecodeJSON([]byte(`{"a":1}`), &values))
+	assert.Equal(map[string]int{"a": 1}, values)
+
+	var notPointer jsonDecoderUser
+	assert.NotNil(DecodeJSON([]byte(`{}`), notPointer))
+}
+
+func TestCtxPostBodyAsJSONRegisterBindType(t *testing.T) {
+	assert := assert.New(t)
+
+	RegisterBindType(reflect.TypeOf(&jsonDecoderUser{}))
+	assert.True(isBindType(&jsonDecoderUser{}))
+	assert.False(isBindType(jsonDecoderUser{}))
+	assert.False(isBindType(&jsonDecoderEvent{}))
+
+	app := MustNew()
+	app.POST("/", func(r *Ctx) Result {
+		var user jsonDecoderUser
+		if err := r.PostBodyAsJSON(&user); err != nil {
+			return Text.BadRequest(err)
+		}
+		return Text.Result(user.Name)
+	})
+
+	contents, meta, err := MockPost(app, "/", ioutil.NopCloser(strings.NewReader(`{"name":"bailey"}`))).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("bailey", string(contents))
+
+	meta, err = MockPost(app, "/", ioutil.NopCloser(strings.NewReader(`{"name":`))).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+}
+
+var jsonDecoderBenchmarkBody = []byte(`{
+	"id": 1234,
+	"name": "bailey",
+	"email": "bailey@example.com",
+	"admin": true,
+	"score": 98.6,
+	"age": 7,
+	"tags": ["a", "b", "c"],
+	"labels": {"team": "dogs", "env": "prod"},
+	"addresses": [{"street": "1 Main", "city": "Bend"}, {"street": "2 Main", "city": "Portland"}]
+}`)
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	b.ReportAllocs()
+	RegisterBindType(reflect.TypeOf(jsonDecoderUser{}))
+	for i := 0; i < b.N; i++ {
+		var user jsonDecoderUser
+		if err := DecodeJSON(jsonDecoderBenchmarkBody, &user); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONUnmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var user jsonDecoderUser
+		if err := json.Unmarshal(jsonDecoderBenchmarkBody, &user); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
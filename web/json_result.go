@@ -1,14 +1,59 @@
 package web
 
-import "github.com/blend/go-sdk/webutil"
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
 
 // JSONResult is a json result.
 type JSONResult struct {
 	StatusCode int
 	Response   interface{}
+	// CacheControl is an optional value for the "Cache-Control" header, e.g. "public" or "no-store".
+	CacheControl string
+	// MaxAge, if set, adds a `max-age` directive to the "Cache-Control" header
+	// and sets the "Expires" header. If `CacheControl` is unset, the response is marked "public".
+	MaxAge time.Duration
+	// Buffered, if set, serializes the response before it's written so the "Content-Length" header can be set.
+	// It's off by default so large responses are streamed.
+	Buffered bool
+	// Headers are optional headers set on the response before the status is written, e.g. "X-Total-Count".
+	Headers http.Header
 }
 
 // Render renders the result
 func (jr *JSONResult) Render(ctx *Ctx) error {
-	return webutil.WriteJSON(ctx.Response, jr.StatusCode, jr.Response)
+	writeResultHeaders(ctx, jr.Headers)
+	if cacheControl := jr.cacheControl(); cacheControl != "" {
+		ctx.Response.Header().Set(HeaderCacheControl, cacheControl)
+	}
+	if jr.MaxAge > 0 {
+		ctx.Response.Header().Set(HeaderExpires, time.Now().UTC().Add(jr.MaxAge).Format(http.TimeFormat))
+	}
+	if !jr.Buffered {
+		return webutil.WriteJSON(ctx.Response, jr.StatusCode, jr.Response)
+	}
+	buffer := new(bytes.Buffer)
+	if err := json.NewEncoder(buffer).Encode(jr.Response); err != nil {
+		return ex.New(err)
+	}
+	ctx.Response.Header().Set(HeaderContentType, ContentTypeApplicationJSON)
+	return WriteWithContentLength(ctx, jr.StatusCode, buffer.Bytes())
+}
+
+func (jr *JSONResult) cacheControl() string {
+	if jr.MaxAge <= 0 {
+		return jr.CacheControl
+	}
+	maxAge := "max-age=" + strconv.FormatInt(int64(jr.MaxAge/time.Second), 10)
+	if jr.CacheControl == "" {
+		return "public, " + maxAge
+	}
+	return jr.CacheControl + ", " + maxAge
 }
@@ -3,9 +3,12 @@ package web
 import (
 	"bytes"
 	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
 	"github.com/blend/go-sdk/webutil"
 )
 
@@ -46,3 +49,84 @@ func TestJSONResultRenderStatusCode(t *testing.T) {
 	assert.Equal(http.StatusBadRequest, w.StatusCode())
 	assert.Equal("{\"foo\":\"bar\"}\n", buf.String())
 }
+
+func TestJSONResultRenderCacheControl(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	w := webutil.NewMockResponse(buf)
+	r := NewCtx(w, webutil.NewMockRequest("GET", "/"))
+	assert.Nil((&JSONResult{StatusCode: http.StatusOK, Response: "foo"}).Render(r))
+	assert.Empty(w.Header().Get(HeaderCacheControl), "cache headers should not be set by default")
+	assert.Empty(w.Header().Get(HeaderExpires))
+
+	w = webutil.NewMockResponse(new(bytes.Buffer))
+	r = NewCtx(w, webutil.NewMockRequest("GET", "/"))
+	assert.Nil((&JSONResult{StatusCode: http.StatusOK, Response: "foo", CacheControl: "no-store"}).Render(r))
+	assert.Equal("no-store", w.Header().Get(HeaderCacheControl))
+	assert.Empty(w.Header().Get(HeaderExpires))
+
+	w = webutil.NewMockResponse(new(bytes.Buffer))
+	r = NewCtx(w, webutil.NewMockRequest("GET", "/"))
+	assert.Nil((&JSONResult{StatusCode: http.StatusOK, Response: "foo", CacheControl: "private", MaxAge: time.Minute}).Render(r))
+	assert.Equal("private, max-age=60", w.Header().Get(HeaderCacheControl))
+}
+
+func TestCtxJSONCached(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	w := webutil.NewMockResponse(buf)
+	r := NewCtx(w, webutil.NewMockRequest("GET", "/"))
+
+	before := time.Now().UTC().Truncate(time.Second)
+	assert.Nil(r.JSONCached(map[string]string{"foo": "bar"}, 5*time.Minute).Render(r))
+	assert.Equal(http.StatusOK, w.StatusCode())
+	assert.Equal("{\"foo\":\"bar\"}\n", buf.String())
+	assert.Equal("public, max-age=300", w.Header().Get(HeaderCacheControl))
+
+	expires, err := http.ParseTime(w.Header().Get(HeaderExpires))
+	assert.Nil(err)
+	assert.InTimeDelta(before.Add(5*time.Minute), expires, 2*time.Second)
+}
+
+func TestJSONResultRenderBuffered(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return &JSONResult{StatusCode: http.StatusOK, Response: map[string]interface{}{"foo": "bar"}, Buffered: true}
+	})
+	app.GET("/gzip", func(_ *Ctx) Result {
+		return &JSONResult{StatusCode: http.StatusOK, Response: map[string]interface{}{"foo": "bar"}, Buffered: true}
+	}, GZip)
+
+	contents, meta, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("{\"foo\":\"bar\"}\n", string(contents))
+	assert.Equal(strconv.Itoa(len(contents)), meta.Header.Get(HeaderContentLength))
+	assert.Empty(meta.TransferEncoding)
+
+	meta, err = MockGet(app, "/gzip", r2.OptHeaderValue(HeaderAcceptEncoding, ContentEncodingGZIP)).Discard()
+	assert.Nil(err)
+	assert.NotEqual(strconv.Itoa(len(contents)), meta.Header.Get(HeaderContentLength), "the uncompressed length shouldn't be sent")
+}
+
+func TestJSONResultRenderHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return &JSONResult{
+			StatusCode: http.StatusOK,
+			Response:   []string{"foo", "bar"},
+			Headers:    http.Header{"x-total-count": {"2"}},
+		}
+	})
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("2", meta.Header.Get("X-Total-Count"))
+	assert.Equal(ContentTypeApplicationJSON, meta.Header.Get(HeaderContentType))
+}
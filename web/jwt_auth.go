@@ -0,0 +1,160 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/jwt"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// NewJWTAuthConfig returns a new jwt auth config with defaults.
+func NewJWTAuthConfig(options ...JWTAuthOption) JWTAuthConfig {
+	var cfg JWTAuthConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// JWTAuthOption mutates a jwt auth config.
+type JWTAuthOption func(*JWTAuthConfig)
+
+// OptJWTAuthIssuer sets the issuer (`iss`) tokens must have.
+func OptJWTAuthIssuer(issuer string) JWTAuthOption {
+	return func(cfg *JWTAuthConfig) { cfg.Issuer = issuer }
+}
+
+// OptJWTAuthAudience sets the audience (`aud`) tokens must have.
+func OptJWTAuthAudience(audience string) JWTAuthOption {
+	return func(cfg *JWTAuthConfig) { cfg.Audience = audience }
+}
+
+// OptJWTAuthClockSkew sets how far the `exp`, `nbf` and `iat` claims can be off from the server clock.
+func OptJWTAuthClockSkew(skew time.Duration) JWTAuthOption {
+	return func(cfg *JWTAuthConfig) { cfg.ClockSkew = skew }
+}
+
+// OptJWTAuthValidMethods sets the signing methods tokens may use, e.g. `HS512`. It is required.
+func OptJWTAuthValidMethods(methods ...string) JWTAuthOption {
+	return func(cfg *JWTAuthConfig) { cfg.ValidMethods = methods }
+}
+
+// OptJWTAuthAllowMissingExpiration sets if tokens without an `exp` claim, which never expire, are accepted.
+func OptJWTAuthAllowMissingExpiration(allow bool) JWTAuthOption {
+	return func(cfg *JWTAuthConfig) { cfg.AllowMissingExpiration = allow }
+}
+
+// OptJWTAuthUnauthorized sets the handler called for requests that fail authentication.
+// The error's class is one of the `ErrJWT...` classes.
+func OptJWTAuthUnauthorized(unauthorized func(*Ctx, error) Result) JWTAuthOption {
+	return func(cfg *JWTAuthConfig) { cfg.Unauthorized = unauthorized }
+}
+
+// JWTAuthConfig is the configuration for the jwt auth middleware.
+type JWTAuthConfig struct {
+	Issuer                 string
+	Audience               string
+	ClockSkew              time.Duration
+	ValidMethods           []string
+	AllowMissingExpiration bool
+	Unauthorized           func(*Ctx, error) Result
+}
+
+// Verify parses and verifies a token, returning its claims.
+// Errors have one of the `ErrJWT...` classes to distinguish why the token was rejected.
+func (cfg JWTAuthConfig) Verify(token string, keyFunc jwt.Keyfunc) (*jwt.StandardClaims, error) {
+	if len(cfg.ValidMethods) == 0 {
+		return nil, ex.New(ErrJWTValidMethodsUnset)
+	}
+	var claims jwt.StandardClaims
+	parser := jwt.Parser{
+		ValidMethods:         cfg.ValidMethods,
+		SkipClaimsValidation: true,
+	}
+	if _, err := parser.ParseWithClaims(token, &claims, keyFunc); err != nil {
+		return nil, ex.New(ErrJWTInvalid, ex.OptInner(err))
+	}
+	if err := cfg.Validate(&claims, jwt.TimeFunc()); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// Validate validates the standard claims of a token as of a given time.
+func (cfg JWTAuthConfig) Validate(claims *jwt.StandardClaims, now time.Time) error {
+	skew := int64(cfg.ClockSkew / time.Second)
+	if claims.ExpiresAt == 0 && !cfg.AllowMissingExpiration {
+		return ex.New(ErrJWTExpirationMissing)
+	}
+	if claims.ExpiresAt != 0 && now.Unix() > claims.ExpiresAt+skew {
+		return ex.New(ErrJWTExpired, ex.OptMessagef("token is expired by %v", now.Sub(time.Unix(claims.ExpiresAt, 0))))
+	}
+	if claims.NotBefore != 0 && now.Unix() < claims.NotBefore-skew {
+		return ex.New(ErrJWTNotYetValid, ex.OptMessage("token used before its not before time"))
+	}
+	if claims.IssuedAt != 0 && now.Unix() < claims.IssuedAt-skew {
+		return ex.New(ErrJWTNotYetValid, ex.OptMessage("token used before it was issued"))
+	}
+	if cfg.Issuer != "" && !claims.VerifyIssuer(cfg.Issuer, true) {
+		return ex.New(ErrJWTIssuerInvalid, ex.OptMessagef("expected %q, got %q", cfg.Issuer, claims.Issuer))
+	}
+	if cfg.Audience != "" && !claims.VerifyAudience(cfg.Audience, true) {
+		return ex.New(ErrJWTAudienceInvalid, ex.OptMessagef("expected %q, got %q", cfg.Audience, claims.Audience))
+	}
+	return nil
+}
+
+// JWTAuth returns a middleware that authenticates requests with a jwt bearer token.
+// `OptJWTAuthValidMethods(...)` is required; on success the claims are available from `Ctx.Claims()`.
+func JWTAuth(keyFunc jwt.Keyfunc, options ...JWTAuthOption) (Middleware, error) {
+	cfg := NewJWTAuthConfig(options...)
+	if len(cfg.ValidMethods) == 0 {
+		return nil, ex.New(ErrJWTValidMethodsUnset)
+	}
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			token, ok := BearerToken(ctx.Request)
+			if !ok {
+				return cfg.unauthorized(ctx, ex.New(ErrJWTBearerTokenMissing))
+			}
+			claims, err := cfg.Verify(token, keyFunc)
+			if err != nil {
+				return cfg.unauthorized(ctx, err)
+			}
+			ctx.claims = claims
+			return action(ctx)
+		}
+	}, nil
+}
+
+// MustJWTAuth returns a jwt auth middleware like `JWTAuth`, and panics on error.
+func MustJWTAuth(keyFunc jwt.Keyfunc, options ...JWTAuthOption) Middleware {
+	middleware, err := JWTAuth(keyFunc, options...)
+	if err != nil {
+		panic(err)
+	}
+	return middleware
+}
+
+// BearerToken returns the token from a request's `Authorization: Bearer <token>` header.
+func BearerToken(req *http.Request) (string, bool) {
+	header := req.Header.Get(webutil.HeaderAuthorization)
+	if len(header) < len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(header[len("Bearer "):])
+	return token, token != ""
+}
+
+func (cfg JWTAuthConfig) unauthorized(ctx *Ctx, err error) Result {
+	if cfg.Unauthorized != nil {
+		return cfg.Unauthorized(ctx, err)
+	}
+	reason := ex.ErrClass(err).Error()
+	ctx.Response.Header().Set(webutil.HeaderWWWAuthenticate, fmt.Sprintf("Bearer error=\"invalid_token\", error_description=%q", reason))
+	return ctx.DefaultProvider.Status(http.StatusUnauthorized, reason)
+}
@@ -0,0 +1,147 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/jwt"
+	"github.com/blend/go-sdk/r2"
+)
+
+var jwtAuthTestKey = []byte("test-signing-key")
+
+func jwtAuthTestApp(options ...JWTAuthOption) *App {
+	app := MustNew()
+	keyFunc := func(_ *jwt.Token) (interface{}, error) {
+		return jwtAuthTestKey, nil
+	}
+	app.GET("/", func(r *Ctx) Result {
+		return Text.Result(r.Claims().Subject)
+	}, MustJWTAuth(keyFunc, append([]JWTAuthOption{OptJWTAuthValidMethods(jwt.SigningMethodHMAC256.Alg())}, options...)...))
+	return app
+}
+
+func jwtAuthTestToken(t *testing.T, claims jwt.StandardClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHMAC256, &claims).SignedString(jwtAuthTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	app := jwtAuthTestApp(OptJWTAuthIssuer("go-sdk"))
+	now := time.Now().UTC()
+
+	valid := jwtAuthTestToken(t, jwt.StandardClaims{
+		Subject:   "bailey",
+		Issuer:    "go-sdk",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	})
+	contents, meta, err := MockGet(app, "/", r2.OptHeaderValue("Authorization", "Bearer "+valid)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("bailey", string(contents))
+
+	expired := jwtAuthTestToken(t, jwt.StandardClaims{
+		Subject:   "bailey",
+		Issuer:    "go-sdk",
+		ExpiresAt: now.Add(-time.Hour).Unix(),
+	})
+	contents, meta, err = MockGet(app, "/", r2.OptHeaderValue("Authorization", "Bearer "+expired)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Contains(string(contents), ErrJWTExpired.Error())
+	assert.NotEmpty(meta.Header.Get("WWW-Authenticate"))
+
+	wrongIssuer := jwtAuthTestToken(t, jwt.StandardClaims{
+		Subject:   "bailey",
+		Issuer:    "not-go-sdk",
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	})
+	contents, meta, err = MockGet(app, "/", r2.OptHeaderValue("Authorization", "Bearer "+wrongIssuer)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Contains(string(contents), ErrJWTIssuerInvalid.Error())
+
+	contents, meta, err = MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Contains(string(contents), ErrJWTBearerTokenMissing.Error())
+
+	contents, meta, err = MockGet(app, "/", r2.OptHeaderValue("Authorization", "Bearer "+valid+"garbage")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Contains(string(contents), ErrJWTInvalid.Error())
+}
+
+func TestJWTAuthClockSkew(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now().UTC()
+	recentlyExpired := jwtAuthTestToken(t, jwt.StandardClaims{
+		Subject:   "bailey",
+		ExpiresAt: now.Add(-30 * time.Second).Unix(),
+	})
+
+	meta, err := MockGet(jwtAuthTestApp(), "/", r2.OptHeaderValue("Authorization", "Bearer "+recentlyExpired)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	meta, err = MockGet(jwtAuthTestApp(OptJWTAuthClockSkew(time.Minute)), "/", r2.OptHeaderValue("Authorization", "Bearer "+recentlyExpired)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+}
+
+func TestJWTAuthValidMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	keyFunc := func(_ *jwt.Token) (interface{}, error) {
+		return jwtAuthTestKey, nil
+	}
+	_, err := JWTAuth(keyFunc)
+	assert.True(ex.Is(err, ErrJWTValidMethodsUnset))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHMAC512, &jwt.StandardClaims{
+		Subject:   "bailey",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}).SignedString(jwtAuthTestKey)
+	assert.Nil(err)
+	contents, meta, err := MockGet(jwtAuthTestApp(), "/", r2.OptHeaderValue("Authorization", "Bearer "+token)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Contains(string(contents), ErrJWTInvalid.Error())
+}
+
+func TestJWTAuthMissingExpiration(t *testing.T) {
+	assert := assert.New(t)
+
+	token := jwtAuthTestToken(t, jwt.StandardClaims{Subject: "bailey"})
+	contents, meta, err := MockGet(jwtAuthTestApp(), "/", r2.OptHeaderValue("Authorization", "Bearer "+token)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Contains(string(contents), ErrJWTExpirationMissing.Error())
+
+	meta, err = MockGet(jwtAuthTestApp(OptJWTAuthAllowMissingExpiration(true)), "/", r2.OptHeaderValue("Authorization", "Bearer "+token)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+}
+
+func TestBearerToken(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Header: http.Header{"Authorization": {"bearer abc.def.ghi"}}}
+	token, ok := BearerToken(req)
+	assert.True(ok)
+	assert.Equal("abc.def.ghi", token)
+
+	req.Header.Set("Authorization", "Basic Zm9vOmJhcg==")
+	_, ok = BearerToken(req)
+	assert.False(ok)
+}
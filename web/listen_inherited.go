@@ -0,0 +1,114 @@
+package web
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/ex"
+)
+
+// ListenInherited returns the listener passed to the process by its parent with `LISTEN_FDS` if `InheritListener`
+// is set and there is one, otherwise it binds to the config bind address.
+func (a *App) ListenInherited() (*net.TCPListener, error) {
+	if a.Config.InheritListener {
+		fd, ok, err := takeInheritedListenerFD(env.Env())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return inheritedListener(fd)
+		}
+	}
+
+	listenConfig := net.ListenConfig{}
+	if a.Config.ReusePort {
+		listenConfig.Control = reusePortControl
+	}
+	listener, err := listenConfig.Listen(context.Background(), "tcp", a.Config.BindAddrOrDefault())
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		listener.Close()
+		return nil, ex.New("listener returned was not a net.TCPListener")
+	}
+	return tcpListener, nil
+}
+
+// InheritedListenerFD returns the fd of the listener passed to the process, if any, from the
+// `LISTEN_FDS` and `LISTEN_PID` env vars. Only the first passed fd is used.
+func InheritedListenerFD(vars env.Vars) (fd uintptr, ok bool, err error) {
+	if !vars.Has(EnvironmentVariableListenFDs) {
+		return
+	}
+	count, parseErr := strconv.Atoi(vars.String(EnvironmentVariableListenFDs))
+	if parseErr != nil {
+		err = ex.New(ErrInheritedListenerInvalid, ex.OptMessagef("%s: %q", EnvironmentVariableListenFDs, vars.String(EnvironmentVariableListenFDs)), ex.OptInner(parseErr))
+		return
+	}
+	if count < 1 {
+		return
+	}
+	if vars.Has(EnvironmentVariableListenPID) {
+		pid, parseErr := strconv.Atoi(vars.String(EnvironmentVariableListenPID))
+		if parseErr != nil {
+			err = ex.New(ErrInheritedListenerInvalid, ex.OptMessagef("%s: %q", EnvironmentVariableListenPID, vars.String(EnvironmentVariableListenPID)), ex.OptInner(parseErr))
+			return
+		}
+		// the fds were passed to a different process, e.g. our parent.
+		if pid != os.Getpid() {
+			return
+		}
+	}
+	return ListenFDsStart, true, nil
+}
+
+var (
+	inheritedListenerMu    sync.Mutex
+	inheritedListenerTaken bool
+)
+
+// takeInheritedListenerFD returns the inherited listener fd if it hasn't already been taken by another app,
+// and unsets the `LISTEN_FDS` and `LISTEN_PID` env vars.
+func takeInheritedListenerFD(vars env.Vars) (fd uintptr, ok bool, err error) {
+	inheritedListenerMu.Lock()
+	defer inheritedListenerMu.Unlock()
+	if inheritedListenerTaken {
+		return
+	}
+	if fd, ok, err = InheritedListenerFD(vars); err != nil || !ok {
+		return
+	}
+	inheritedListenerTaken = true
+	for _, key := range []string{EnvironmentVariableListenFDs, EnvironmentVariableListenPID} {
+		vars.Delete(key)
+		os.Unsetenv(key)
+	}
+	return
+}
+
+// inheritedListener returns a tcp listener for an inherited fd.
+func inheritedListener(fd uintptr) (*net.TCPListener, error) {
+	file := os.NewFile(fd, "inherited-listener")
+	if file == nil {
+		return nil, ex.New(ErrInheritedListenerInvalid, ex.OptMessagef("fd: %d", fd))
+	}
+	// the listener has its own copy of the fd.
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, ex.New(ErrInheritedListenerInvalid, ex.OptMessagef("fd: %d", fd), ex.OptInner(err))
+	}
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		listener.Close()
+		return nil, ex.New(ErrInheritedListenerInvalid, ex.OptMessagef("fd: %d is not a tcp listener", fd))
+	}
+	return tcpListener, nil
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestInheritedListenerFD(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok, err := InheritedListenerFD(env.Vars{})
+	assert.Nil(err)
+	assert.False(ok)
+
+	fd, ok, err := InheritedListenerFD(env.Vars{EnvironmentVariableListenFDs: "1"})
+	assert.Nil(err)
+	assert.True(ok)
+	assert.Equal(ListenFDsStart, fd)
+
+	_, ok, err = InheritedListenerFD(env.Vars{EnvironmentVariableListenFDs: "0"})
+	assert.Nil(err)
+	assert.False(ok)
+
+	_, ok, err = InheritedListenerFD(env.Vars{
+		EnvironmentVariableListenFDs: "1",
+		EnvironmentVariableListenPID: strconv.Itoa(os.Getpid()),
+	})
+	assert.Nil(err)
+	assert.True(ok)
+
+	_, ok, err = InheritedListenerFD(env.Vars{
+		EnvironmentVariableListenFDs: "1",
+		EnvironmentVariableListenPID: strconv.Itoa(os.Getpid() + 1),
+	})
+	assert.Nil(err)
+	assert.False(ok, "fds passed to another process should be ignored")
+
+	_, _, err = InheritedListenerFD(env.Vars{EnvironmentVariableListenFDs: "one"})
+	assert.True(ex.Is(err, ErrInheritedListenerInvalid))
+}
+
+func TestInheritedListener(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	defer listener.Close()
+	file, err := listener.(*net.TCPListener).File()
+	assert.Nil(err)
+	defer file.Close()
+
+	inherited, err := inheritedListener(file.Fd())
+	assert.Nil(err)
+	defer inherited.Close()
+	assert.Equal(listener.Addr().String(), inherited.Addr().String())
+}
+
+func TestAppListenInheritedReusePort(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SO_REUSEPORT is not supported on windows")
+	}
+	assert := assert.New(t)
+
+	first, err := MustNew(OptBindAddr("127.0.0.1:0"), OptReusePort(true)).ListenInherited()
+	assert.Nil(err)
+	defer first.Close()
+
+	second, err := MustNew(OptBindAddr(first.Addr().String()), OptReusePort(true)).ListenInherited()
+	assert.Nil(err)
+	defer second.Close()
+	assert.Equal(first.Addr().String(), second.Addr().String())
+}
+
+func TestTakeInheritedListenerFD(t *testing.T) {
+	assert := assert.New(t)
+	defer func() { inheritedListenerTaken = false }()
+
+	os.Setenv(EnvironmentVariableListenFDs, "1")
+	defer os.Unsetenv(EnvironmentVariableListenFDs)
+
+	vars := env.Vars{EnvironmentVariableListenFDs: "1"}
+	fd, ok, err := takeInheritedListenerFD(vars)
+	assert.Nil(err)
+	assert.True(ok)
+	assert.Equal(ListenFDsStart, fd)
+	assert.False(vars.Has(EnvironmentVariableListenFDs))
+	_, isSet := os.LookupEnv(EnvironmentVariableListenFDs)
+	assert.False(isSet, "child processes shouldn't inherit the env vars")
+
+	_, ok, err = takeInheritedListenerFD(env.Vars{EnvironmentVariableListenFDs: "1"})
+	assert.Nil(err)
+	assert.False(ok, "the fd should only be taken once")
+}
+
+func TestAppListenInheritedOptIn(t *testing.T) {
+	assert := assert.New(t)
+
+	env.SetEnv(env.Vars{EnvironmentVariableListenFDs: "1"})
+	defer env.Restore()
+
+	listener, err := MustNew(OptBindAddr("127.0.0.1:0")).ListenInherited()
+	assert.Nil(err)
+	defer listener.Close()
+	assert.NotEqual("127.0.0.1:0", listener.Addr().String())
+	assert.True(env.Env().Has(EnvironmentVariableListenFDs), "the fd shouldn't be taken unless the app opts in")
+}
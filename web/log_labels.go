@@ -0,0 +1,42 @@
+package web
+
+import (
+	"github.com/blend/go-sdk/logger"
+)
+
+// AddLogLabel adds a label to the events logged for the rest of the request, e.g. `tenant=acme`.
+func (rc *Ctx) AddLogLabel(key, value string) {
+	rc.logLabelsMu.Lock()
+	defer rc.logLabelsMu.Unlock()
+	if rc.logLabels == nil {
+		rc.logLabels = make(logger.Fields)
+	}
+	rc.logLabels[key] = value
+}
+
+// LogLabels returns a copy of the labels added with `AddLogLabel`.
+func (rc *Ctx) LogLabels() logger.Fields {
+	rc.logLabelsMu.Lock()
+	defer rc.logLabelsMu.Unlock()
+	if len(rc.logLabels) == 0 {
+		return nil
+	}
+	return logger.CombineFields(rc.logLabels)
+}
+
+// Logger returns the app logger with the request log labels as context fields.
+// It returns a logger that discards events if the app logger is unset.
+func (rc *Ctx) Logger() logger.Log {
+	if rc.App == nil || rc.App.Log == nil {
+		return logger.None()
+	}
+	return rc.withLogLabels(rc.App.Log)
+}
+
+// withLogLabels returns a log with the request log labels, if any, as context fields.
+func (rc *Ctx) withLogLabels(log logger.Log) logger.Log {
+	if labels := rc.LogLabels(); len(labels) > 0 {
+		return log.WithFields(labels)
+	}
+	return log
+}
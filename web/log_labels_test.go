@@ -0,0 +1,52 @@
+package web
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestCtxAddLogLabel(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(buffer), logger.OptText(logger.OptTextNoColor(), logger.OptTextHideTimestamp()))
+	app := MustNew(OptLog(log))
+	app.GET("/", func(r *Ctx) Result {
+		r.AddLogLabel("tenant", "acme")
+		r.Logger().Infof("fetching account")
+		return NoContent
+	})
+
+	_, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Nil(log.Drain())
+
+	var infoLine, responseLine string
+	for _, line := range strings.Split(buffer.String(), "\n") {
+		if strings.Contains(line, "fetching account") {
+			infoLine = line
+		} else if strings.HasPrefix(line, "["+logger.HTTPResponse+"]") {
+			responseLine = line
+		}
+	}
+	assert.Contains(infoLine, "tenant=acme")
+	assert.Contains(responseLine, "tenant=acme")
+}
+
+func TestCtxLogLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	assert.NotNil(rc.Logger(), "the logger should discard events if the app logger is unset")
+	rc.Logger().Infof("this is discarded")
+	assert.Empty(rc.LogLabels())
+
+	rc.AddLogLabel("tenant", "acme")
+	labels := rc.LogLabels()
+	labels["tenant"] = "not-acme"
+	assert.Equal("acme", rc.LogLabels()["tenant"])
+}
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// DefaultMaxConcurrentRetryAfter is the default `Retry-After` sent with requests rejected by `MaxConcurrent`.
+const DefaultMaxConcurrentRetryAfter = time.Second
+
+// MaxConcurrentOption mutates a max concurrent config.
+type MaxConcurrentOption func(*MaxConcurrentConfig)
+
+// OptMaxConcurrentQueue sets the number of requests that can wait for a slot when all the slots are in use,
+// and how long they wait before they're rejected. A timeout of zero or less waits until the request is cancelled.
+func OptMaxConcurrentQueue(size int, timeout time.Duration) MaxConcurrentOption {
+	return func(cfg *MaxConcurrentConfig) {
+		cfg.QueueSize = size
+		cfg.QueueTimeout = timeout
+	}
+}
+
+// OptMaxConcurrentRetryAfter sets the `Retry-After` sent with rejected requests.
+func OptMaxConcurrentRetryAfter(retryAfter time.Duration) MaxConcurrentOption {
+	return func(cfg *MaxConcurrentConfig) { cfg.RetryAfter = retryAfter }
+}
+
+// OptMaxConcurrentRejected sets the action called for rejected requests.
+func OptMaxConcurrentRejected(rejected Action) MaxConcurrentOption {
+	return func(cfg *MaxConcurrentConfig) { cfg.Rejected = rejected }
+}
+
+// MaxConcurrentConfig is the configuration for the max concurrent middleware.
+type MaxConcurrentConfig struct {
+	Limit        int
+	QueueSize    int
+	QueueTimeout time.Duration
+	RetryAfter   time.Duration
+	Rejected     Action
+}
+
+// MaxConcurrent returns a middleware that limits the number of requests handled at once to `limit`, shedding the rest.
+// Slots are released when the action returns, before the result is rendered.
+func MaxConcurrent(limit int, options ...MaxConcurrentOption) (Middleware, error) {
+	cfg := MaxConcurrentConfig{
+		Limit:      limit,
+		RetryAfter: DefaultMaxConcurrentRetryAfter,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	if cfg.Limit <= 0 {
+		return nil, ex.New(ErrMaxConcurrentLimitInvalid, ex.OptMessagef("limit: %d", cfg.Limit))
+	}
+	slots := make(chan struct{}, cfg.Limit)
+	var queue chan struct{}
+	if cfg.QueueSize > 0 {
+		queue = make(chan struct{}, cfg.QueueSize)
+	}
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			if !cfg.acquire(ctx, slots, queue) {
+				return cfg.reject(ctx)
+			}
+			defer func() { <-slots }()
+			return action(ctx)
+		}
+	}, nil
+}
+
+// MustMaxConcurrent returns a max concurrent middleware like `MaxConcurrent`, and panics on error.
+func MustMaxConcurrent(limit int, options ...MaxConcurrentOption) Middleware {
+	middleware, err := MaxConcurrent(limit, options...)
+	if err != nil {
+		panic(err)
+	}
+	return middleware
+}
+
+// acquire takes a slot, waiting in the queue if there is room.
+func (cfg MaxConcurrentConfig) acquire(ctx *Ctx, slots, queue chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+	if queue == nil {
+		return false
+	}
+	select {
+	case queue <- struct{}{}:
+		defer func() { <-queue }()
+	default:
+		return false
+	}
+
+	var timeout <-chan time.Time
+	if cfg.QueueTimeout > 0 {
+		timer := time.NewTimer(cfg.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timeout:
+		return false
+	case <-ctx.Context().Done():
+		return false
+	}
+}
+
+func (cfg MaxConcurrentConfig) reject(ctx *Ctx) Result {
+	if cfg.RetryAfter > 0 {
+		// the header is a whole number of seconds, so round up.
+		seconds := int64((cfg.RetryAfter + time.Second - 1) / time.Second)
+		ctx.Response.Header().Set(webutil.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+	}
+	if cfg.Rejected != nil {
+		return cfg.Rejected(ctx)
+	}
+	return ctx.DefaultProvider.Status(http.StatusServiceUnavailable)
+}
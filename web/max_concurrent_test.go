@@ -0,0 +1,129 @@
+package web
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+// maxConcurrentTestApp returns an app with a route that blocks until released,
+// and a channel that is signalled when a request is being handled.
+func maxConcurrentTestApp(options ...MaxConcurrentOption) (app *App, started chan struct{}, release chan struct{}) {
+	started = make(chan struct{}, 8)
+	release = make(chan struct{})
+	app = MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		started <- struct{}{}
+		<-release
+		return NoContent
+	}, MustMaxConcurrent(1, options...))
+	return
+}
+
+func TestMaxConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	app, started, release := maxConcurrentTestApp(OptMaxConcurrentRetryAfter(1500 * time.Millisecond))
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	var statusCode int
+	go func() {
+		defer wg.Done()
+		meta, _ := MockGet(app, "/").Discard()
+		statusCode = meta.StatusCode
+	}()
+	<-started
+
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, meta.StatusCode)
+	assert.Equal("2", meta.Header.Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+	assert.Equal(http.StatusNoContent, statusCode)
+
+	meta, err = MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode, "the slot should be released")
+}
+
+func TestMaxConcurrentQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	app, started, release := maxConcurrentTestApp(OptMaxConcurrentQueue(1, 5*time.Second))
+
+	results := make(chan int, 3)
+	for x := 0; x < 2; x++ {
+		go func() {
+			meta, _ := MockGet(app, "/").Discard()
+			results <- meta.StatusCode
+		}()
+	}
+	<-started
+
+	// wait for the second request to be queued, then a third is rejected as the queue is full.
+	var statusCode int
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		meta, err := MockGet(app, "/").Discard()
+		assert.Nil(err)
+		if statusCode = meta.StatusCode; statusCode == http.StatusServiceUnavailable {
+			break
+		}
+	}
+	assert.Equal(http.StatusServiceUnavailable, statusCode)
+
+	close(release)
+	assert.Equal(http.StatusNoContent, <-results)
+	assert.Equal(http.StatusNoContent, <-results, "the queued request should be handled once a slot is free")
+}
+
+func TestMaxConcurrentQueueTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	app, started, release := maxConcurrentTestApp(OptMaxConcurrentQueue(1, 10*time.Millisecond))
+	defer close(release)
+
+	go func() { _, _ = MockGet(app, "/").Discard() }()
+	<-started
+
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, meta.StatusCode)
+}
+
+func TestMaxConcurrentPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(r *Ctx) Result {
+		if r.Request.URL.Query().Get("panic") != "" {
+			panic("this is only a test")
+		}
+		return NoContent
+	}, MustMaxConcurrent(1))
+
+	meta, err := MockGet(app, "/", r2.OptQueryValue("panic", "true")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, meta.StatusCode)
+
+	meta, err = MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode, "the slot should be released after a panic")
+}
+
+func TestMaxConcurrentInvalidLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, limit := range []int{0, -1} {
+		middleware, err := MaxConcurrent(limit)
+		assert.Nil(middleware)
+		assert.True(ex.Is(err, ErrMaxConcurrentLimitInvalid))
+	}
+}
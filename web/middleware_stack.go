@@ -0,0 +1,35 @@
+package web
+
+// NewMiddlewareStack returns a new middleware stack.
+func NewMiddlewareStack(middleware ...Middleware) MiddlewareStack {
+	return append(MiddlewareStack(nil), middleware...)
+}
+
+// MiddlewareStack is a reusable list of middleware, applied in the same order as middleware passed to a route.
+type MiddlewareStack []Middleware
+
+// Append returns a new stack with the given middleware added after the middleware in the stack.
+// The original stack is not modified.
+func (ms MiddlewareStack) Append(middleware ...Middleware) MiddlewareStack {
+	output := make(MiddlewareStack, 0, len(ms)+len(middleware))
+	output = append(output, ms...)
+	return append(output, middleware...)
+}
+
+// Extend returns a new stack with the middleware of another stack added after the middleware in the stack.
+// The original stacks are not modified.
+func (ms MiddlewareStack) Extend(other MiddlewareStack) MiddlewareStack {
+	return ms.Append(other...)
+}
+
+// Then wraps an action with the middleware in the stack.
+func (ms MiddlewareStack) Then(action Action) Action {
+	return NestMiddleware(action, ms...)
+}
+
+// Middleware returns the stack as a single middleware.
+func (ms MiddlewareStack) Middleware() Middleware {
+	return func(action Action) Action {
+		return ms.Then(action)
+	}
+}
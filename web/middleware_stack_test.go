@@ -0,0 +1,54 @@
+package web
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestMiddlewareStack(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	tracked := func(name string) Middleware {
+		return func(action Action) Action {
+			return func(r *Ctx) Result {
+				calls = append(calls, name)
+				return action(r)
+			}
+		}
+	}
+	action := func(r *Ctx) Result {
+		calls = append(calls, "action")
+		return nil
+	}
+
+	base := NewMiddlewareStack(tracked("one"), tracked("two"))
+	extended := base.Append(tracked("three"))
+	other := base.Append(tracked("four"))
+	assert.Len(base, 2, "appending should not modify the base stack")
+	assert.Len(extended, 3)
+	assert.Len(other, 3)
+
+	base.Then(action)(MockCtx("GET", "/"))
+	assert.Equal([]string{"two", "one", "action"}, calls)
+
+	calls = nil
+	extended.Then(action)(MockCtx("GET", "/"))
+	assert.Equal([]string{"three", "two", "one", "action"}, calls)
+
+	calls = nil
+	other.Then(action)(MockCtx("GET", "/"))
+	assert.Equal([]string{"four", "two", "one", "action"}, calls, "stacks appended from the same base should be independent")
+
+	calls = nil
+	NestMiddleware(action, base.Extend(NewMiddlewareStack(tracked("five")))...)(MockCtx("GET", "/"))
+	assert.Equal([]string{"five", "two", "one", "action"}, calls, "stacks should apply as the equivalent middleware list")
+
+	calls = nil
+	app := MustNew()
+	app.GET("/", action, base.Middleware(), tracked("route"))
+	_, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal([]string{"route", "two", "one", "action"}, calls)
+}
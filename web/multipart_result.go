@@ -0,0 +1,117 @@
+package web
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// DefaultMultipartSubtype is the default subtype of multipart responses.
+const DefaultMultipartSubtype = "mixed"
+
+// Multipart returns a new multipart result.
+func Multipart(parts ...MultipartPart) *MultipartResult {
+	return &MultipartResult{Parts: parts}
+}
+
+// MultipartPart is a part of a multipart result.
+// If `Reader` is set it's streamed as the body, and closed if it's an `io.Closer`, otherwise `Body` is written.
+type MultipartPart struct {
+	ContentType string
+	Headers     http.Header
+	Body        []byte
+	Reader      io.Reader
+}
+
+// MultipartResult is a result with multiple parts, each with its own content type, e.g. the results of a batch request.
+type MultipartResult struct {
+	StatusCode int
+	// Subtype is the multipart subtype, e.g. "mixed" or "related"; it defaults to "mixed".
+	Subtype string
+	// Boundary is an optional boundary; a random one is used if it's unset.
+	Boundary string
+	Parts    []MultipartPart
+	// Headers are optional headers set on the response before the status is written.
+	Headers http.Header
+}
+
+// Add adds a part with a given content type and body.
+func (mr *MultipartResult) Add(contentType string, body []byte) *MultipartResult {
+	mr.Parts = append(mr.Parts, MultipartPart{ContentType: contentType, Body: body})
+	return mr
+}
+
+// AddReader adds a part with a given content type whose body is streamed from a reader.
+func (mr *MultipartResult) AddReader(contentType string, body io.Reader) *MultipartResult {
+	mr.Parts = append(mr.Parts, MultipartPart{ContentType: contentType, Reader: body})
+	return mr
+}
+
+// Render renders the result.
+func (mr *MultipartResult) Render(ctx *Ctx) error {
+	defer mr.closeReaders()
+
+	writer := multipart.NewWriter(ctx.Response)
+	if mr.Boundary != "" {
+		if err := writer.SetBoundary(mr.Boundary); err != nil {
+			return ex.New(err)
+		}
+	}
+	writeResultHeaders(ctx, mr.Headers)
+	ctx.Response.Header().Set(HeaderContentType, "multipart/"+mr.subtype()+"; boundary="+writer.Boundary())
+
+	statusCode := mr.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	ctx.Response.WriteHeader(statusCode)
+
+	for index, part := range mr.Parts {
+		partWriter, err := writer.CreatePart(part.header())
+		if err != nil {
+			return ex.New(err, ex.OptMessagef("part: %d", index))
+		}
+		if part.Reader != nil {
+			_, err = io.Copy(partWriter, part.Reader)
+		} else {
+			_, err = partWriter.Write(part.Body)
+		}
+		if err != nil {
+			return ex.New(err, ex.OptMessagef("part: %d", index))
+		}
+		ctx.Response.Flush()
+	}
+	if err := writer.Close(); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+func (mr *MultipartResult) subtype() string {
+	if mr.Subtype != "" {
+		return mr.Subtype
+	}
+	return DefaultMultipartSubtype
+}
+
+func (mr *MultipartResult) closeReaders() {
+	for _, part := range mr.Parts {
+		if closer, ok := part.Reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+func (mp MultipartPart) header() textproto.MIMEHeader {
+	header := make(textproto.MIMEHeader)
+	for key, values := range mp.Headers {
+		header[textproto.CanonicalMIMEHeaderKey(key)] = values
+	}
+	if mp.ContentType != "" {
+		header.Set(HeaderContentType, mp.ContentType)
+	}
+	return header
+}
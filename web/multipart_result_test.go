@@ -0,0 +1,84 @@
+package web
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type closeTrackingReader struct {
+	*strings.Reader
+	closed bool
+}
+
+func (ctr *closeTrackingReader) Close() error {
+	ctr.closed = true
+	return nil
+}
+
+func TestMultipartResult(t *testing.T) {
+	assert := assert.New(t)
+
+	report := &closeTrackingReader{Reader: strings.NewReader("id,name\n1,bailey\n")}
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		result := Multipart().
+			Add(ContentTypeApplicationJSON, []byte(`{"id":1}`)).
+			AddReader("text/csv", report)
+		result.Boundary = "test-boundary"
+		result.Parts[0].Headers = http.Header{"content-id": {"<user>"}}
+		return result
+	})
+
+	contents, meta, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("multipart/mixed; boundary=test-boundary", meta.Header.Get(HeaderContentType))
+	assert.True(report.closed)
+	assert.True(strings.HasPrefix(string(contents), "--test-boundary\r\n"))
+	assert.True(strings.HasSuffix(string(contents), "\r\n--test-boundary--\r\n"))
+
+	mediaType, params, err := mime.ParseMediaType(meta.Header.Get(HeaderContentType))
+	assert.Nil(err)
+	assert.Equal("multipart/mixed", mediaType)
+	reader := multipart.NewReader(strings.NewReader(string(contents)), params["boundary"])
+
+	part, err := reader.NextPart()
+	assert.Nil(err)
+	assert.Equal(ContentTypeApplicationJSON, part.Header.Get(HeaderContentType))
+	assert.Equal("<user>", part.Header.Get("Content-Id"))
+	body, err := ioutil.ReadAll(part)
+	assert.Nil(err)
+	assert.Equal(`{"id":1}`, string(body))
+
+	part, err = reader.NextPart()
+	assert.Nil(err)
+	assert.Equal("text/csv", part.Header.Get(HeaderContentType))
+	body, err = ioutil.ReadAll(part)
+	assert.Nil(err)
+	assert.Equal("id,name\n1,bailey\n", string(body))
+
+	_, err = reader.NextPart()
+	assert.NotNil(err)
+}
+
+func TestMultipartResultRandomBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return &MultipartResult{Subtype: "related", StatusCode: http.StatusMultiStatus}
+	})
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusMultiStatus, meta.StatusCode)
+	mediaType, params, err := mime.ParseMediaType(meta.Header.Get(HeaderContentType))
+	assert.Nil(err)
+	assert.Equal("multipart/related", mediaType)
+	assert.NotEmpty(params["boundary"])
+}
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+var (
+	middlewareNamesMu sync.RWMutex
+	middlewareNames   = map[uintptr]namedMiddleware{}
+)
+
+// namedMiddleware is an entry in the middleware names table.
+// It holds a reference to the middleware so its address isn't reused while the name is recorded.
+type namedMiddleware struct {
+	Name       string
+	Middleware Middleware
+}
+
+// Named returns a middleware that is reported with a given name by `App.RouteMiddleware(...)`.
+func Named(name string, middleware Middleware) Middleware {
+	named := func(action Action) Action {
+		return middleware(action)
+	}
+	middlewareNamesMu.Lock()
+	middlewareNames[middlewareID(named)] = namedMiddleware{Name: name, Middleware: named}
+	middlewareNamesMu.Unlock()
+	return named
+}
+
+// MiddlewareName returns the name of a middleware, either as given to `Named(...)` or as reported by the runtime,
+// e.g. `github.com/blend/go-sdk/web.SessionRequired`.
+func MiddlewareName(middleware Middleware) string {
+	if middleware == nil {
+		return ""
+	}
+	middlewareNamesMu.RLock()
+	named, ok := middlewareNames[middlewareID(middleware)]
+	middlewareNamesMu.RUnlock()
+	if ok {
+		return named.Name
+	}
+	if fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// middlewareID returns the address of a middleware's closure, which identifies each closure `Named` returns.
+// The code pointer from reflect can't be used, as it is shared by all closures of a function literal.
+func middlewareID(middleware Middleware) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&middleware))
+}
@@ -0,0 +1,214 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/oauth"
+	"github.com/blend/go-sdk/uuid"
+)
+
+// OAuth defaults.
+const (
+	// DefaultOAuthCookieName is the default name of the cookie that holds the signed oauth state.
+	DefaultOAuthCookieName = "_oauth"
+	// DefaultOAuthCookieTimeout is the default time a user has to finish logging in.
+	DefaultOAuthCookieTimeout = 10 * time.Minute
+)
+
+// OAuthOption mutates an oauth config.
+type OAuthOption func(*OAuthConfig)
+
+// OptOAuthCookieName sets the name of the cookie that holds the signed oauth state.
+func OptOAuthCookieName(name string) OAuthOption {
+	return func(cfg *OAuthConfig) { cfg.CookieName = name }
+}
+
+// OptOAuthCookiePath sets the path of the oauth state cookie.
+// It must include both the login and callback routes.
+func OptOAuthCookiePath(path string) OAuthOption {
+	return func(cfg *OAuthConfig) { cfg.CookiePath = path }
+}
+
+// OptOAuthCookieSecure sets if the oauth state cookie should only be sent over https.
+func OptOAuthCookieSecure(secure bool) OAuthOption {
+	return func(cfg *OAuthConfig) { cfg.CookieSecure = secure }
+}
+
+// OptOAuthCookieTimeout sets how long the user has to finish logging in.
+func OptOAuthCookieTimeout(timeout time.Duration) OAuthOption {
+	return func(cfg *OAuthConfig) { cfg.CookieTimeout = timeout }
+}
+
+// OptOAuthPKCE sets if the login flow should use a PKCE code challenge, which is required for public clients.
+func OptOAuthPKCE(pkce bool) OAuthOption {
+	return func(cfg *OAuthConfig) { cfg.PKCE = pkce }
+}
+
+// OAuthConfig is the configuration for the oauth middleware.
+type OAuthConfig struct {
+	Manager       *oauth.Manager
+	CookieName    string
+	CookiePath    string
+	CookieSecure  bool
+	CookieTimeout time.Duration
+	PKCE          bool
+}
+
+// OAuthAware returns a middleware that enables the `Ctx.OAuthRedirect()` and `Ctx.OAuthCallback()` login helpers.
+// The manager must have a secret set.
+func OAuthAware(manager *oauth.Manager, options ...OAuthOption) Middleware {
+	cfg := OAuthConfig{
+		Manager:       manager,
+		CookieName:    DefaultOAuthCookieName,
+		CookiePath:    DefaultCookiePath,
+		CookieTimeout: DefaultOAuthCookieTimeout,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			ctx.oauth = &cfg
+			return action(ctx)
+		}
+	}
+}
+
+// oauthCookie is the state stored in the signed oauth cookie.
+type oauthCookie struct {
+	Token        string `json:"t"`
+	CodeVerifier string `json:"v,omitempty"`
+}
+
+// redirect returns the redirect to the provider for a request, writing the oauth cookie.
+func (cfg OAuthConfig) redirect(ctx *Ctx, state string) (Result, error) {
+	if len(cfg.Manager.Secret) == 0 {
+		return nil, ex.New(oauth.ErrSecretRequired)
+	}
+	contents := oauthCookie{Token: uuid.V4().String()}
+	stateOptions := []oauth.StateOption{
+		oauth.OptStateToken(contents.Token),
+		oauth.OptStateRedirectURI(state),
+	}
+
+	var oauthURL string
+	var err error
+	if cfg.PKCE {
+		if contents.CodeVerifier, err = oauth.NewPKCEVerifier(); err != nil {
+			return nil, err
+		}
+		oauthURL, err = cfg.Manager.OAuthURLPKCE(ctx.Request, contents.CodeVerifier, stateOptions...)
+	} else {
+		oauthURL, err = cfg.Manager.OAuthURL(ctx.Request, stateOptions...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := cfg.sign(contents)
+	if err != nil {
+		return nil, err
+	}
+	cfg.writeCookie(ctx, value, time.Now().UTC().Add(cfg.CookieTimeout))
+	return Redirect(oauthURL), nil
+}
+
+// callback validates the callback state against the oauth cookie and finishes the login.
+func (cfg OAuthConfig) callback(ctx *Ctx) (*oauth.Result, error) {
+	if len(cfg.Manager.Secret) == 0 {
+		return nil, ex.New(oauth.ErrSecretRequired)
+	}
+	cookie := ctx.Cookie(cfg.CookieName)
+	// the cookie is single use.
+	cfg.writeCookie(ctx, "", time.Unix(0, 0))
+	if cookie == nil {
+		return nil, ex.New(ErrOAuthStateInvalid, ex.OptMessage("oauth cookie missing"))
+	}
+	contents, err := cfg.verify(cookie.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := oauth.DeserializeState(ctx.Request.URL.Query().Get("state"))
+	if err != nil {
+		return nil, ex.New(ErrOAuthStateInvalid, ex.OptInner(err))
+	}
+	if !hmac.Equal([]byte(state.Token), []byte(contents.Token)) {
+		return nil, ex.New(ErrOAuthStateInvalid, ex.OptMessage("oauth state doesn't match the oauth cookie"))
+	}
+
+	var result *oauth.Result
+	if contents.CodeVerifier != "" {
+		result, err = cfg.Manager.FinishPKCE(ctx.Request, contents.CodeVerifier)
+	} else {
+		result, err = cfg.Manager.Finish(ctx.Request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.Manager.ValidateProfile(&result.Profile); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (cfg OAuthConfig) sign(contents oauthCookie) (string, error) {
+	payload, err := json.Marshal(contents)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(cfg.mac(encoded)), nil
+}
+
+func (cfg OAuthConfig) verify(value string) (contents oauthCookie, err error) {
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 {
+		err = ex.New(ErrOAuthStateInvalid, ex.OptMessage("oauth cookie malformed"))
+		return
+	}
+	signature, decodeErr := base64.RawURLEncoding.DecodeString(parts[1])
+	if decodeErr != nil || !hmac.Equal(signature, cfg.mac(parts[0])) {
+		err = ex.New(ErrOAuthStateInvalid, ex.OptMessage("oauth cookie signature invalid"))
+		return
+	}
+	payload, decodeErr := base64.RawURLEncoding.DecodeString(parts[0])
+	if decodeErr != nil {
+		err = ex.New(ErrOAuthStateInvalid, ex.OptInner(decodeErr))
+		return
+	}
+	if decodeErr = json.Unmarshal(payload, &contents); decodeErr != nil {
+		err = ex.New(ErrOAuthStateInvalid, ex.OptInner(decodeErr))
+	}
+	return
+}
+
+func (cfg OAuthConfig) mac(encoded string) []byte {
+	mac := hmac.New(sha256.New, cfg.Manager.Secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+func (cfg OAuthConfig) writeCookie(ctx *Ctx, value string, expires time.Time) {
+	cookie := &http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    value,
+		Path:     cfg.CookiePath,
+		Secure:   cfg.CookieSecure,
+		HttpOnly: true,
+		// the callback is a cross site navigation from the provider, so the cookie can't be strict.
+		SameSite: http.SameSiteLaxMode,
+		Expires:  expires,
+	}
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+	ctx.WriteNewCookie(cookie)
+}
@@ -0,0 +1,184 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/oauth"
+	"github.com/blend/go-sdk/r2"
+)
+
+func oauthTestApp(options ...OAuthOption) *App {
+	manager := oauth.MustNew(
+		oauth.OptClientID("test_client_id"),
+		oauth.OptSecret([]byte("test_secret")),
+		oauth.OptRedirectURI("https://app.example.com/oauth/google"),
+	)
+	app := MustNew()
+	app.GET("/login", func(r *Ctx) Result {
+		return r.OAuthRedirect("/dashboard")
+	}, OAuthAware(manager, options...))
+	app.GET("/oauth/google", func(r *Ctx) Result {
+		_, err := r.OAuthCallback()
+		if ex.Is(err, ErrOAuthStateInvalid) {
+			return Text.NotAuthorized()
+		}
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		return NoContent
+	}, OAuthAware(manager, options...))
+	return app
+}
+
+func oauthTestLogin(t *testing.T, app *App) (*url.URL, *http.Cookie) {
+	meta, err := MockGet(app, "/login", r2.OptNoFollow()).Discard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	location, err := url.Parse(meta.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range ReadSetCookies(meta.Header) {
+		if cookie.Name == DefaultOAuthCookieName {
+			return location, cookie
+		}
+	}
+	t.Fatal("oauth cookie not set")
+	return nil, nil
+}
+
+func TestOAuthRedirect(t *testing.T) {
+	assert := assert.New(t)
+
+	location, cookie := oauthTestLogin(t, oauthTestApp(OptOAuthPKCE(true)))
+	assert.Equal("test_client_id", location.Query().Get("client_id"))
+	assert.NotEmpty(location.Query().Get("code_challenge"))
+	assert.True(cookie.HttpOnly)
+
+	state, err := oauth.DeserializeState(location.Query().Get("state"))
+	assert.Nil(err)
+	assert.Equal("/dashboard", state.RedirectURI)
+	assert.NotEmpty(state.Token)
+}
+
+func TestOAuthCallbackStateMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	app := oauthTestApp()
+	location, cookie := oauthTestLogin(t, app)
+
+	// a state from a login started by another browser.
+	otherLocation, _ := oauthTestLogin(t, app)
+	meta, err := MockGet(app, "/oauth/google",
+		r2.OptQueryValue("code", "test_code"),
+		r2.OptQueryValue("state", otherLocation.Query().Get("state")),
+		r2.OptCookieValue(DefaultOAuthCookieName, cookie.Value),
+	).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	// no cookie.
+	meta, err = MockGet(app, "/oauth/google",
+		r2.OptQueryValue("code", "test_code"),
+		r2.OptQueryValue("state", location.Query().Get("state")),
+	).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	// tampered cookie.
+	meta, err = MockGet(app, "/oauth/google",
+		r2.OptQueryValue("code", "test_code"),
+		r2.OptQueryValue("state", location.Query().Get("state")),
+		r2.OptCookieValue(DefaultOAuthCookieName, cookie.Value+"x"),
+	).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+}
+
+// oauthTestTransport sends every request to a mock server.
+type oauthTestTransport struct {
+	server *httptest.Server
+}
+
+func (ott oauthTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mockURL, _ := url.Parse(ott.server.URL)
+	req.URL.Scheme = mockURL.Scheme
+	req.URL.Host = mockURL.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOAuthCallback(t *testing.T) {
+	assert := assert.New(t)
+
+	var codeVerifier string
+	mock := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if req.Method == http.MethodPost {
+			req.ParseForm()
+			codeVerifier = req.PostForm.Get("code_verifier")
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"access_token": "test_access_token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		json.NewEncoder(rw).Encode(oauth.Profile{Email: "bailey@blend.com"})
+	}))
+	defer mock.Close()
+
+	manager := oauth.MustNew(
+		oauth.OptClientID("test_client_id"),
+		oauth.OptSecret([]byte("test_secret")),
+		oauth.OptRedirectURI("https://app.example.com/oauth/google"),
+		oauth.OptFetchProfileDefaults(r2.OptURL(mock.URL)),
+	)
+	// the code exchange uses the http client from the request context.
+	mockClient := func(action Action) Action {
+		return func(r *Ctx) Result {
+			r.WithContext(context.WithValue(r.Context(), oauth2.HTTPClient, &http.Client{Transport: oauthTestTransport{mock}}))
+			return action(r)
+		}
+	}
+
+	app := MustNew()
+	app.GET("/login", func(r *Ctx) Result {
+		return r.OAuthRedirect("/dashboard")
+	}, OAuthAware(manager, OptOAuthPKCE(true)))
+	app.GET("/oauth/google", func(r *Ctx) Result {
+		result, err := r.OAuthCallback()
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		return Text.Result(result.Profile.Email + " " + result.State.RedirectURI)
+	}, OAuthAware(manager, OptOAuthPKCE(true)), mockClient)
+
+	location, cookie := oauthTestLogin(t, app)
+	contents, meta, err := MockGet(app, "/oauth/google",
+		r2.OptQueryValue("code", "test_code"),
+		r2.OptQueryValue("state", location.Query().Get("state")),
+		r2.OptCookieValue(DefaultOAuthCookieName, cookie.Value),
+	).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode, string(contents))
+	assert.Equal("bailey@blend.com /dashboard", string(contents))
+	assert.Equal(oauth.PKCEChallenge(codeVerifier), location.Query().Get("code_challenge"))
+}
+
+func TestCtxOAuthUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	_, err := rc.OAuthCallback()
+	assert.True(ex.Is(err, ErrOAuthUnset))
+}
@@ -4,10 +4,12 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/blend/go-sdk/env"
 	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
 )
 
 // Option is an option for an app.
@@ -62,6 +64,14 @@ func OptPort(port int32) Option {
 	}
 }
 
+// OptMaxBodyBytes sets the maximum request body size read by `Ctx.PostBody()`.
+func OptMaxBodyBytes(maxBytes int64) Option {
+	return func(a *App) error {
+		a.Config.MaxBodyBytes = maxBytes
+		return nil
+	}
+}
+
 // OptLog sets the logger.
 func OptLog(log logger.Log) Option {
 	return func(a *App) error {
@@ -148,18 +158,65 @@ func OptUse(m Middleware) Option {
 	}
 }
 
-// OptMethodNotAllowedHandler sets default headers.
+// OptMethodNotAllowedHandler sets the action used to render 405s.
+// The app default middleware set by options before this one will apply to the action.
 func OptMethodNotAllowedHandler(action Action) Option {
 	return func(a *App) error {
-		a.MethodNotAllowedHandler = a.RenderAction(action)
+		a.SetMethodNotAllowedHandler(action)
+		return nil
+	}
+}
+
+// OptHandleMethodNotAllowed sets if requests for a path that has routes registered for other methods
+// are responded to with a 405 and an `Allow` header listing the registered methods, rather than a 404.
+// The response can be customized with `OptMethodNotAllowedHandler`.
+// It is disabled by default.
+func OptHandleMethodNotAllowed(enabled bool) Option {
+	return func(a *App) error {
+		a.Config.HandleMethodNotAllowed = enabled
 		return nil
 	}
 }
 
-// OptNotFoundHandler sets default headers.
+// OptDisallowedMethods sets the methods that are rejected with a 405 before routing, e.g. `TRACE` and `CONNECT`.
+// The 405 has an `Allow` header listing the methods registered for the path.
+// The response can be customized with `OptMethodNotAllowedHandler`.
+// `TRACE` is disallowed by default; call `OptDisallowedMethods()` with no methods to allow every method.
+func OptDisallowedMethods(methods ...string) Option {
+	return func(a *App) error {
+		a.Config.DisallowedMethods = make([]string, 0, len(methods))
+		for _, method := range methods {
+			a.Config.DisallowedMethods = append(a.Config.DisallowedMethods, strings.ToUpper(method))
+		}
+		return nil
+	}
+}
+
+// OptHandleOptions sets if `OPTIONS` requests for a path without an explicitly registered `OPTIONS` route
+// are responded to with a 204 and an `Allow` header listing the registered methods.
+// Explicitly registered `OPTIONS` routes always take precedence.
+// It is disabled by default.
+func OptHandleOptions(enabled bool) Option {
+	return func(a *App) error {
+		a.Config.HandleOptions = enabled
+		return nil
+	}
+}
+
+// OptNotFoundHandler sets the action used to render 404s.
+// The app default middleware set by options before this one will apply to the action.
 func OptNotFoundHandler(action Action) Option {
 	return func(a *App) error {
-		a.NotFoundHandler = a.RenderAction(action)
+		a.SetNotFoundHandler(action)
+		return nil
+	}
+}
+
+// OptErrorHandler sets the action used to translate errors returned by actions into results.
+// If the handler returns nil, `DefaultErrorHandler` is used.
+func OptErrorHandler(handler ErrorAction) Option {
+	return func(a *App) error {
+		a.ErrorAction = handler
 		return nil
 	}
 }
@@ -171,3 +228,91 @@ func OptShutdownGracePeriod(d time.Duration) Option {
 		return nil
 	}
 }
+
+// OptReusePort sets if `SO_REUSEPORT` is set on the listener, so a new instance of the app can
+// bind the same port before the old instance stops. It is ignored on platforms that don't support it.
+func OptReusePort(reusePort bool) Option {
+	return func(a *App) error {
+		a.Config.ReusePort = reusePort
+		return nil
+	}
+}
+
+// OptInheritListener sets if the app uses the listener passed by the parent process, if any, instead of binding
+// the bind address. See `App.ListenInherited`.
+func OptInheritListener(inheritListener bool) Option {
+	return func(a *App) error {
+		a.Config.InheritListener = inheritListener
+		return nil
+	}
+}
+
+// OptRedirectTrailingSlash sets if requests for a path that differs from a registered route
+// only by a trailing slash are redirected to the registered route.
+// GET requests are redirected with a 301, and other methods with a 307 so the method and body are preserved.
+// It is enabled by default.
+func OptRedirectTrailingSlash(enabled bool) Option {
+	return func(a *App) error {
+		a.Config.SkipRedirectTrailingSlash = !enabled
+		return nil
+	}
+}
+
+// OptStrictSlash sets the app to treat paths that differ only by a trailing slash
+// as distinct, i.e. `/users/` will 404 if only `/users` is registered.
+// It is equivalent to `OptRedirectTrailingSlash(false)`.
+func OptStrictSlash() Option {
+	return OptRedirectTrailingSlash(false)
+}
+
+// OptCaseInsensitiveRouting sets if request paths are matched to routes case insensitively,
+// i.e. `/Users/Bailey` will match a route registered as `/users/:name`.
+// Route parameter values and `ctx.Request` keep the casing of the original request.
+// It is disabled by default.
+func OptCaseInsensitiveRouting(enabled bool) Option {
+	return func(a *App) error {
+		a.Config.CaseInsensitiveRouting = enabled
+		return nil
+	}
+}
+
+// OptAccessLogFields sets the standard fields written in the json form of http response events, e.g. to add
+// `logger.HTTPResponseFieldReferer` or drop `logger.HTTPResponseFieldUserAgent`.
+// It returns an error if any of the fields aren't one of the `logger.HTTPResponseFields`.
+func OptAccessLogFields(fields ...string) Option {
+	return func(a *App) error {
+		if err := logger.ValidateHTTPResponseFields(fields...); err != nil {
+			return err
+		}
+		a.AccessLogFields = fields
+		return nil
+	}
+}
+
+// OptTrustedProxies sets the proxies whose forwarding headers are trusted when determining
+// the client ip with `Ctx.RealIP()`.
+func OptTrustedProxies(trustedProxies *webutil.IPAllowlist) Option {
+	return func(a *App) error {
+		a.TrustedProxies = trustedProxies
+		return nil
+	}
+}
+
+// OptAccessLogSlowThreshold sets the app to only write the http response events of requests that take at least a threshold.
+// Slow requests also log a warning with the elapsed time and the span breakdown from `Ctx.StartSpan(...)`.
+// Faster requests aren't written, but the events are still delivered to listeners, e.g. for metrics.
+// The warning is only written if the `logger.Warning` flag is enabled, which it isn't by default.
+func OptAccessLogSlowThreshold(threshold time.Duration) Option {
+	return func(a *App) error {
+		a.AccessLogSlowThreshold = threshold
+		return nil
+	}
+}
+
+// OptAccessLogExtra sets a function that returns custom fields written in the json form of http response events.
+func OptAccessLogExtra(extra func(*Ctx) map[string]interface{}) Option {
+	return func(a *App) error {
+		a.AccessLogExtra = extra
+		return nil
+	}
+}
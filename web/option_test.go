@@ -3,6 +3,7 @@ package web
 import (
 	"testing"
 
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/logger"
 
 	"github.com/blend/go-sdk/assert"
@@ -40,3 +41,63 @@ func TestOptLog(t *testing.T) {
 	assert.Nil(OptLog(logger.None())(&app))
 	assert.NotNil(app.Log)
 }
+
+func TestOptRedirectTrailingSlash(t *testing.T) {
+	assert := assert.New(t)
+
+	var app App
+	assert.Nil(OptRedirectTrailingSlash(false)(&app))
+	assert.True(app.Config.SkipRedirectTrailingSlash)
+	assert.Nil(OptRedirectTrailingSlash(true)(&app))
+	assert.False(app.Config.SkipRedirectTrailingSlash)
+	assert.Nil(OptStrictSlash()(&app))
+	assert.True(app.Config.SkipRedirectTrailingSlash)
+}
+
+func TestOptCaseInsensitiveRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	var app App
+	assert.Nil(OptCaseInsensitiveRouting(true)(&app))
+	assert.True(app.Config.CaseInsensitiveRouting)
+}
+
+func TestOptHandleMethodNotAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	var app App
+	assert.Nil(OptHandleMethodNotAllowed(true)(&app))
+	assert.True(app.Config.HandleMethodNotAllowed)
+	assert.Nil(OptHandleMethodNotAllowed(false)(&app))
+	assert.False(app.Config.HandleMethodNotAllowed)
+}
+
+func TestOptHandleOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	var app App
+	assert.Nil(OptHandleOptions(true)(&app))
+	assert.True(app.Config.HandleOptions)
+}
+
+func TestOptAccessLogFields(t *testing.T) {
+	assert := assert.New(t)
+
+	var app App
+	assert.Nil(OptAccessLogFields(logger.HTTPResponseFieldVerb, logger.HTTPResponseFieldReferer)(&app))
+	assert.Equal([]string{logger.HTTPResponseFieldVerb, logger.HTTPResponseFieldReferer}, app.AccessLogFields)
+
+	err := OptAccessLogFields(logger.HTTPResponseFieldVerb, "not-a-field")(&app)
+	assert.True(ex.Is(err, logger.ErrHTTPResponseFieldUnknown))
+}
+
+func TestOptDisallowedMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	var app App
+	assert.Equal(DefaultDisallowedMethods, app.Config.DisallowedMethodsOrDefault())
+	assert.Nil(OptDisallowedMethods("trace", MethodConnect)(&app))
+	assert.Equal([]string{MethodTrace, MethodConnect}, app.Config.DisallowedMethodsOrDefault())
+	assert.Nil(OptDisallowedMethods()(&app))
+	assert.Empty(app.Config.DisallowedMethodsOrDefault())
+}
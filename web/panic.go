@@ -0,0 +1,60 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// panicError returns an error for a value recovered from a panic, with the stack captured at recovery.
+// If the value is an error it is kept as the inner error, so it can be read with `ex.ErrInner`.
+func panicError(rcv interface{}, stack []byte) error {
+	options := []ex.Option{
+		ex.OptStackTrace(ex.StackStrings(strings.Split(strings.TrimSpace(string(stack)), "\n"))),
+	}
+	if err, ok := rcv.(error); ok {
+		options = append(options, ex.OptInnerClass(err))
+	} else if panicFields(rcv) != nil {
+		options = append(options, ex.OptMessagef("%T", rcv))
+	} else {
+		options = append(options, ex.OptMessage(rcv))
+	}
+	return ex.New(ErrPanic, options...)
+}
+
+// panicFields returns the exported fields of a struct (or pointer to struct) panic value,
+// including custom error types. It returns nil for any other value.
+// Field values that can't be marshalled as json, e.g. funcs or chans, are stringified.
+func panicFields(rcv interface{}) map[string]interface{} {
+	value := reflect.ValueOf(rcv)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make(map[string]interface{})
+	valueType := value.Type()
+	for index := 0; index < valueType.NumField(); index++ {
+		field := valueType.Field(index)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldValue := value.Field(index).Interface()
+		if _, err := json.Marshal(fieldValue); err != nil {
+			fieldValue = fmt.Sprint(fieldValue)
+		}
+		fields[field.Name] = fieldValue
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+type panicTestError struct {
+	Code int
+}
+
+func (pte panicTestError) Error() string {
+	return fmt.Sprintf("panic test error: %d", pte.Code)
+}
+
+type panicTestValue struct {
+	Reason string
+	Retry  bool
+	secret string
+}
+
+func recoveredPanicEvent(assert *assert.Assertions, value interface{}) *logger.ErrorEvent {
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(nil))
+	defer log.Close()
+
+	events := make(chan *logger.ErrorEvent, 1)
+	log.Listen(logger.Fatal, "test", logger.NewErrorEventListener(func(_ context.Context, ee *logger.ErrorEvent) {
+		if ex.Is(ee.Err, ErrPanic) {
+			events <- ee
+		}
+	}))
+
+	app := MustNew(OptLog(log))
+	app.GET("/", func(_ *Ctx) Result {
+		panic(value)
+	})
+
+	meta, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, meta.StatusCode)
+	return <-events
+}
+
+func TestAppRecoverPanicError(t *testing.T) {
+	assert := assert.New(t)
+
+	ee := recoveredPanicEvent(assert, panicTestError{Code: 42})
+	assert.Equal(panicTestError{Code: 42}, ex.ErrInner(ee.Err))
+	assert.Equal(42, ee.Fields["Code"])
+	assert.Contains(ex.ErrStackTrace(ee.Err).String(), "panic_test.go", "the stack should be captured where the panic happened")
+}
+
+func TestAppRecoverPanicStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	ee := recoveredPanicEvent(assert, &panicTestValue{Reason: "bad state", Retry: true, secret: "hidden"})
+	assert.Equal(map[string]interface{}{"Reason": "bad state", "Retry": true}, ee.Fields)
+	assert.Equal("*web.panicTestValue", ex.ErrMessage(ee.Err))
+	assert.Nil(ex.ErrInner(ee.Err))
+}
+
+func TestAppRecoverPanicUnmarshalable(t *testing.T) {
+	assert := assert.New(t)
+
+	ee := recoveredPanicEvent(assert, struct {
+		Reason   string
+		Callback func()
+		Done     chan struct{}
+	}{Reason: "bad state", Callback: func() {}, Done: make(chan struct{})})
+	assert.Equal("bad state", ee.Fields["Reason"])
+	_, isString := ee.Fields["Callback"].(string)
+	assert.True(isString)
+	_, isString = ee.Fields["Done"].(string)
+	assert.True(isString)
+
+	_, err := json.Marshal(ee.Fields)
+	assert.Nil(err)
+}
+
+func TestAppRecoverPanicString(t *testing.T) {
+	assert := assert.New(t)
+
+	ee := recoveredPanicEvent(assert, "this is only a test")
+	assert.Equal("this is only a test", ex.ErrMessage(ee.Err))
+	assert.Nil(ee.Fields)
+}
@@ -0,0 +1,185 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ Controller = (*Pprof)(nil)
+)
+
+// OptPprof registers the pprof handlers under a given path prefix, gated by an auth middleware.
+// If the prefix is unset, `DefaultPprofPrefix` is used. The auth middleware is required.
+// The routes are registered when the option is applied, so default middleware added after it does not apply.
+func OptPprof(prefix string, auth Middleware) Option {
+	return func(a *App) error {
+		if auth == nil {
+			return ex.New(ErrPprofAuthUnset)
+		}
+		a.Register(Pprof{Prefix: prefix, Auth: auth})
+		return nil
+	}
+}
+
+// Pprof is a controller that serves the same profiles as `net/http/pprof`.
+// It's built on `runtime/pprof` so it doesn't register the `net/http/pprof` handlers on `http.DefaultServeMux`.
+type Pprof struct {
+	Prefix string
+	Auth   Middleware
+}
+
+// PrefixOrDefault returns the path prefix or a default.
+func (p Pprof) PrefixOrDefault() string {
+	if p.Prefix != "" {
+		return strings.TrimSuffix(p.Prefix, "/")
+	}
+	return DefaultPprofPrefix
+}
+
+// Register implements Controller.
+func (p Pprof) Register(app *App) {
+	prefix := p.PrefixOrDefault()
+	app.GET(prefix+"/", p.index, p.Auth)
+	app.GET(prefix+"/:name", p.profile, p.Auth)
+	app.POST(prefix+"/symbol", p.symbol, p.Auth)
+}
+
+// index renders the pprof index, which links to the individual profiles.
+func (p Pprof) index(r *Ctx) Result {
+	r.Response.Header().Set(HeaderContentType, ContentTypeHTML)
+	fmt.Fprint(r.Response, "<html><head><title>pprof</title></head><body><ul>\n")
+	for _, profile := range pprof.Profiles() {
+		name := html.EscapeString(profile.Name())
+		fmt.Fprintf(r.Response, "<li><a href=\"%s?debug=1\">%s</a> (%d)</li>\n", name, name, profile.Count())
+	}
+	fmt.Fprint(r.Response, "<li><a href=\"cmdline\">cmdline</a></li>\n<li><a href=\"profile\">profile</a></li>\n<li><a href=\"trace?seconds=1\">trace</a></li>\n")
+	fmt.Fprint(r.Response, "</ul></body></html>\n")
+	return nil
+}
+
+// profile renders a named profile.
+func (p Pprof) profile(r *Ctx) Result {
+	name, _ := r.RouteParam("name")
+	switch name {
+	case "cmdline":
+		r.Response.Header().Set(HeaderContentType, ContentTypeText)
+		fmt.Fprint(r.Response, strings.Join(os.Args, "\x00"))
+		return nil
+	case "profile":
+		return p.cpuProfile(r)
+	case "symbol":
+		return p.symbol(r)
+	case "trace":
+		return p.trace(r)
+	}
+
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		return r.DefaultProvider.NotFound()
+	}
+	debug, _ := strconv.Atoi(r.Request.URL.Query().Get("debug"))
+	if name == "heap" && r.Request.URL.Query().Get("gc") != "" {
+		runtime.GC()
+	}
+	if debug != 0 {
+		r.Response.Header().Set(HeaderContentType, ContentTypeText)
+	} else {
+		pprofAttachment(r, name)
+	}
+	profile.WriteTo(r.Response, debug)
+	return nil
+}
+
+// cpuProfile renders a cpu profile for the number of seconds in the query, or 30 seconds.
+func (p Pprof) cpuProfile(r *Ctx) Result {
+	buffer := new(bytes.Buffer)
+	if err := pprof.StartCPUProfile(buffer); err != nil {
+		return r.DefaultProvider.InternalError(ex.New(err))
+	}
+	pprofSleep(r, 30*time.Second)
+	pprof.StopCPUProfile()
+	pprofAttachment(r, "profile")
+	r.Response.Write(buffer.Bytes())
+	return nil
+}
+
+// trace renders an execution trace for the number of seconds in the query, or a second.
+func (p Pprof) trace(r *Ctx) Result {
+	buffer := new(bytes.Buffer)
+	if err := trace.Start(buffer); err != nil {
+		return r.DefaultProvider.InternalError(ex.New(err))
+	}
+	pprofSleep(r, time.Second)
+	trace.Stop()
+	pprofAttachment(r, "trace")
+	r.Response.Write(buffer.Bytes())
+	return nil
+}
+
+// symbol handles symbol lookups posted by `go tool pprof`, i.e. `+` separated program counters.
+func (p Pprof) symbol(r *Ctx) Result {
+	r.Response.Header().Set(HeaderContentType, ContentTypeText)
+	output := new(bytes.Buffer)
+	// the symbol count isn't known, but `go tool pprof` only checks it's positive.
+	fmt.Fprint(output, "num_symbols: 1\n")
+
+	var input *bufio.Reader
+	if r.Request.Method == http.MethodPost {
+		body, err := r.PostBody()
+		if err != nil {
+			return r.DefaultProvider.BadRequest(err)
+		}
+		input = bufio.NewReader(bytes.NewReader(body))
+	} else {
+		input = bufio.NewReader(strings.NewReader(r.Request.URL.RawQuery))
+	}
+	for {
+		word, err := input.ReadSlice('+')
+		if err == nil {
+			word = word[:len(word)-1]
+		}
+		if pc, parseErr := strconv.ParseUint(string(word), 0, 64); parseErr == nil && pc != 0 {
+			if fn := runtime.FuncForPC(uintptr(pc)); fn != nil {
+				fmt.Fprintf(output, "%#x %s\n", pc, fn.Name())
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	r.Response.Write(output.Bytes())
+	return nil
+}
+
+// pprofSleep waits for the number of seconds in the query, or a default, or until the request is cancelled.
+func pprofSleep(r *Ctx, defaultDuration time.Duration) {
+	duration := defaultDuration
+	if seconds, err := strconv.ParseInt(r.Request.URL.Query().Get("seconds"), 10, 64); err == nil && seconds > 0 {
+		duration = time.Duration(seconds) * time.Second
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+}
+
+// pprofAttachment sets the headers for a binary profile download.
+func pprofAttachment(r *Ctx, name string) {
+	r.Response.Header().Set(HeaderContentType, "application/octet-stream")
+	r.Response.Header().Set(HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+}
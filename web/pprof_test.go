@@ -0,0 +1,105 @@
+package web
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+func pprofTestAuth(action Action) Action {
+	return func(r *Ctx) Result {
+		if r.Request.Header.Get("Authorization") != "Bearer secret" {
+			return Text.NotAuthorized()
+		}
+		return action(r)
+	}
+}
+
+func TestOptPprof(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptPprof("", pprofTestAuth))
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap", "/debug/pprof/goroutine"} {
+		route, _, _ := app.Lookup(http.MethodGet, path)
+		assert.NotNil(route, path)
+	}
+	route, _, _ := app.Lookup(http.MethodPost, "/debug/pprof/symbol")
+	assert.NotNil(route)
+
+	meta, err := MockGet(app, "/debug/pprof/cmdline").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	contents, meta, err := MockGet(app, "/debug/pprof/", r2.OptHeaderValue("Authorization", "Bearer secret")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), "goroutine")
+
+	meta, err = MockGet(app, "/debug/pprof/goroutine", r2.OptHeaderValue("Authorization", "Bearer secret"), r2.OptQueryValue("debug", "1")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	meta, err = MockGet(app, "/debug/pprof/not-a-profile", r2.OptHeaderValue("Authorization", "Bearer secret")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+}
+
+func TestOptPprofPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptPprof("/_internal/pprof/", pprofTestAuth))
+	route, _, _ := app.Lookup(http.MethodGet, "/_internal/pprof/heap")
+	assert.NotNil(route)
+	route, _, _ = app.Lookup(http.MethodGet, "/debug/pprof/heap")
+	assert.Nil(route)
+}
+
+func TestOptPprofRequiresAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := New(OptPprof("", nil))
+	assert.True(ex.Is(err, ErrPprofAuthUnset))
+
+	app := MustNew()
+	route, _, _ := app.Lookup(http.MethodGet, "/debug/pprof/heap")
+	assert.Nil(route, "pprof should be off by default")
+}
+
+func TestOptPprofHandlers(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptPprof("", pprofTestAuth))
+	auth := r2.OptHeaderValue("Authorization", "Bearer secret")
+
+	contents, meta, err := MockGet(app, "/debug/pprof/cmdline", auth).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.NotEmpty(contents)
+
+	meta, err = MockGet(app, "/debug/pprof/heap", auth).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("application/octet-stream", meta.Header.Get(HeaderContentType))
+
+	pc := reflect.ValueOf(TestOptPprofHandlers).Pointer()
+	contents, meta, err = MockPost(app, "/debug/pprof/symbol", ioutil.NopCloser(strings.NewReader(fmt.Sprintf("%#x", pc))), auth).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.True(strings.HasPrefix(string(contents), "num_symbols: 1\n"))
+	assert.Contains(string(contents), "TestOptPprofHandlers")
+}
+
+func TestOptPprofDefaultServeMux(t *testing.T) {
+	assert := assert.New(t)
+
+	_, pattern := http.DefaultServeMux.Handler(httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil))
+	assert.Empty(pattern, "the pprof handlers shouldn't be registered on the default serve mux")
+}
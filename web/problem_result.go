@@ -0,0 +1,36 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ProblemResult is a json result in the RFC 7807 "problem details" format.
+type ProblemResult struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is an extension member with a machine readable error code.
+	Code string `json:"code,omitempty"`
+}
+
+// Render renders the result.
+func (pr *ProblemResult) Render(ctx *Ctx) error {
+	problem := *pr
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	ctx.Response.Header().Set(HeaderContentType, ContentTypeApplicationProblemJSON)
+	ctx.Response.WriteHeader(problem.Status)
+	return ex.New(json.NewEncoder(ctx.Response).Encode(problem))
+}
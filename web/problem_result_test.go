@@ -0,0 +1,23 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestProblemResultRender(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	w := webutil.NewMockResponse(buf)
+	r := NewCtx(w, webutil.NewMockRequest("GET", "/"))
+
+	assert.Nil((&ProblemResult{Detail: "something broke"}).Render(r))
+	assert.Equal(http.StatusInternalServerError, w.StatusCode())
+	assert.Equal(ContentTypeApplicationProblemJSON, w.Header().Get(HeaderContentType))
+	assert.Equal(`{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"something broke"}`+"\n", buf.String())
+}
@@ -0,0 +1,105 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// ErrProxyResultUnset is returned if a proxy result is rendered without an upstream response.
+	ErrProxyResultUnset ex.Class = "proxy result; upstream response unset"
+)
+
+// DefaultProxyResultHeaders are the upstream headers copied by proxy results by default.
+var DefaultProxyResultHeaders = []string{
+	HeaderContentType,
+	HeaderContentLength,
+	HeaderContentDisposition,
+}
+
+// Proxy returns a result that streams an upstream response.
+func Proxy(res *http.Response) *ProxyResult {
+	return &ProxyResult{
+		Response: res,
+		Headers:  DefaultProxyResultHeaders,
+	}
+}
+
+// ProxyResult is a result that streams an upstream response to the client without buffering it.
+type ProxyResult struct {
+	Response *http.Response
+	Headers  []string
+}
+
+// Render renders the result.
+func (pr *ProxyResult) Render(ctx *Ctx) error {
+	if pr.Response == nil {
+		return ex.New(ErrProxyResultUnset)
+	}
+	if pr.Response.Body == nil {
+		pr.copyHeaders(ctx)
+		ctx.Response.WriteHeader(pr.statusCode())
+		return nil
+	}
+	defer pr.Response.Body.Close()
+
+	body := contextReader{ctx: ctx.Context(), Reader: pr.Response.Body}
+
+	// read the first chunk before writing anything so upstream
+	// errors can still be reported with an appropriate status.
+	first := make([]byte, 32*1024)
+	read, err := body.Read(first)
+	if err != nil && err != io.EOF {
+		ctx.Response.WriteHeader(http.StatusBadGateway)
+		return ex.New(err)
+	}
+
+	pr.copyHeaders(ctx)
+	ctx.Response.WriteHeader(pr.statusCode())
+	if read > 0 {
+		if _, writeErr := ctx.Response.Write(first[:read]); writeErr != nil {
+			return ex.New(writeErr)
+		}
+	}
+	if err == io.EOF {
+		return nil
+	}
+	if _, err = io.Copy(ctx.Response, body); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+func (pr *ProxyResult) statusCode() int {
+	if pr.Response.StatusCode == 0 {
+		return http.StatusOK
+	}
+	return pr.Response.StatusCode
+}
+
+func (pr *ProxyResult) copyHeaders(ctx *Ctx) {
+	for _, header := range pr.Headers {
+		if values, ok := pr.Response.Header[http.CanonicalHeaderKey(header)]; ok {
+			ctx.Response.Header()[http.CanonicalHeaderKey(header)] = values
+		}
+	}
+}
+
+// contextReader is a reader that stops reading when a context is done.
+type contextReader struct {
+	io.Reader
+	ctx context.Context
+}
+
+// Read implements io.Reader.
+func (cr contextReader) Read(contents []byte) (int, error) {
+	if cr.ctx != nil {
+		if err := cr.ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+	return cr.Reader.Read(contents)
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
+
+type closeTrackingBody struct {
+	*strings.Reader
+	closed bool
+}
+
+func (ctb *closeTrackingBody) Close() error {
+	ctb.closed = true
+	return nil
+}
+
+type failingBody struct {
+	closed bool
+}
+
+func (fb *failingBody) Read(_ []byte) (int, error) { return 0, fmt.Errorf("upstream failed") }
+func (fb *failingBody) Close() error               { fb.closed = true; return nil }
+
+func TestProxyResult(t *testing.T) {
+	assert := assert.New(t)
+
+	body := &closeTrackingBody{Reader: strings.NewReader("this is the upstream body")}
+	upstream := &http.Response{
+		StatusCode: http.StatusPartialContent,
+		Header: http.Header{
+			HeaderContentType:        []string{ContentTypeText},
+			HeaderContentDisposition: []string{`attachment; filename="upstream.txt"`},
+			"X-Upstream-Secret":      []string{"do not copy"},
+		},
+		Body: body,
+	}
+
+	buf := new(bytes.Buffer)
+	res := webutil.NewMockResponse(buf)
+	ctx := NewCtx(res, webutil.NewMockRequest("GET", "/"))
+
+	assert.Nil(Proxy(upstream).Render(ctx))
+	assert.Equal(http.StatusPartialContent, res.StatusCode())
+	assert.Equal(ContentTypeText, res.Header().Get(HeaderContentType))
+	assert.Equal(`attachment; filename="upstream.txt"`, res.Header().Get(HeaderContentDisposition))
+	assert.Empty(res.Header().Get("X-Upstream-Secret"))
+	assert.Equal("this is the upstream body", buf.String())
+	assert.True(body.closed)
+}
+
+func TestProxyResultUpstreamError(t *testing.T) {
+	assert := assert.New(t)
+
+	body := &failingBody{}
+	buf := new(bytes.Buffer)
+	res := webutil.NewMockResponse(buf)
+	ctx := NewCtx(res, webutil.NewMockRequest("GET", "/"))
+
+	err := Proxy(&http.Response{StatusCode: http.StatusOK, Header: http.Header{HeaderContentType: []string{ContentTypeText}}, Body: body}).Render(ctx)
+	assert.NotNil(err)
+	assert.Equal("upstream failed", ex.ErrClass(err).Error())
+	assert.Equal(http.StatusBadGateway, res.StatusCode())
+	assert.Empty(res.Header().Get(HeaderContentType))
+	assert.True(body.closed)
+
+	assert.True(ex.Is((&ProxyResult{}).Render(ctx), ErrProxyResultUnset))
+}
+
+func TestProxyResultContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	body := &closeTrackingBody{Reader: strings.NewReader("this is the upstream body")}
+	buf := new(bytes.Buffer)
+	res := webutil.NewMockResponse(buf)
+	ctx := NewCtx(res, webutil.NewMockRequest("GET", "/"))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx.WithContext(cancelled)
+
+	err := Proxy(&http.Response{StatusCode: http.StatusOK, Body: body}).Render(ctx)
+	assert.True(ex.Is(err, context.Canceled))
+	assert.Empty(buf.String())
+	assert.True(body.closed)
+}
+
+func TestProxyResultApp(t *testing.T) {
+	assert := assert.New(t)
+
+	upstreamApp := MustNew()
+	upstreamApp.GET("/download", func(_ *Ctx) Result {
+		return RawWithContentType(ContentTypeText, []byte(strings.Repeat("a", 64*1024)))
+	})
+	upstream := MockGet(upstreamApp, "/download")
+	defer upstream.Close()
+	upstreamRes, err := upstream.Do()
+	assert.Nil(err)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return Proxy(upstreamRes) })
+
+	contents, meta, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal(ContentTypeText, meta.Header.Get(HeaderContentType))
+	assert.Equal(64*1024, len(contents))
+	_, err = ioutil.ReadAll(upstreamRes.Body)
+	assert.NotNil(err, "the upstream body should be closed")
+}
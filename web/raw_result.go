@@ -17,18 +17,26 @@ type RawResult struct {
 	StatusCode  int
 	ContentType string
 	Response    []byte
+	// Buffered, if set, sets the "Content-Length" header.
+	Buffered bool
+	// Headers are optional headers set on the response before the status is written, e.g. "X-Total-Count".
+	Headers http.Header
 }
 
 // Render renders the result.
 func (rr *RawResult) Render(ctx *Ctx) error {
+	writeResultHeaders(ctx, rr.Headers)
 	if len(rr.ContentType) != 0 {
 		ctx.Response.Header().Set("Content-Type", rr.ContentType)
 	}
-	if rr.StatusCode == 0 {
-		ctx.Response.WriteHeader(http.StatusOK)
-	} else {
-		ctx.Response.WriteHeader(rr.StatusCode)
+	statusCode := rr.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
 	}
+	if rr.Buffered {
+		return WriteWithContentLength(ctx, statusCode, rr.Response)
+	}
+	ctx.Response.WriteHeader(statusCode)
 	_, err := ctx.Response.Write(rr.Response)
 	return err
 }
@@ -0,0 +1,43 @@
+package web
+
+import (
+	"net"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// RealIP returns the client ip for the request, or nil if it can't be determined.
+// Forwarding headers are only considered if the immediate peer is one of the app trusted proxies.
+func (rc *Ctx) RealIP() net.IP {
+	rc.realIPOnce.Do(func() {
+		var trustedProxies *webutil.IPAllowlist
+		if rc.App != nil {
+			trustedProxies = rc.App.TrustedProxies
+		}
+		rc.realIP = webutil.GetClientIP(rc.Request, trustedProxies)
+	})
+	return rc.realIP
+}
+
+// RemoteAddr returns the client ip recorded in access logs, sessions and traces.
+// It is `RealIP()` if the app has trusted proxies set, and otherwise `webutil.GetRemoteAddr`, which trusts
+// forwarding headers from any peer, for compatibility with apps that don't set trusted proxies.
+func (rc *Ctx) RemoteAddr() string {
+	if rc.App == nil || rc.App.TrustedProxies == nil {
+		return webutil.GetRemoteAddr(rc.Request)
+	}
+	if clientIP := rc.RealIP(); clientIP != nil {
+		return clientIP.String()
+	}
+	return ""
+}
+
+// IsTLS returns if the original request was made over https.
+// The forwarded protocol headers are only trusted from the app trusted proxies, as with `RealIP()`.
+func (rc *Ctx) IsTLS() bool {
+	var trustedProxies *webutil.IPAllowlist
+	if rc.App != nil {
+		trustedProxies = rc.App.TrustedProxies
+	}
+	return webutil.IsTLS(rc.Request, webutil.OptIsTLSTrustedProxies(trustedProxies))
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestCtxRealIP(t *testing.T) {
+	assert := assert.New(t)
+
+	am, err := NewLocalAuthManager()
+	assert.Nil(err)
+
+	var realIP, accessLogIP, sessionIP string
+	app := MustNew(OptTrustedProxies(webutil.MustIPAllowlist([]string{"127.0.0.1", "::1"})))
+	allow := webutil.MustIPAllowlist([]string{"10.0.0.0/8"})
+	app.GET("/", func(r *Ctx) Result {
+		realIP = r.RealIP().String()
+		accessLogIP = app.httpResponseEvent(r).ClientIP
+		session, err := am.Login("bailey", r)
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		sessionIP = session.RemoteAddr
+		return NoContent
+	}, IPFilter(allow, nil))
+
+	meta, err := MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderXForwardedFor, "11.1.2.3, 10.1.2.3")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Equal("10.1.2.3", realIP)
+	assert.Equal(realIP, accessLogIP)
+	assert.Equal(realIP, sessionIP)
+
+	meta, err = MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderXForwardedFor, "11.1.2.3")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+}
+
+func TestCtxRealIPUntrusted(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	rc.Request.RemoteAddr = "192.168.1.1:4321"
+	rc.Request.Header.Set(webutil.HeaderXForwardedFor, "10.1.2.3")
+	assert.Equal("192.168.1.1", rc.RealIP().String(), "forwarding headers from untrusted peers should be ignored")
+
+	rc.Request.RemoteAddr = "192.168.1.2:4321"
+	assert.Equal("192.168.1.1", rc.RealIP().String(), "the real ip should be cached")
+}
+
+func TestCtxRemoteAddr(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	rc := MockCtx("GET", "/", OptCtxApp(app))
+	rc.Request.RemoteAddr = "192.168.1.1:4321"
+	rc.Request.Header.Set(webutil.HeaderXForwardedFor, "10.1.2.3")
+	assert.Equal("10.1.2.3", rc.RemoteAddr(), "without trusted proxies the remote addr should be unchanged")
+	assert.Equal("10.1.2.3", app.httpResponseEvent(rc).ClientIP)
+	assert.Equal("192.168.1.1", rc.RealIP().String())
+
+	app = MustNew(OptTrustedProxies(webutil.MustIPAllowlist(nil)))
+	rc = MockCtx("GET", "/", OptCtxApp(app))
+	rc.Request.RemoteAddr = "192.168.1.1:4321"
+	rc.Request.Header.Set(webutil.HeaderXForwardedFor, "10.1.2.3")
+	assert.Equal("192.168.1.1", rc.RemoteAddr())
+}
@@ -39,11 +39,19 @@ func RedirectWithMethodf(method, format string, args ...interface{}) *RedirectRe
 type RedirectResult struct {
 	Method      string `json:"redirect_method"`
 	RedirectURI string `json:"redirect_uri"`
+	// StatusCode is the redirect status code, e.g. `http.StatusSeeOther`.
+	// If unset, it is 302 if a method is set and 307 otherwise.
+	StatusCode int `json:"redirect_status_code,omitempty"`
 }
 
 // Render writes the result to the response.
 func (rr *RedirectResult) Render(ctx *Ctx) error {
-	if len(rr.Method) > 0 {
+	if rr.StatusCode > 0 {
+		if len(rr.Method) > 0 {
+			ctx.Request.Method = rr.Method
+		}
+		http.Redirect(ctx.Response, ctx.Request, rr.RedirectURI, rr.StatusCode)
+	} else if len(rr.Method) > 0 {
 		ctx.Request.Method = rr.Method
 		http.Redirect(ctx.Response, ctx.Request, rr.RedirectURI, http.StatusFound)
 	} else {
@@ -1,5 +1,10 @@
 package web
 
+import (
+	"net/http"
+	"strconv"
+)
+
 // Result is the result of a controller.
 type Result interface {
 	Render(ctx *Ctx) error
@@ -15,6 +20,26 @@ type ResultPostRender interface {
 	PostRender(ctx *Ctx) error
 }
 
+// WriteWithContentLength writes a status code and body with an accurate `Content-Length` header,
+// so the response isn't sent with chunked encoding. Compressing response writers remove the header.
+func WriteWithContentLength(ctx *Ctx, statusCode int, contents []byte) error {
+	ctx.Response.Header().Set(HeaderContentLength, strconv.Itoa(len(contents)))
+	ctx.Response.WriteHeader(statusCode)
+	_, err := ctx.Response.Write(contents)
+	return err
+}
+
+// writeResultHeaders sets a result's custom headers on the response, canonicalizing the keys.
+func writeResultHeaders(ctx *Ctx, headers http.Header) {
+	for key, values := range headers {
+		key = http.CanonicalHeaderKey(key)
+		ctx.Response.Header().Del(key)
+		for _, value := range values {
+			ctx.Response.Header().Add(key, value)
+		}
+	}
+}
+
 // ResultWithLoggedError logs an error before it renders the result.
 func ResultWithLoggedError(result Result, err error) *LoggedErrorResult {
 	return &LoggedErrorResult{
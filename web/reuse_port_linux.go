@@ -0,0 +1,8 @@
+//go:build !mips && !mipsle && !mips64 && !mips64le
+// +build !mips,!mipsle,!mips64,!mips64le
+
+package web
+
+// soReusePort is the `SO_REUSEPORT` socket option on linux, other than on mips.
+// It's defined here as the syscall package only defines it on some architectures (e.g. not amd64, 386 or arm).
+const soReusePort = 0xf
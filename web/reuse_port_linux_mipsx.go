@@ -0,0 +1,8 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+// +build linux
+// +build mips mipsle mips64 mips64le
+
+package web
+
+// soReusePort is the `SO_REUSEPORT` socket option on linux mips, where it differs from other architectures.
+const soReusePort = 0x200
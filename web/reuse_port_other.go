@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package web
+
+import "syscall"
+
+// reusePortControl is a no-op on platforms that don't support `SO_REUSEPORT`.
+func reusePortControl(_, _ string, _ syscall.RawConn) error {
+	return nil
+}
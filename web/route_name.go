@@ -0,0 +1,95 @@
+package web
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// RouteRegistration is returned when registering a route with `App.GET(...)` etc., and is used to name the route.
+type RouteRegistration struct {
+	App    *App
+	Method string
+	Path   string
+}
+
+// Name names the route so its path can be built with `App.Path(...)`.
+// It panics if the name is already in use, as with registering conflicting routes.
+func (rr RouteRegistration) Name(name string) RouteRegistration {
+	if _, ok := rr.App.NamedRoutes[name]; ok {
+		panic("route name '" + name + "' is already registered")
+	}
+	route, _, _ := rr.App.Routes[rr.Method].getValue(rr.Path)
+	if route == nil {
+		route = &Route{Method: rr.Method, Path: rr.Path}
+	}
+	if rr.App.NamedRoutes == nil {
+		rr.App.NamedRoutes = make(map[string]*Route)
+	}
+	rr.App.NamedRoutes[name] = route
+	return rr
+}
+
+// Path returns the path for a named route with its parameters substituted, e.g. `/users/1234` for
+// the route `/users/:id` and the params `{"id": "1234"}`.
+func (a *App) Path(name string, params map[string]string) (string, error) {
+	route, ok := a.NamedRoutes[name]
+	if !ok {
+		return "", ex.New(ErrRouteNameUnknown, ex.OptMessagef("route name: %s", name))
+	}
+	return routePath(route.Path, params)
+}
+
+// routePath substitutes the params of a route path.
+func routePath(path string, params map[string]string) (string, error) {
+	output := new(strings.Builder)
+	for index := 0; index < len(path); index++ {
+		c := path[index]
+		if c != ':' && c != '*' {
+			output.WriteByte(c)
+			continue
+		}
+		end := index + 1
+		for end < len(path) && path[end] != '/' {
+			end++
+		}
+		param := path[index+1 : end]
+		value, ok := params[param]
+		if !ok || value == "" {
+			return "", ex.New(ErrRouteParamMissing, ex.OptMessagef("route: %s, parameter: %s", path, param))
+		}
+		if c == '*' {
+			// catch-all values include the leading slash, which is already in the route path.
+			segments := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for segmentIndex := range segments {
+				segments[segmentIndex] = url.PathEscape(segments[segmentIndex])
+			}
+			output.WriteString(strings.Join(segments, "/"))
+		} else {
+			output.WriteString(url.PathEscape(value))
+		}
+		index = end - 1
+	}
+	return output.String(), nil
+}
+
+// ViewFuncRoutePath is the name of the view function that builds the path for a named route,
+// e.g. `{{ route_path "user.show" "id" .ViewModel.ID }}`.
+const ViewFuncRoutePath = "route_path"
+
+// viewRoutePath is the `route_path` view function.
+func (a *App) viewRoutePath(name string, pairs ...interface{}) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", ex.New(ErrRouteParamMissing, ex.OptMessagef("route name: %s, parameters must be name and value pairs", name))
+	}
+	params := make(map[string]string, len(pairs)/2)
+	for index := 0; index < len(pairs); index += 2 {
+		// missing fields of the view model are nil, and are missing parameters.
+		if pairs[index+1] != nil {
+			params[fmt.Sprint(pairs[index])] = fmt.Sprint(pairs[index+1])
+		}
+	}
+	return a.Path(name, params)
+}
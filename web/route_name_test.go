@@ -0,0 +1,101 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func routeNameTestApp() *App {
+	app := MustNew()
+	noop := func(_ *Ctx) Result { return NoContent }
+	app.GET("/", noop).Name("index")
+	app.GET("/users/:id/posts/:post", noop).Name("user.post")
+	app.GET("/files/*filepath", noop).Name("files")
+	return app
+}
+
+func TestAppPath(t *testing.T) {
+	assert := assert.New(t)
+
+	app := routeNameTestApp()
+
+	path, err := app.Path("index", nil)
+	assert.Nil(err)
+	assert.Equal("/", path)
+
+	path, err = app.Path("user.post", map[string]string{"id": "1234", "post": "5678", "unused": "value"})
+	assert.Nil(err)
+	assert.Equal("/users/1234/posts/5678", path)
+
+	path, err = app.Path("user.post", map[string]string{"id": "a/b", "post": "hello world?#"})
+	assert.Nil(err)
+	assert.Equal("/users/a%2Fb/posts/hello%20world%3F%23", path)
+
+	path, err = app.Path("files", map[string]string{"filepath": "css/site styles.css"})
+	assert.Nil(err)
+	assert.Equal("/files/css/site%20styles.css", path)
+}
+
+func TestAppPathRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/users/:id/posts/:post", func(r *Ctx) Result {
+		return Text.Result(r.RouteParams.Get("id") + "|" + r.RouteParams.Get("post"))
+	}).Name("user.post")
+
+	path, err := app.Path("user.post", map[string]string{"id": "1234", "post": "hello world?#%"})
+	assert.Nil(err)
+	// the path is escaped, so it's parsed rather than set as the (unescaped) url path as with `MockGet`.
+	parsed, err := url.Parse(path)
+	assert.Nil(err)
+	contents, _, err := Mock(app, &http.Request{Method: "GET", URL: parsed}).Bytes()
+	assert.Nil(err)
+	assert.Equal("1234|hello world?#%", string(contents))
+}
+
+func TestAppPathErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	app := routeNameTestApp()
+
+	_, err := app.Path("user.post", map[string]string{"id": "1234"})
+	assert.True(ex.Is(err, ErrRouteParamMissing))
+	assert.Contains(ex.ErrMessage(err), "post")
+
+	_, err = app.Path("user.post", map[string]string{"id": "1234", "post": ""})
+	assert.True(ex.Is(err, ErrRouteParamMissing))
+
+	_, err = app.Path("user.show", map[string]string{"id": "1234"})
+	assert.True(ex.Is(err, ErrRouteNameUnknown))
+
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		app.GET("/users/:id", func(_ *Ctx) Result { return NoContent }).Name("user.post")
+	}()
+	assert.NotNil(recovered, "duplicate route names should panic")
+}
+
+func TestAppPathViewFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	app := routeNameTestApp()
+	app.Views.Literals = append(app.Views.Literals,
+		`{{ define "link" }}<a href="{{ route_path "user.post" "id" .ID "post" .Post }}">post</a>{{ end }}`,
+	)
+	view, err := app.Views.Lookup("link")
+	assert.Nil(err)
+
+	buf := new(bytes.Buffer)
+	assert.Nil(view.Execute(buf, map[string]interface{}{"ID": 1234, "Post": "hello world"}))
+	assert.Equal(`<a href="/users/1234/posts/hello%20world">post</a>`, buf.String())
+
+	buf.Reset()
+	assert.NotNil(view.Execute(buf, map[string]interface{}{"ID": 1234}))
+}
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewSecurityHeadersConfig returns a new security headers config with sane defaults.
+func NewSecurityHeadersConfig(options ...SecurityHeadersOption) SecurityHeadersConfig {
+	cfg := SecurityHeadersConfig{
+		HSTS:                  true,
+		HSTSMaxAge:            DefaultHSTSMaxAge,
+		HSTSIncludeSubDomains: true,
+		ContentTypeOptions:    XContentTypeOptionsNoSniff,
+		FrameOptions:          XFrameOptionsDeny,
+		ReferrerPolicy:        ReferrerPolicyStrictOriginWhenCrossOrigin,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// SecurityHeadersOption mutates a security headers config.
+type SecurityHeadersOption func(*SecurityHeadersConfig)
+
+// OptSecurityHeadersHSTS sets if the hsts header should be emitted.
+func OptSecurityHeadersHSTS(enabled bool) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.HSTS = enabled }
+}
+
+// OptSecurityHeadersHSTSMaxAge sets the hsts max age.
+func OptSecurityHeadersHSTSMaxAge(maxAge time.Duration) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.HSTSMaxAge = maxAge }
+}
+
+// OptSecurityHeadersHSTSIncludeSubDomains sets if the hsts header should include sub domains.
+func OptSecurityHeadersHSTSIncludeSubDomains(includeSubDomains bool) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.HSTSIncludeSubDomains = includeSubDomains }
+}
+
+// OptSecurityHeadersHSTSPreload sets if the hsts header should include the preload token.
+func OptSecurityHeadersHSTSPreload(preload bool) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.HSTSPreload = preload }
+}
+
+// OptSecurityHeadersContentTypeOptions sets the content type options header value.
+// An empty value disables the header.
+func OptSecurityHeadersContentTypeOptions(value string) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.ContentTypeOptions = value }
+}
+
+// OptSecurityHeadersFrameOptions sets the frame options header value.
+// An empty value disables the header.
+func OptSecurityHeadersFrameOptions(value string) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.FrameOptions = value }
+}
+
+// OptSecurityHeadersReferrerPolicy sets the referrer policy header value.
+// An empty value disables the header.
+func OptSecurityHeadersReferrerPolicy(value string) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.ReferrerPolicy = value }
+}
+
+// OptSecurityHeadersContentSecurityPolicy sets the content security policy header value.
+// An empty value disables the header.
+func OptSecurityHeadersContentSecurityPolicy(value string) SecurityHeadersOption {
+	return func(cfg *SecurityHeadersConfig) { cfg.ContentSecurityPolicy = value }
+}
+
+// SecurityHeadersConfig is the configuration for the security headers middleware.
+type SecurityHeadersConfig struct {
+	// HSTS determines if the `Strict-Transport-Security` header is emitted.
+	// It is only ever emitted for https requests.
+	HSTS                  bool
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubDomains bool
+	HSTSPreload           bool
+	// ContentTypeOptions is the value of the `X-Content-Type-Options` header.
+	ContentTypeOptions string
+	// FrameOptions is the value of the `X-Frame-Options` header.
+	FrameOptions string
+	// ReferrerPolicy is the value of the `Referrer-Policy` header.
+	ReferrerPolicy string
+	// ContentSecurityPolicy is the value of the `Content-Security-Policy` header.
+	ContentSecurityPolicy string
+}
+
+// HSTSHeaderValue returns the value for the `Strict-Transport-Security` header.
+func (shc SecurityHeadersConfig) HSTSHeaderValue() string {
+	tokens := []string{fmt.Sprintf(HSTSMaxAgeFormat, int64(shc.HSTSMaxAge/time.Second))}
+	if shc.HSTSIncludeSubDomains {
+		tokens = append(tokens, HSTSIncludeSubDomains)
+	}
+	if shc.HSTSPreload {
+		tokens = append(tokens, HSTSPreload)
+	}
+	return strings.Join(tokens, "; ")
+}
+
+// SecurityHeaders returns a middleware that sets common security headers on responses.
+func SecurityHeaders(options ...SecurityHeadersOption) Middleware {
+	cfg := NewSecurityHeadersConfig(options...)
+	hsts := cfg.HSTSHeaderValue()
+	return func(action Action) Action {
+		return func(r *Ctx) Result {
+			header := r.Response.Header()
+			if cfg.HSTS && r.IsTLS() {
+				header.Set(HeaderStrictTransportSecurity, hsts)
+			}
+			if cfg.ContentTypeOptions != "" {
+				header.Set(HeaderXContentTypeOptions, cfg.ContentTypeOptions)
+			}
+			if cfg.FrameOptions != "" {
+				header.Set(HeaderXFrameOptions, cfg.FrameOptions)
+			}
+			if cfg.ReferrerPolicy != "" {
+				header.Set(HeaderReferrerPolicy, cfg.ReferrerPolicy)
+			}
+			if cfg.ContentSecurityPolicy != "" {
+				header.Set(HeaderContentSecurityPolicy, cfg.ContentSecurityPolicy)
+			}
+			return action(r)
+		}
+	}
+}
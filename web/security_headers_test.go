@@ -0,0 +1,68 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestSecurityHeadersDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptTrustedProxies(webutil.MustIPAllowlist([]string{"127.0.0.1", "::1"})))
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, SecurityHeaders())
+
+	res, err := MockGet(app, "/").Discard()
+	assert.Nil(err)
+	assert.Empty(res.Header.Get(HeaderStrictTransportSecurity), "hsts should not be set for http requests")
+	assert.Equal(XContentTypeOptionsNoSniff, res.Header.Get(HeaderXContentTypeOptions))
+	assert.Equal(XFrameOptionsDeny, res.Header.Get(HeaderXFrameOptions))
+	assert.Equal(ReferrerPolicyStrictOriginWhenCrossOrigin, res.Header.Get(HeaderReferrerPolicy))
+	assert.Empty(res.Header.Get(HeaderContentSecurityPolicy))
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderXForwardedProto, SchemeHTTPS)).Discard()
+	assert.Nil(err)
+	assert.Equal("max-age=31536000; includeSubDomains", res.Header.Get(HeaderStrictTransportSecurity))
+}
+
+func TestSecurityHeadersOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptTrustedProxies(webutil.MustIPAllowlist([]string{"127.0.0.1", "::1"})))
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, SecurityHeaders(
+		OptSecurityHeadersHSTSMaxAge(time.Hour),
+		OptSecurityHeadersHSTSIncludeSubDomains(false),
+		OptSecurityHeadersHSTSPreload(true),
+		OptSecurityHeadersContentTypeOptions(""),
+		OptSecurityHeadersFrameOptions(XFrameOptionsSameOrigin),
+		OptSecurityHeadersReferrerPolicy(""),
+		OptSecurityHeadersContentSecurityPolicy("default-src 'self'"),
+	))
+	app.GET("/nohsts", func(_ *Ctx) Result { return NoContent }, SecurityHeaders(OptSecurityHeadersHSTS(false)))
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderXForwardedProto, SchemeHTTPS)).Discard()
+	assert.Nil(err)
+	assert.Equal("max-age=3600; preload", res.Header.Get(HeaderStrictTransportSecurity))
+	assert.Empty(res.Header.Get(HeaderXContentTypeOptions))
+	assert.Equal(XFrameOptionsSameOrigin, res.Header.Get(HeaderXFrameOptions))
+	assert.Empty(res.Header.Get(HeaderReferrerPolicy))
+	assert.Equal("default-src 'self'", res.Header.Get(HeaderContentSecurityPolicy))
+
+	res, err = MockGet(app, "/nohsts", r2.OptHeaderValue(webutil.HeaderXForwardedProto, SchemeHTTPS)).Discard()
+	assert.Nil(err)
+	assert.Empty(res.Header.Get(HeaderStrictTransportSecurity))
+}
+
+func TestSecurityHeadersUntrustedForwardedProto(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result { return NoContent }, SecurityHeaders())
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderXForwardedProto, SchemeHTTPS)).Discard()
+	assert.Nil(err)
+	assert.Empty(res.Header.Get(HeaderStrictTransportSecurity), "the forwarded proto should only be trusted from trusted proxies")
+}
@@ -8,6 +8,7 @@ func SessionAware(action Action) Action {
 			return ctx.DefaultProvider.InternalError(err)
 		}
 		ctx.Session = session
+		ctx.WithContext(WithSession(ctx.Context(), session))
 		return action(ctx)
 	}
 }
@@ -24,6 +25,7 @@ func SessionRequired(action Action) Action {
 			return ctx.App.Auth.LoginRedirect(ctx)
 		}
 		ctx.Session = session
+		ctx.WithContext(WithSession(ctx.Context(), session))
 		return action(ctx)
 	}
 }
@@ -44,6 +46,7 @@ func SessionMiddleware(notAuthorized Action) Middleware {
 				return ctx.App.Auth.LoginRedirect(ctx)
 			}
 			ctx.Session = session
+			ctx.WithContext(WithSession(ctx.Context(), session))
 			return action(ctx)
 		}
 	}
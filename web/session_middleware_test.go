@@ -23,7 +23,7 @@ func TestSessionAware(t *testing.T) {
 
 	app.GET("/", func(r *Ctx) Result {
 		didExecuteHandler = true
-		sessionWasSet = r.Session != nil
+		sessionWasSet = r.Session != nil && GetSession(r.Context()) == r.Session
 		return Text.Result("COOL")
 	}, SessionAware)
 
@@ -129,3 +129,73 @@ func TestSessionMiddleware(t *testing.T) {
 	assert.True(sessionWasSet)
 	assert.True(calledCustom)
 }
+
+func TestSessionValues(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := NewLocalSessionCache()
+	app := MustNew(OptAuth(NewLocalAuthManagerFromCache(cache)))
+	cookieName := app.Auth.CookieDefaults.Name
+
+	app.POST("/login", func(r *Ctx) Result {
+		if _, err := r.App.Auth.Login("bailey", r); err != nil {
+			return Text.InternalError(err)
+		}
+		return NoContent
+	})
+	app.GET("/cart", func(r *Ctx) Result {
+		value, _ := r.SessionValue("cart").(string)
+		return Text.Result(value)
+	}, SessionRequired)
+	app.POST("/cart/:item", func(r *Ctx) Result {
+		if err := r.SetSessionValue("cart", r.RouteParams.Get("item")); err != nil {
+			return Text.InternalError(err)
+		}
+		return NoContent
+	}, SessionRequired)
+	app.POST("/logout", func(r *Ctx) Result {
+		if err := r.DestroySession(); err != nil {
+			return Text.InternalError(err)
+		}
+		return NoContent
+	}, SessionRequired)
+
+	// create.
+	meta, err := MockPost(app, "/login", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	cookies := ReadSetCookies(meta.Header)
+	assert.NotEmpty(cookies)
+	sessionID := cookies[0].Value
+	assert.NotNil(cache.Get(sessionID))
+
+	// persist.
+	meta, err = MockPost(app, "/cart/socks", nil, r2.OptCookieValue(cookieName, sessionID)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Equal("socks", cache.Get(sessionID).State["cart"])
+
+	contents, _, err := MockGet(app, "/cart", r2.OptCookieValue(cookieName, sessionID)).Bytes()
+	assert.Nil(err)
+	assert.Equal("socks", string(contents))
+
+	// destroy.
+	meta, err = MockPost(app, "/logout", nil, r2.OptCookieValue(cookieName, sessionID)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Nil(cache.Get(sessionID))
+
+	meta, err = MockGet(app, "/cart", r2.OptCookieValue(cookieName, sessionID)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+}
+
+func TestSessionValuesUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("GET", "/")
+	assert.Nil(rc.SessionValue("foo"))
+	assert.NotNil(rc.SetSessionValue("foo", "bar"))
+	assert.NotNil(rc.RegenerateSession())
+	assert.NotNil(rc.DestroySession())
+}
@@ -2,11 +2,18 @@ package web
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"os"
+	"path"
 	"regexp"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/webutil"
 )
 
 // NewStaticFileServer returns a new static file cache.
@@ -42,16 +49,25 @@ func OptStaticFileServerCacheDisabled(cacheDisabled bool) StaticFileserverOption
 	}
 }
 
+// OptStaticFileServerPrecompressedDisabled sets if the static fileserver should skip
+// looking for gzip precompressed variants of files.
+func OptStaticFileServerPrecompressedDisabled(precompressedDisabled bool) StaticFileserverOption {
+	return func(sfs *StaticFileServer) {
+		sfs.PrecompressedDisabled = precompressedDisabled
+	}
+}
+
 // StaticFileServer is a cache of static files.
 // It can operate in cached mode, or with `CacheDisabled` it will read from
 // disk for each request.
 type StaticFileServer struct {
 	sync.RWMutex
-	SearchPaths   []http.FileSystem
-	RewriteRules  []RewriteRule
-	Headers       http.Header
-	CacheDisabled bool
-	Cache         map[string]*CachedStaticFile
+	SearchPaths           []http.FileSystem
+	RewriteRules          []RewriteRule
+	Headers               http.Header
+	CacheDisabled         bool
+	PrecompressedDisabled bool
+	Cache                 map[string]*CachedStaticFile
 }
 
 // AddHeader adds a header to the static cache results.
@@ -107,6 +123,16 @@ func (sc *StaticFileServer) Action(r *Ctx) Result {
 // ServeFile writes the file to the response by reading from disk
 // for each request (i.e. skipping the cache)
 func (sc *StaticFileServer) ServeFile(r *Ctx, filePath string) Result {
+	if sc.acceptsPrecompressed(r, filePath) {
+		if f, err := sc.openFile(sc.rewritePath(filePath) + precompressedExtension); err == nil && f != nil {
+			defer f.Close()
+			if finfo, err := f.Stat(); err == nil && !finfo.IsDir() {
+				servePrecompressed(r, filePath, finfo.ModTime(), f)
+				return nil
+			}
+		}
+	}
+
 	f, err := sc.ResolveFile(filePath)
 	if f == nil || (err != nil && os.IsNotExist(err)) {
 		if r.DefaultProvider != nil {
@@ -139,6 +165,16 @@ func (sc *StaticFileServer) ServeFile(r *Ctx, filePath string) Result {
 // ServeCachedFile writes the file to the response, potentially
 // serving a cached instance of the file.
 func (sc *StaticFileServer) ServeCachedFile(r *Ctx, filepath string) Result {
+	if sc.acceptsPrecompressed(r, filepath) {
+		file, err := sc.resolveCachedFile(filepath+precompressedCacheKeySuffix, func() (http.File, error) {
+			return sc.openFile(sc.rewritePath(filepath) + precompressedExtension)
+		})
+		if err == nil && file != nil {
+			servePrecompressed(r, filepath, file.ModTime, file.Contents)
+			return nil
+		}
+	}
+
 	file, err := sc.ResolveCachedFile(filepath)
 	if err != nil {
 		if r.DefaultProvider != nil {
@@ -162,12 +198,36 @@ func (sc *StaticFileServer) ServeCachedFile(r *Ctx, filepath string) Result {
 // First the file path is modified according to the rewrite rules.
 // Then each search path is checked for the resolved file path.
 func (sc *StaticFileServer) ResolveFile(filePath string) (f http.File, err error) {
+	return sc.openFile(sc.rewritePath(filePath))
+}
+
+// ResolveCachedFile returns a cached file at a given path.
+// It returns the cached instance of a file if it exists, and adds it to the cache if there is a miss.
+func (sc *StaticFileServer) ResolveCachedFile(filepath string) (*CachedStaticFile, error) {
+	return sc.resolveCachedFile(filepath, func() (http.File, error) {
+		return sc.ResolveFile(filepath)
+	})
+}
+
+const (
+	precompressedExtension = ".gz"
+	// precompressedCacheKeySuffix distinguishes cached precompressed variants from
+	// direct requests for `.gz` files, which are resolved differently.
+	precompressedCacheKeySuffix = "\x00" + ContentEncodingGZIP
+)
+
+// rewritePath applies the rewrite rules to a file path.
+func (sc *StaticFileServer) rewritePath(filePath string) string {
 	for _, rule := range sc.RewriteRules {
 		if matched, newFilePath := rule.Apply(filePath); matched {
 			filePath = newFilePath
 		}
 	}
+	return filePath
+}
 
+// openFile opens the first file found at a path in the search paths.
+func (sc *StaticFileServer) openFile(filePath string) (f http.File, err error) {
 	// for each searchpath, sniff if the file exists ...
 	var openErr error
 	for _, searchPath := range sc.SearchPaths {
@@ -183,12 +243,11 @@ func (sc *StaticFileServer) ResolveFile(filePath string) (f http.File, err error
 	return
 }
 
-// ResolveCachedFile returns a cached file at a given path.
-// It returns the cached instance of a file if it exists, and adds it to the cache if there is a miss.
-func (sc *StaticFileServer) ResolveCachedFile(filepath string) (*CachedStaticFile, error) {
+// resolveCachedFile returns a cached file for a given key, resolving it and adding it to the cache if there is a miss.
+func (sc *StaticFileServer) resolveCachedFile(key string, resolve func() (http.File, error)) (*CachedStaticFile, error) {
 	sc.RLock()
 	if sc.Cache != nil {
-		if file, ok := sc.Cache[filepath]; ok {
+		if file, ok := sc.Cache[key]; ok {
 			sc.RUnlock()
 			return file, nil
 		}
@@ -202,19 +261,20 @@ func (sc *StaticFileServer) ResolveCachedFile(filepath string) (*CachedStaticFil
 		sc.Cache = make(map[string]*CachedStaticFile)
 	}
 	// double check ftw
-	if file, ok := sc.Cache[filepath]; ok {
+	if file, ok := sc.Cache[key]; ok {
 		return file, nil
 	}
 
-	diskFile, err := sc.ResolveFile(filepath)
+	diskFile, err := resolve()
 	if err != nil {
 		return nil, err
 	}
 
 	if diskFile == nil {
-		sc.Cache[filepath] = nil
+		sc.Cache[key] = nil
 		return nil, nil
 	}
+	defer diskFile.Close()
 
 	finfo, err := diskFile.Stat()
 	if err != nil {
@@ -227,12 +287,36 @@ func (sc *StaticFileServer) ResolveCachedFile(filepath string) (*CachedStaticFil
 	}
 
 	file := &CachedStaticFile{
-		Path:     filepath,
+		Path:     strings.TrimSuffix(key, precompressedCacheKeySuffix),
 		Contents: bytes.NewReader(contents),
 		ModTime:  finfo.ModTime(),
 		Size:     len(contents),
 	}
 
-	sc.Cache[filepath] = file
+	sc.Cache[key] = file
 	return file, nil
 }
+
+// acceptsPrecompressed returns if a precompressed variant of a file should be served for a request.
+func (sc *StaticFileServer) acceptsPrecompressed(r *Ctx, filePath string) bool {
+	if sc.PrecompressedDisabled || strings.HasSuffix(filePath, precompressedExtension) {
+		return false
+	}
+	// the response is already being encoded, e.g. by the gzip middleware.
+	if r.Response.Header().Get(HeaderContentEncoding) != "" {
+		return false
+	}
+	return webutil.HeaderAny(r.Request.Header, HeaderAcceptEncoding, ContentEncodingGZIP)
+}
+
+// servePrecompressed serves the gzip precompressed variant of a file with the content type of the file.
+func servePrecompressed(r *Ctx, filePath string, modTime time.Time, contents io.ReadSeeker) {
+	contentType := mime.TypeByExtension(path.Ext(filePath))
+	if contentType == "" {
+		contentType = ContentTypeApplicationOctetStream
+	}
+	r.Response.Header().Set(HeaderContentType, contentType)
+	r.Response.Header().Set(HeaderContentEncoding, ContentEncodingGZIP)
+	r.Response.Header().Add(HeaderVary, HeaderAcceptEncoding)
+	http.ServeContent(r.Response, r.Request, filePath, modTime, contents)
+}
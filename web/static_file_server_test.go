@@ -2,8 +2,12 @@ package web
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
@@ -149,3 +153,68 @@ func TestStaticFileserverLiveNotFound(t *testing.T) {
 	assert.Equal(http.StatusNotFound, res.StatusCode())
 	assert.NotEmpty(buffer.Bytes())
 }
+
+func TestStaticFileserverPrecompressed(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "web-static")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log('plain');"), 0600))
+	compressed := new(bytes.Buffer)
+	gz := gzip.NewWriter(compressed)
+	_, err = gz.Write([]byte("console.log('compressed');"))
+	assert.Nil(err)
+	assert.Nil(gz.Close())
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "app.js.gz"), compressed.Bytes(), 0600))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "other.js"), []byte("console.log('other');"), 0600))
+
+	for _, cacheDisabled := range []bool{true, false} {
+		cfs := NewStaticFileServer(
+			OptStaticFileServerSearchPaths(http.Dir(dir)),
+			OptStaticFileServerCacheDisabled(cacheDisabled),
+		)
+		serve := func(path string, acceptGzip bool) (*webutil.MockResponseWriter, *bytes.Buffer) {
+			buffer := new(bytes.Buffer)
+			res := webutil.NewMockResponse(buffer)
+			req := webutil.NewMockRequest("GET", path)
+			if acceptGzip {
+				req.Header.Set(HeaderAcceptEncoding, "deflate, gzip")
+			}
+			assert.Nil(cfs.Action(NewCtx(res, req, OptCtxRouteParams(RouteParameters{
+				RouteTokenFilepath: path,
+			}))))
+			return res, buffer
+		}
+
+		res, buffer := serve("/app.js", true)
+		assert.Equal(http.StatusOK, res.StatusCode())
+		assert.Equal(ContentEncodingGZIP, res.Header().Get(HeaderContentEncoding))
+		assert.Contains(res.Header().Get(HeaderContentType), "javascript")
+		assert.Equal(compressed.Bytes(), buffer.Bytes(), "the precompressed file should be served")
+
+		res, buffer = serve("/app.js", false)
+		assert.Empty(res.Header().Get(HeaderContentEncoding))
+		assert.Equal("console.log('plain');", buffer.String())
+
+		res, buffer = serve("/other.js", true)
+		assert.Empty(res.Header().Get(HeaderContentEncoding), "files without a precompressed variant should be served as is")
+		assert.Equal("console.log('other');", buffer.String())
+
+		res, buffer = serve("/app.js.gz", true)
+		assert.Empty(res.Header().Get(HeaderContentEncoding), "direct requests for .gz files should be served as is")
+		assert.Equal(compressed.Bytes(), buffer.Bytes())
+	}
+
+	cfs := NewStaticFileServer(
+		OptStaticFileServerSearchPaths(http.Dir(dir)),
+		OptStaticFileServerPrecompressedDisabled(true),
+	)
+	buffer := new(bytes.Buffer)
+	res := webutil.NewMockResponse(buffer)
+	req := webutil.NewMockRequest("GET", "/app.js")
+	req.Header.Set(HeaderAcceptEncoding, "gzip")
+	assert.Nil(cfs.Action(NewCtx(res, req, OptCtxRouteParams(RouteParameters{RouteTokenFilepath: "/app.js"}))))
+	assert.Equal("console.log('plain');", buffer.String())
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// StreamJSON reads the request body as a stream of json values, calling the handler once per value.
+// The body can be newline delimited json or a top level json array; it isn't cached.
+func (rc *Ctx) StreamJSON(handler func(decode func(interface{}) error) error) error {
+	if rc.Request == nil || rc.Request.Body == nil {
+		return nil
+	}
+	defer rc.Request.Body.Close()
+
+	var body io.Reader = rc.Request.Body
+	if rc.App != nil {
+		if maxBytes := rc.App.Config.MaxBodyBytesOrDefault(); maxBytes > 0 {
+			body = &maxBytesReader{Reader: io.LimitReader(body, maxBytes+1), MaxBytes: maxBytes}
+		}
+	}
+
+	buffered := bufio.NewReader(body)
+	isArray, err := isJSONArray(buffered)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(buffered)
+	if isArray {
+		// consume the opening `[`.
+		if _, err = decoder.Token(); err != nil {
+			return streamJSONDecodeError(err, 0)
+		}
+	}
+
+	for index := 0; ; index++ {
+		if err = rc.Context().Err(); err != nil {
+			return ex.New(err)
+		}
+		if !decoder.More() {
+			// this is either the closing `]` of an array, the end of the stream, or a stray closing delimiter.
+			_, err = decoder.Token()
+			if (isArray && err == nil) || (!isArray && err == io.EOF) {
+				return nil
+			}
+			return streamJSONDecodeError(err, index)
+		}
+
+		var decoded bool
+		var decodeErr error
+		decode := func(item interface{}) error {
+			if decoded {
+				return decodeErr
+			}
+			decoded = true
+			if decodeErr = decoder.Decode(item); decodeErr != nil {
+				decodeErr = streamJSONDecodeError(decodeErr, index)
+			}
+			return decodeErr
+		}
+		if err = handler(decode); err != nil {
+			return err
+		}
+		if !decoded {
+			var skipped json.RawMessage
+			if err = decode(&skipped); err != nil {
+				return err
+			}
+		}
+		if decodeErr != nil {
+			return decodeErr
+		}
+	}
+}
+
+// maxBytesReader returns `ErrBodyTooLarge` once more than `MaxBytes` are read.
+type maxBytesReader struct {
+	io.Reader
+	MaxBytes int64
+	read     int64
+}
+
+func (mbr *maxBytesReader) Read(p []byte) (n int, err error) {
+	n, err = mbr.Reader.Read(p)
+	mbr.read += int64(n)
+	if mbr.read > mbr.MaxBytes {
+		return n, ex.New(ErrBodyTooLarge, ex.OptMessagef("max bytes: %d", mbr.MaxBytes))
+	}
+	return
+}
+
+// isJSONArray returns if the first non whitespace byte of a reader opens an array.
+func isJSONArray(r *bufio.Reader) (bool, error) {
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !isJSONWhitespace(b[0]) {
+			return b[0] == '[', nil
+		}
+		if _, err = r.ReadByte(); err != nil {
+			return false, err
+		}
+	}
+}
+
+func isJSONWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func streamJSONDecodeError(err error, index int) error {
+	if ex.Is(err, ErrBodyTooLarge) {
+		return err
+	}
+	return ex.New(ErrStreamJSONDecode, ex.OptMessagef("item: %d", index), ex.OptInner(err))
+}
@@ -0,0 +1,117 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+type streamJSONTestItem struct {
+	Name string `json:"name"`
+}
+
+func streamJSONTestNames(rc *Ctx) ([]string, error) {
+	var names []string
+	err := rc.StreamJSON(func(decode func(interface{}) error) error {
+		var item streamJSONTestItem
+		if err := decode(&item); err != nil {
+			return err
+		}
+		names = append(names, item.Name)
+		return nil
+	})
+	return names, err
+}
+
+func TestCtxStreamJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	ndjson := "{\"name\":\"foo\"}\n{\"name\":\"bar\"}\n\n{\"name\":\"baz\"}\n"
+	names, err := streamJSONTestNames(MockCtx("POST", "/", OptCtxBodyBytes([]byte(ndjson))))
+	assert.Nil(err)
+	assert.Equal([]string{"foo", "bar", "baz"}, names)
+
+	array := ` [{"name":"foo"}, {"name":"bar"},
+		{"name":"baz"}]`
+	names, err = streamJSONTestNames(MockCtx("POST", "/", OptCtxBodyBytes([]byte(array))))
+	assert.Nil(err)
+	assert.Equal([]string{"foo", "bar", "baz"}, names)
+
+	names, err = streamJSONTestNames(MockCtx("POST", "/", OptCtxBodyBytes([]byte("[]"))))
+	assert.Nil(err)
+	assert.Empty(names)
+
+	names, err = streamJSONTestNames(MockCtx("POST", "/"))
+	assert.Nil(err)
+	assert.Empty(names)
+}
+
+func TestCtxStreamJSONDecodeError(t *testing.T) {
+	assert := assert.New(t)
+
+	ndjson := "{\"name\":\"foo\"}\n{\"name\":\"bar\"\n{\"name\":\"baz\"}\n"
+	names, err := streamJSONTestNames(MockCtx("POST", "/", OptCtxBodyBytes([]byte(ndjson))))
+	assert.True(ex.Is(err, ErrStreamJSONDecode))
+	assert.Equal("item: 1", ex.ErrMessage(err))
+	assert.Equal([]string{"foo"}, names)
+
+	array := `[{"name":"foo"}, {"name":"bar"}, {"name":1}]`
+	names, err = streamJSONTestNames(MockCtx("POST", "/", OptCtxBodyBytes([]byte(array))))
+	assert.True(ex.Is(err, ErrStreamJSONDecode))
+	assert.Equal("item: 2", ex.ErrMessage(err))
+	assert.Len(names, 2)
+
+	_, err = streamJSONTestNames(MockCtx("POST", "/", OptCtxBodyBytes([]byte(`[{"name":"foo"}`))))
+	assert.True(ex.Is(err, ErrStreamJSONDecode), "unterminated arrays should error")
+}
+
+func TestCtxStreamJSONSkipsItems(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := MockCtx("POST", "/", OptCtxBodyBytes([]byte(`{"name":"foo"} {"name":"bar"}`)))
+	var calls int
+	err := rc.StreamJSON(func(_ func(interface{}) error) error {
+		calls++
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(2, calls)
+}
+
+func TestCtxStreamJSONMaxBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	var body strings.Builder
+	for x := 0; x < 100; x++ {
+		fmt.Fprintf(&body, "{\"name\":\"item-%d\"}\n", x)
+	}
+	app := MustNew(OptMaxBodyBytes(64))
+	names, err := streamJSONTestNames(MockCtx("POST", "/", OptCtxApp(app), OptCtxBodyBytes([]byte(body.String()))))
+	assert.True(ex.Is(err, ErrBodyTooLarge))
+	assert.True(len(names) < 100)
+}
+
+func TestCtxStreamJSONCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := MockCtx("POST", "/", OptCtxBodyBytes([]byte(`{"name":"foo"} {"name":"bar"}`)))
+	rc.WithContext(ctx)
+
+	var names []string
+	err := rc.StreamJSON(func(decode func(interface{}) error) error {
+		var item streamJSONTestItem
+		if err := decode(&item); err != nil {
+			return err
+		}
+		names = append(names, item.Name)
+		cancel()
+		return nil
+	})
+	assert.NotNil(err)
+	assert.Equal([]string{"foo"}, names)
+}
@@ -2,18 +2,51 @@ package web
 
 import (
 	"context"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
 )
 
+// TimeoutOption mutates a timeout config.
+type TimeoutOption func(*TimeoutConfig)
+
+// OptTimeoutResult sets the function that returns the result for a request that timed out.
+// The error's class is either `ErrRequestTimeout` or `ErrHandlerTimeout`.
+func OptTimeoutResult(result func(*Ctx, error) Result) TimeoutOption {
+	return func(cfg *TimeoutConfig) { cfg.Result = result }
+}
+
+// TimeoutConfig is the configuration for the timeout middleware.
+type TimeoutConfig struct {
+	Timeout time.Duration
+	Result  func(*Ctx, error) Result
+}
+
 // WithTimeout injects the context for a given action with a timeout context.
-func WithTimeout(d time.Duration) Middleware {
+// Timeouts while reading the request body are answered with a 408, otherwise with a 504.
+func WithTimeout(d time.Duration, options ...TimeoutOption) Middleware {
+	cfg := TimeoutConfig{Timeout: d}
+	for _, option := range options {
+		option(&cfg)
+	}
 	return func(action Action) Action {
 		return func(r *Ctx) Result {
 			ctx, cancel := context.WithTimeout(r.Context(), d)
 			defer func() { cancel() }()
 
 			r.Request = r.Request.WithContext(ctx)
+			var body *timeoutBodyReader
+			if r.Request.Body != nil && r.Request.Body != http.NoBody {
+				body = &timeoutBodyReader{ReadCloser: r.Request.Body}
+				r.Request.Body = body
+			}
 
 			panicChan := make(chan interface{}, 1)
 			resultChan := make(chan Result, 1)
@@ -33,8 +66,108 @@ func WithTimeout(d time.Duration) Middleware {
 			case res := <-resultChan:
 				return res
 			case <-ctx.Done():
-				return r.DefaultProvider.Status(http.StatusServiceUnavailable)
+				return cfg.timedOut(r, body != nil && body.IsReading())
 			}
 		}
 	}
 }
+
+// timedOut logs a warning for a request that timed out and returns its result.
+func (cfg TimeoutConfig) timedOut(r *Ctx, readingBody bool) Result {
+	class := ErrHandlerTimeout
+	if readingBody {
+		class = ErrRequestTimeout
+	}
+	err := ex.New(class, ex.OptMessagef("%s %s timed out after %v", r.Request.Method, r.Request.URL.Path, cfg.Timeout))
+	if r.App != nil && r.App.Log != nil {
+		fields := map[string]interface{}{
+			"elapsed": r.Elapsed().String(),
+			"timeout": cfg.Timeout.String(),
+		}
+		if r.Route != nil {
+			fields["route"] = r.Route.String()
+		}
+		r.App.Log.Trigger(r.Context(), logger.NewErrorEvent(logger.Warning, err, logger.OptErrorEventState(r.Request), logger.OptErrorEventFields(fields)))
+	}
+	if cfg.Result != nil {
+		return cfg.Result(r, err)
+	}
+	if readingBody {
+		return &ProblemResult{
+			Status: http.StatusRequestTimeout,
+			Detail: "the request body was not received within " + cfg.Timeout.String(),
+			Code:   "request_timeout",
+		}
+	}
+	return &ProblemResult{
+		Status: http.StatusGatewayTimeout,
+		Detail: "the request was not handled within " + cfg.Timeout.String(),
+		Code:   "handler_timeout",
+	}
+}
+
+// timeoutBodyReader tracks if a request body is being read, so timeouts can be attributed to the client.
+type timeoutBodyReader struct {
+	io.ReadCloser
+	reading int32
+}
+
+func (tbr *timeoutBodyReader) Read(p []byte) (int, error) {
+	atomic.StoreInt32(&tbr.reading, 1)
+	defer atomic.StoreInt32(&tbr.reading, 0)
+	return tbr.ReadCloser.Read(p)
+}
+
+// IsReading returns if a read is in progress.
+func (tbr *timeoutBodyReader) IsReading() bool {
+	return atomic.LoadInt32(&tbr.reading) == 1
+}
+
+// WithHeaderTimeout returns a middleware that applies a deadline to the request context
+// from a timeout sent by the caller in a header, e.g. `X-Request-Timeout: 1500ms`, clamped to `max`.
+func WithHeaderTimeout(header string, max time.Duration) Middleware {
+	if header == "" {
+		header = HeaderXRequestTimeout
+	}
+	return func(action Action) Action {
+		return func(r *Ctx) Result {
+			value := r.Request.Header.Get(header)
+			if value == "" {
+				return action(r)
+			}
+			timeout, err := ParseTimeout(value)
+			if err != nil {
+				if r.App != nil {
+					logger.MaybeWarningf(r.App.Log, "ignoring invalid %s header: %v", header, err)
+				}
+				return action(r)
+			}
+			if max > 0 && timeout > max {
+				timeout = max
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r.WithContext(ctx)
+			return action(r)
+		}
+	}
+}
+
+// ParseTimeout parses a timeout from either a duration, e.g. `1500ms`, or a number of seconds, e.g. `1.5`.
+// It returns an error if the value is malformed or is not positive.
+func ParseTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, parseErr := strconv.ParseFloat(value, 64)
+		// non-finite and overflowing values would otherwise convert to an arbitrary duration.
+		if parseErr != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds > float64(math.MaxInt64)/float64(time.Second) {
+			return 0, ex.New(ErrInvalidTimeout, ex.OptMessagef("value: %q", value))
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if timeout <= 0 {
+		return 0, ex.New(ErrInvalidTimeout, ex.OptMessagef("value: %q", value))
+	}
+	return timeout, nil
+}
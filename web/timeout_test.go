@@ -1,11 +1,17 @@
 package web
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/r2"
 )
 
 func TestTimeout(t *testing.T) {
@@ -46,3 +52,133 @@ func TestTimeout(t *testing.T) {
 	assert.Nil(err)
 	assert.True(didFinish)
 }
+
+func TestParseTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	timeout, err := ParseTimeout("1500ms")
+	assert.Nil(err)
+	assert.Equal(1500*time.Millisecond, timeout)
+
+	timeout, err = ParseTimeout(" 2 ")
+	assert.Nil(err)
+	assert.Equal(2*time.Second, timeout)
+
+	timeout, err = ParseTimeout("0.25")
+	assert.Nil(err)
+	assert.Equal(250*time.Millisecond, timeout)
+
+	for _, value := range []string{"", "soon", "-1s", "0", "NaN", "Inf", "+Infinity", "1e300"} {
+		_, err = ParseTimeout(value)
+		assert.True(ex.Is(err, ErrInvalidTimeout), value)
+	}
+}
+
+func TestWithHeaderTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	remaining := func(ctx *Ctx) Result {
+		deadline, ok := ctx.Context().Deadline()
+		if !ok {
+			return Text.Result("none")
+		}
+		return Text.Result(time.Until(deadline).Round(time.Second).String())
+	}
+
+	warnings := new(bytes.Buffer)
+	app := MustNew(OptLog(logger.MustNew(logger.OptOutput(warnings), logger.OptAll())))
+	app.GET("/", remaining, WithHeaderTimeout("", 10*time.Second))
+	app.GET("/custom", remaining, WithHeaderTimeout("X-Patience", 0))
+	app.GET("/nested", remaining, WithHeaderTimeout("", 0), WithTimeout(2*time.Second))
+
+	contents, _, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal("none", string(contents))
+
+	contents, _, err = MockGet(app, "/", r2.OptHeaderValue(HeaderXRequestTimeout, "5s")).Bytes()
+	assert.Nil(err)
+	assert.Equal("5s", string(contents))
+
+	contents, _, err = MockGet(app, "/", r2.OptHeaderValue(HeaderXRequestTimeout, "60")).Bytes()
+	assert.Nil(err)
+	assert.Equal("10s", string(contents), "the timeout should be clamped to the max")
+
+	contents, _, err = MockGet(app, "/custom", r2.OptHeaderValue("X-Patience", "60")).Bytes()
+	assert.Nil(err)
+	assert.Equal("1m0s", string(contents))
+
+	contents, _, err = MockGet(app, "/nested", r2.OptHeaderValue(HeaderXRequestTimeout, "60")).Bytes()
+	assert.Nil(err)
+	assert.Equal("2s", string(contents), "an existing deadline should not be extended")
+
+	contents, meta, err := MockGet(app, "/", r2.OptHeaderValue(HeaderXRequestTimeout, "whenever")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("none", string(contents))
+	assert.Nil(app.Log.(*logger.Logger).Drain())
+	assert.Contains(warnings.String(), "ignoring invalid X-Request-Timeout header")
+}
+
+func TestTimeoutProblemResult(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.MustNew(logger.OptOutput(buffer), logger.OptText(logger.OptTextNoColor(), logger.OptTextHideTimestamp()), logger.OptEnabled(logger.Warning))
+	defer log.Close()
+
+	app := MustNew(OptLog(log))
+	release := make(chan struct{})
+	defer close(release)
+	app.GET("/users/:id", func(_ *Ctx) Result {
+		<-release
+		return NoContent
+	}, WithTimeout(5*time.Millisecond))
+
+	contents, meta, err := MockGet(app, "/users/1234").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusGatewayTimeout, meta.StatusCode)
+	assert.Equal(ContentTypeApplicationProblemJSON, meta.Header.Get(HeaderContentType))
+	var problem ProblemResult
+	assert.Nil(json.Unmarshal(contents, &problem))
+	assert.Equal(http.StatusGatewayTimeout, problem.Status)
+	assert.Equal("handler_timeout", problem.Code)
+	assert.Equal("the request was not handled within 5ms", problem.Detail)
+
+	assert.Contains(buffer.String(), "[warning]")
+	assert.Contains(buffer.String(), "handler timeout GET /users/1234 timed out after 5ms")
+	assert.Contains(buffer.String(), "route:/users/:id")
+	assert.Contains(buffer.String(), "elapsed:")
+}
+
+func TestTimeoutRequestBody(t *testing.T) {
+	assert := assert.New(t)
+
+	readBody := func(r *Ctx) Result {
+		if _, err := r.PostBody(); err != nil {
+			return Text.BadRequest(err)
+		}
+		return NoContent
+	}
+
+	// the client never finishes sending the body.
+	body, writer := io.Pipe()
+	defer writer.Close()
+	rc := MockCtx("POST", "/")
+	rc.Request.Body = body
+
+	result := WithTimeout(5 * time.Millisecond)(readBody)(rc)
+	problem, ok := result.(*ProblemResult)
+	assert.True(ok)
+	assert.Equal(http.StatusRequestTimeout, problem.Status)
+	assert.Equal("request_timeout", problem.Code)
+
+	var timeoutErr error
+	rc = MockCtx("POST", "/")
+	rc.Request.Body = body
+	result = WithTimeout(5*time.Millisecond, OptTimeoutResult(func(_ *Ctx, err error) Result {
+		timeoutErr = err
+		return NoContent
+	}))(readBody)(rc)
+	assert.Equal(NoContent, result)
+	assert.True(ex.Is(timeoutErr, ErrRequestTimeout))
+}
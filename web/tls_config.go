@@ -0,0 +1,72 @@
+package web
+
+import (
+	"crypto/tls"
+
+	"github.com/blend/go-sdk/certutil"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// SecureTLSConfig returns a tls config with hardened defaults for servers, e.g. TLS 1.2 or later.
+func SecureTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		},
+		CurvePreferences: []tls.CurveID{
+			tls.X25519,
+			tls.CurveP256,
+		},
+		NextProtos:               []string{"h2", "http/1.1"},
+		PreferServerCipherSuites: true,
+	}
+}
+
+// OptTLS loads a certificate and key from the given files and sets the app to serve https.
+// If the app does not have a tls config yet, it is set to `SecureTLSConfig()`.
+// It can be given multiple times to serve multiple certificates, which are selected by SNI.
+func OptTLS(certFile, keyFile string) Option {
+	return func(a *App) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return ex.New(err)
+		}
+		if a.TLSConfig == nil {
+			a.TLSConfig = SecureTLSConfig()
+		}
+		a.TLSConfig.Certificates = append(a.TLSConfig.Certificates, cert)
+		// the certificates are only selected by SNI with the name map.
+		a.TLSConfig.BuildNameToCertificate()
+		return nil
+	}
+}
+
+// OptTLSCertFileWatcher sets the app to serve https with a certificate and key that are
+// reloaded from the given files when they change, e.g. when they're rotated, without restarting.
+func OptTLSCertFileWatcher(certFile, keyFile string, options ...certutil.CertFileWatcherOption) Option {
+	return func(a *App) error {
+		watcher, err := certutil.NewCertFileWatcher(certFile, keyFile, append([]certutil.CertFileWatcherOption{
+			certutil.OptCertFileWatcherOnReload(func(_ *certutil.CertFileWatcher, err error) {
+				if err != nil {
+					logger.MaybeError(a.Log, err)
+				}
+			}),
+		}, options...)...)
+		if err != nil {
+			return err
+		}
+		if a.TLSConfig == nil {
+			a.TLSConfig = SecureTLSConfig()
+		}
+		a.TLSConfig.GetCertificate = watcher.GetCertificate
+		a.TLSCertFileWatcher = watcher
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/certutil"
+)
+
+func TestSecureTLSConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := SecureTLSConfig()
+	assert.Equal(tls.VersionTLS12, cfg.MinVersion)
+	assert.Equal([]string{"h2", "http/1.1"}, cfg.NextProtos)
+	assert.NotEmpty(cfg.CipherSuites)
+	assert.True(cfg.PreferServerCipherSuites)
+	assert.Empty(cfg.Certificates)
+}
+
+func TestOptTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "web-tls")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.Nil(ioutil.WriteFile(certFile, []byte(TestTLSCert), 0600))
+	assert.Nil(ioutil.WriteFile(keyFile, []byte(TestTLSKey), 0600))
+
+	app, err := New(OptTLS(certFile, keyFile), OptTLS(certFile, keyFile))
+	assert.Nil(err)
+	assert.NotNil(app.TLSConfig)
+	assert.Equal(tls.VersionTLS12, app.TLSConfig.MinVersion)
+	assert.Len(app.TLSConfig.Certificates, 2)
+
+	existing := &tls.Config{MinVersion: tls.VersionTLS13}
+	app, err = New(OptTLSConfig(existing), OptTLS(certFile, keyFile))
+	assert.Nil(err)
+	assert.Equal(tls.VersionTLS13, app.TLSConfig.MinVersion, "an existing tls config should be kept")
+	assert.Len(app.TLSConfig.Certificates, 1)
+
+	_, err = New(OptTLS(filepath.Join(dir, "missing.pem"), keyFile))
+	assert.NotNil(err)
+}
+
+func TestOptTLSSNI(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "web-tls")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	var options []Option
+	for _, name := range []string{"a.example.com", "b.example.com"} {
+		bundle, err := certutil.CreateSelfServerCert(name)
+		assert.Nil(err)
+		certPEM, err := bundle.CertPEM()
+		assert.Nil(err)
+		keyPEM, err := bundle.KeyPEM()
+		assert.Nil(err)
+		certFile := filepath.Join(dir, name+".cert.pem")
+		keyFile := filepath.Join(dir, name+".key.pem")
+		assert.Nil(ioutil.WriteFile(certFile, certPEM, 0600))
+		assert.Nil(ioutil.WriteFile(keyFile, keyPEM, 0600))
+		options = append(options, OptTLS(certFile, keyFile))
+	}
+	app, err := New(options...)
+	assert.Nil(err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", app.TLSConfig)
+	assert.Nil(err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	for _, name := range []string{"a.example.com", "b.example.com"} {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{ServerName: name, InsecureSkipVerify: true})
+		assert.Nil(err)
+		assert.Equal(name, conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+		conn.Close()
+	}
+}
+
+func TestOptTLSCertFileWatcher(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "web-tls")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.Nil(ioutil.WriteFile(certFile, []byte(TestTLSCert), 0600))
+	assert.Nil(ioutil.WriteFile(keyFile, []byte(TestTLSKey), 0600))
+
+	app, err := New(OptTLSCertFileWatcher(certFile, keyFile))
+	assert.Nil(err)
+	assert.NotNil(app.TLSCertFileWatcher)
+	assert.Equal(tls.VersionTLS12, app.TLSConfig.MinVersion)
+	assert.NotNil(app.TLSConfig.GetCertificate)
+
+	cert, err := app.TLSConfig.GetCertificate(nil)
+	assert.Nil(err)
+	assert.NotNil(cert)
+
+	_, err = New(OptTLSCertFileWatcher(filepath.Join(dir, "missing.pem"), keyFile))
+	assert.NotNil(err)
+}
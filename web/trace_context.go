@@ -0,0 +1,25 @@
+package web
+
+import (
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// TraceContextAware is an action that continues the trace and request id of the inbound request on the request context,
+// so outbound requests made with `r2.OptTraceContext(r.Context())` are part of the same trace.
+func TraceContextAware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		requestContext := ctx.Context()
+		if _, ok := webutil.GetTraceContext(requestContext); !ok {
+			if tc, err := webutil.ParseTraceContext(ctx.Request.Header.Get(webutil.HeaderTraceParent), ctx.Request.Header.Get(webutil.HeaderTraceState)); err == nil {
+				requestContext = webutil.WithTraceContext(requestContext, tc)
+			}
+		}
+		requestID := ctx.Request.Header.Get(webutil.HeaderXRequestID)
+		if !webutil.IsValidRequestID(requestID) {
+			requestID = uuid.V4().String()
+		}
+		ctx.WithContext(WithRequestID(requestContext, requestID))
+		return action(ctx)
+	}
+}
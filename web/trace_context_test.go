@@ -0,0 +1,83 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestTraceContextAware(t *testing.T) {
+	assert := assert.New(t)
+
+	var outbound http.Header
+	downstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		outbound = req.Header
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer downstream.Close()
+
+	var requestID string
+	app := MustNew(OptUse(TraceContextAware))
+	app.GET("/", func(r *Ctx) Result {
+		requestID = GetRequestID(r.Context())
+		if _, err := r2.New(downstream.URL, r2.OptTraceContext(r.Context())).Discard(); err != nil {
+			return Text.InternalError(err)
+		}
+		return NoContent
+	})
+
+	meta, err := MockGet(app, "/",
+		r2.OptHeaderValue(webutil.HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+		r2.OptHeaderValue(webutil.HeaderTraceState, "congo=t61rcWkgMzE"),
+		r2.OptHeaderValue(webutil.HeaderXRequestID, "request-1234"),
+	).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Equal("request-1234", requestID)
+	assert.Equal("request-1234", outbound.Get(webutil.HeaderXRequestID))
+	assert.Equal("congo=t61rcWkgMzE", outbound.Get(webutil.HeaderTraceState))
+
+	tc, err := webutil.ParseTraceContext(outbound.Get(webutil.HeaderTraceParent), "")
+	assert.Nil(err)
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID, "the outbound request should be in the inbound trace")
+	assert.Equal("00f067aa0ba902b7", tc.SpanID, "without a tracer the outbound request should be a child of the caller's span")
+	assert.True(tc.Sampled())
+
+	// requests without a trace context don't start one, but do get a request id.
+	meta, err = MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderTraceParent, "not-a-trace")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Empty(outbound.Get(webutil.HeaderTraceParent))
+	assert.NotEmpty(requestID)
+	assert.NotEqual("request-1234", requestID)
+	assert.Equal(requestID, outbound.Get(webutil.HeaderXRequestID))
+
+	// invalid request ids are replaced.
+	meta, err = MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderXRequestID, "request 1234; fake=log")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.True(webutil.IsValidRequestID(requestID))
+	assert.NotEqual("request 1234; fake=log", requestID)
+
+	// the trace context set by a tracer from the request span is kept.
+	traced := webutil.TraceContext{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331", Flags: webutil.TraceFlagSampled}
+	app.Tracer = traceContextTestTracer{traced}
+	meta, err = MockGet(app, "/", r2.OptHeaderValue(webutil.HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Equal(traced.TraceParent(), outbound.Get(webutil.HeaderTraceParent))
+}
+
+// traceContextTestTracer sets a trace context on requests, like a tracer that records spans.
+type traceContextTestTracer struct {
+	tc webutil.TraceContext
+}
+
+func (tct traceContextTestTracer) Start(ctx *Ctx) TraceFinisher {
+	ctx.WithContext(webutil.WithTraceContext(ctx.Context(), tct.tc))
+	return nil
+}
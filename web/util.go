@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 
 	"github.com/blend/go-sdk/stringutil"
@@ -62,3 +63,16 @@ func CopyHeaders(headers http.Header) http.Header {
 	}
 	return output
 }
+
+// isNilResult returns if a result is nil, including a typed nil, e.g. a nil `*APIError` returned as a `Result`.
+func isNilResult(result Result) bool {
+	if result == nil {
+		return true
+	}
+	value := reflect.ValueOf(result)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
@@ -206,3 +206,18 @@ func TestNewCookie(t *testing.T) {
 	assert.Equal("hello", c.Name)
 	assert.Equal("world", c.Value)
 }
+
+func TestMiddlewareName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(MiddlewareName(nil))
+	assert.Equal("github.com/blend/go-sdk/web.JSONProviderAsDefault", MiddlewareName(JSONProviderAsDefault))
+
+	named := Named("json", JSONProviderAsDefault)
+	assert.Equal("json", MiddlewareName(named))
+	assert.Equal("xml", MiddlewareName(Named("xml", XMLProviderAsDefault)), "closures from the same literal should keep their own names")
+	assert.Equal("json", MiddlewareName(named))
+	r := applyMiddleware(named)
+	_, ok := r.DefaultProvider.(JSONResultProvider)
+	assert.True(ok)
+}
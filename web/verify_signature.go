@@ -0,0 +1,116 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// SignatureVerifier returns if a signature header value is valid for a given body and secret.
+type SignatureVerifier func(body []byte, header string, secret []byte) bool
+
+// VerifySignature returns a middleware that verifies a signed request, e.g. an inbound webhook.
+// If `tolerance` is positive, the signature must include a `t=<unix seconds>` element within it.
+func VerifySignature(header string, secret []byte, tolerance time.Duration, verify SignatureVerifier) Middleware {
+	return func(action Action) Action {
+		return func(r *Ctx) Result {
+			signature := r.Request.Header.Get(header)
+			if signature == "" {
+				return r.DefaultProvider.BadRequest(ex.New(ErrInvalidSignature, ex.OptMessagef("%s header is missing", header)))
+			}
+			if tolerance > 0 {
+				timestamp, ok := SignatureTimestamp(signature)
+				if !ok {
+					return r.DefaultProvider.BadRequest(ex.New(ErrInvalidSignature, ex.OptMessage("timestamp is missing")))
+				}
+				if skew := time.Since(timestamp); skew > tolerance || skew < -tolerance {
+					return r.DefaultProvider.BadRequest(ex.New(ErrInvalidSignature, ex.OptMessage("timestamp is outside the tolerance")))
+				}
+			}
+			body, err := r.PostBody()
+			if err != nil {
+				return r.DefaultProvider.BadRequest(err)
+			}
+			if !verify(body, signature, secret) {
+				return r.DefaultProvider.BadRequest(ex.New(ErrInvalidSignature))
+			}
+			return action(r)
+		}
+	}
+}
+
+// VerifyHMACSHA256 verifies a timestamped signature header of the form `t=<unix seconds>,v1=<hex signature>`,
+// where the signature is the hex encoded HMAC-SHA256 of `<unix seconds>.<body>`.
+// Multiple `v1` elements are allowed, which is useful when rotating secrets, but only one `t` element.
+func VerifyHMACSHA256(body []byte, header string, secret []byte) bool {
+	parsed, ok := parseSignatureHeader(header)
+	if !ok {
+		return false
+	}
+	expected := SignHMACSHA256(body, parsed.timestamp, secret)
+	for _, signature := range parsed.signatures {
+		if hmac.Equal(expected, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignHMACSHA256 returns the HMAC-SHA256 of `<timestamp>.<body>` as verified by `VerifyHMACSHA256`.
+func SignHMACSHA256(body []byte, timestamp string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// SignatureTimestamp returns the `t=<unix seconds>` element of a signature header.
+// It returns false if the header has no `t` element, or more than one.
+func SignatureTimestamp(header string) (time.Time, bool) {
+	parsed, ok := parseSignatureHeader(header)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(parsed.timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// signatureHeader is a parsed timestamped signature header.
+type signatureHeader struct {
+	timestamp  string
+	signatures [][]byte
+}
+
+// parseSignatureHeader parses a timestamped signature header.
+// Headers with more than one `t` element are rejected, so the timestamp checked against the
+// tolerance is always the one that was signed.
+func parseSignatureHeader(header string) (parsed signatureHeader, ok bool) {
+	for _, element := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(element), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "t":
+			if parsed.timestamp != "" {
+				return signatureHeader{}, false
+			}
+			parsed.timestamp = parts[1]
+		case "v1":
+			if signature, err := hex.DecodeString(parts[1]); err == nil {
+				parsed.signatures = append(parsed.signatures, signature)
+			}
+		}
+	}
+	ok = parsed.timestamp != ""
+	return
+}
@@ -0,0 +1,85 @@
+package web
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func signatureTestHeader(body string, timestamp time.Time, secret []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(SignHMACSHA256([]byte(body), t, secret))
+}
+
+func TestVerifySignature(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := []byte("secret")
+	app := MustNew()
+	app.POST("/hook", func(r *Ctx) Result {
+		var payload map[string]string
+		if err := r.PostBodyAsJSON(&payload); err != nil {
+			return Text.BadRequest(err)
+		}
+		return Text.Result(payload["event"])
+	}, VerifySignature("X-Signature", secret, 5*time.Minute, VerifyHMACSHA256))
+
+	body := `{"event":"charge.succeeded"}`
+	post := func(header string) (string, int) {
+		options := []r2.Option{}
+		if header != "" {
+			options = append(options, r2.OptHeaderValue("X-Signature", header))
+		}
+		contents, meta, err := MockPost(app, "/hook", ioutil.NopCloser(bytes.NewBufferString(body)), options...).Bytes()
+		assert.Nil(err)
+		return string(contents), meta.StatusCode
+	}
+
+	contents, statusCode := post(signatureTestHeader(body, time.Now(), secret))
+	assert.Equal(http.StatusOK, statusCode)
+	assert.Equal("charge.succeeded", contents, "the handler should still be able to read the body")
+
+	_, statusCode = post("")
+	assert.Equal(http.StatusBadRequest, statusCode)
+
+	_, statusCode = post(signatureTestHeader(body, time.Now(), []byte("wrong")))
+	assert.Equal(http.StatusBadRequest, statusCode)
+
+	_, statusCode = post(signatureTestHeader(body, time.Now().Add(-time.Hour), secret))
+	assert.Equal(http.StatusBadRequest, statusCode, "stale timestamps should be rejected")
+
+	_, statusCode = post("v1=" + hex.EncodeToString(SignHMACSHA256([]byte(body), "", secret)))
+	assert.Equal(http.StatusBadRequest, statusCode, "a timestamp is required with a tolerance")
+
+	// a fresh timestamp in front of a replayed, stale signature.
+	stale := signatureTestHeader(body, time.Now().Add(-time.Hour), secret)
+	_, statusCode = post("t=" + strconv.FormatInt(time.Now().Unix(), 10) + "," + stale)
+	assert.Equal(http.StatusBadRequest, statusCode, "multiple timestamps should be rejected")
+}
+
+func TestVerifyHMACSHA256(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := []byte("secret")
+	header := signatureTestHeader("payload", time.Unix(1492774577, 0), secret)
+	assert.True(VerifyHMACSHA256([]byte("payload"), header, secret))
+	assert.True(VerifyHMACSHA256([]byte("payload"), "v1=deadbeef,"+header, secret), "any v1 signature can match")
+	assert.False(VerifyHMACSHA256([]byte("tampered"), header, secret))
+	assert.False(VerifyHMACSHA256([]byte("payload"), "v1=deadbeef", secret))
+	assert.False(VerifyHMACSHA256([]byte("payload"), "t=1,"+header, secret), "only one t element is allowed")
+
+	timestamp, ok := SignatureTimestamp(header)
+	assert.True(ok)
+	assert.Equal(int64(1492774577), timestamp.Unix())
+	_, ok = SignatureTimestamp("t=soon")
+	assert.False(ok)
+	_, ok = SignatureTimestamp("t=1," + header)
+	assert.False(ok)
+}
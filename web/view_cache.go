@@ -4,6 +4,7 @@ import (
 	"html/template"
 	"net/http"
 	"sync"
+	"text/template/parse"
 
 	"github.com/blend/go-sdk/bufferutil"
 	"github.com/blend/go-sdk/ex"
@@ -56,6 +57,9 @@ func NewViewCache(options ...ViewCacheOption) *ViewCache {
 	return vc
 }
 
+// ViewLoader adds templates to a view tree, e.g. from a file system.
+type ViewLoader func(*template.Template) (*template.Template, error)
+
 // ViewCache is the cached views used in view results.
 type ViewCache struct {
 	sync.Mutex
@@ -63,6 +67,8 @@ type ViewCache struct {
 	FuncMap    template.FuncMap
 	Paths      []string
 	Literals   []string
+	Loaders    []ViewLoader
+	Layout     string
 	Templates  *template.Template
 	BufferPool *bufferutil.Pool
 
@@ -103,6 +109,16 @@ func (vc *ViewCache) Parse() (views *template.Template, err error) {
 			}
 		}
 	}
+
+	for _, loader := range vc.Loaders {
+		views, err = loader(views)
+		if err != nil {
+			err = ex.New(err)
+			return
+		}
+	}
+
+	err = validateViews(views)
 	return
 }
 
@@ -219,7 +235,19 @@ func (vc *ViewCache) View(viewName string, viewModel interface{}) Result {
 }
 
 // ViewStatus returns a view result with a given status code..
+// If the view cache has a `Layout` set, the view is rendered within it.
 func (vc *ViewCache) ViewStatus(statusCode int, viewName string, viewModel interface{}) Result {
+	return vc.ViewLayoutStatus(statusCode, vc.Layout, viewName, viewModel)
+}
+
+// ViewLayout returns a view result that renders a view within a given layout.
+func (vc *ViewCache) ViewLayout(layoutName, viewName string, viewModel interface{}) Result {
+	return vc.ViewLayoutStatus(http.StatusOK, layoutName, viewName, viewModel)
+}
+
+// ViewLayoutStatus returns a view result with a given status code that renders a view within a given layout.
+// The layout includes the view with `{{ .Content }}` and its blocks with `{{ .Block "<block>" }}`.
+func (vc *ViewCache) ViewLayoutStatus(statusCode int, layoutName, viewName string, viewModel interface{}) Result {
 	t, err := vc.Lookup(viewName)
 	if err != nil {
 		return vc.viewError(err)
@@ -228,11 +256,22 @@ func (vc *ViewCache) ViewStatus(statusCode int, viewName string, viewModel inter
 		return vc.InternalError(ex.New(ErrUnsetViewTemplate, ex.OptMessagef("viewname: %s", viewName)))
 	}
 
+	var layout *template.Template
+	if layoutName != "" {
+		if layout, err = vc.Lookup(layoutName); err != nil {
+			return vc.viewError(err)
+		}
+		if layout == nil {
+			return vc.InternalError(ex.New(ErrUnsetViewTemplate, ex.OptMessagef("layout: %s", layoutName)))
+		}
+	}
+
 	return &ViewResult{
 		ViewName:   viewName,
 		StatusCode: statusCode,
 		ViewModel:  viewModel,
 		Template:   t,
+		Layout:     layout,
 		Views:      vc,
 	}
 }
@@ -251,6 +290,11 @@ func (vc *ViewCache) AddLiterals(views ...string) {
 	vc.Literals = append(vc.Literals, views...)
 }
 
+// AddLoaders adds view loaders to the view collection.
+func (vc *ViewCache) AddLoaders(loaders ...ViewLoader) {
+	vc.Loaders = append(vc.Loaders, loaders...)
+}
+
 // ----------------------------------------------------------------------
 // helpers
 // ----------------------------------------------------------------------
@@ -267,7 +311,7 @@ func (vc *ViewCache) viewError(err error) Result {
 }
 
 func (vc *ViewCache) initialize() error {
-	if len(vc.Paths) == 0 && len(vc.Literals) == 0 {
+	if len(vc.Paths) == 0 && len(vc.Literals) == 0 && len(vc.Loaders) == 0 {
 		return nil
 	}
 	views, err := vc.Parse()
@@ -277,3 +321,51 @@ func (vc *ViewCache) initialize() error {
 	vc.Templates = views
 	return nil
 }
+
+// validateViews returns an error if any of the views include a template
+// with `{{ template "name" }}` that isn't defined, so this is caught when
+// the views are loaded rather than when they are rendered.
+func validateViews(views *template.Template) error {
+	for _, view := range views.Templates() {
+		if view.Tree == nil || view.Tree.Root == nil {
+			continue
+		}
+		if name, ok := undefinedTemplate(views, view.Tree.Root); ok {
+			return ex.New(ErrViewTemplateUndefined, ex.OptMessagef("view: %s, template: %s", view.Name(), name))
+		}
+	}
+	return nil
+}
+
+// undefinedTemplate returns the first template included by a node that isn't in the views.
+func undefinedTemplate(views *template.Template, node parse.Node) (string, bool) {
+	switch typed := node.(type) {
+	case *parse.ListNode:
+		if typed == nil {
+			return "", false
+		}
+		for _, child := range typed.Nodes {
+			if name, ok := undefinedTemplate(views, child); ok {
+				return name, true
+			}
+		}
+	case *parse.IfNode:
+		return undefinedTemplateBranch(views, &typed.BranchNode)
+	case *parse.RangeNode:
+		return undefinedTemplateBranch(views, &typed.BranchNode)
+	case *parse.WithNode:
+		return undefinedTemplateBranch(views, &typed.BranchNode)
+	case *parse.TemplateNode:
+		if views.Lookup(typed.Name) == nil {
+			return typed.Name, true
+		}
+	}
+	return "", false
+}
+
+func undefinedTemplateBranch(views *template.Template, branch *parse.BranchNode) (string, bool) {
+	if name, ok := undefinedTemplate(views, branch.List); ok {
+		return name, true
+	}
+	return undefinedTemplate(views, branch.ElseList)
+}
@@ -15,6 +15,8 @@ type ViewCacheConfig struct {
 	LiveReload bool `json:"liveReload,omitempty" yaml:"liveReload,omitempty" env:"LIVE_RELOAD"`
 	// Paths are a list of view paths to include in the templates list.
 	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Layout is the name of the layout template views are rendered within by default.
+	Layout string `json:"layout,omitempty" yaml:"layout,omitempty"`
 	// BufferPoolSize is the size of the re-usable buffer pool for rendering views.
 	BufferPoolSize int `json:"bufferPoolSize,omitempty" yaml:"bufferPoolSize,omitempty"`
 
@@ -0,0 +1,38 @@
+//go:build go1.16
+// +build go1.16
+
+package web
+
+import (
+	"html/template"
+	"io/fs"
+)
+
+// ViewLoaderFS returns a view loader that parses the templates matching the given
+// glob patterns from a file system, e.g. an `embed.FS`.
+// If no patterns are given, all `*.html` files in the root of the file system are parsed.
+func ViewLoaderFS(fsys fs.FS, patterns ...string) ViewLoader {
+	if len(patterns) == 0 {
+		patterns = []string{"*.html"}
+	}
+	return func(views *template.Template) (*template.Template, error) {
+		return views.ParseFS(fsys, patterns...)
+	}
+}
+
+// OptViewCacheFS adds the templates matching the given glob patterns from a file system to the view cache.
+func OptViewCacheFS(fsys fs.FS, patterns ...string) ViewCacheOption {
+	return OptViewCacheLoaders(ViewLoaderFS(fsys, patterns...))
+}
+
+// OptViewsFS adds the templates matching the given glob patterns from a file system to the app view cache.
+// The templates are parsed once when the app starts, unless the view cache is set to live reload.
+func OptViewsFS(fsys fs.FS, patterns ...string) Option {
+	return func(a *App) error {
+		if a.Views == nil {
+			a.Views = NewViewCache()
+		}
+		a.Views.AddLoaders(ViewLoaderFS(fsys, patterns...))
+		return nil
+	}
+}
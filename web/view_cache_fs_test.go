@@ -0,0 +1,65 @@
+//go:build go1.16
+// +build go1.16
+
+package web
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestOptViewsFS(t *testing.T) {
+	assert := assert.New(t)
+
+	fsys := fstest.MapFS{
+		"views/page.html":   {Data: []byte(`{{ define "page" }}<h1>{{ .ViewModel.Text }}</h1>{{ end }}`)},
+		"views/broken.html": {Data: []byte(`{{ define "broken" }}<h1>{{ .ViewModel.Foo }}</h1>{{ end }}`)},
+	}
+
+	log := logger.MustNew(logger.OptAll(), logger.OptOutput(nil))
+	defer log.Close()
+	errors := make(chan error, 1)
+	log.Listen(logger.Fatal, "test", logger.NewErrorEventListener(func(_ context.Context, ee *logger.ErrorEvent) {
+		errors <- ee.Err
+	}))
+
+	app := MustNew(OptLog(log), OptViewsFS(fsys, "views/*.html"))
+	assert.Nil(app.StartupTasks())
+	assert.NotNil(app.Views.Templates, "the templates should be parsed once at startup")
+
+	app.GET("/", func(r *Ctx) Result {
+		return r.Views.View("page", testViewModel{Text: "<script>"})
+	})
+	app.GET("/broken", func(r *Ctx) Result {
+		return r.Views.View("broken", testViewModel{Text: "bar"})
+	})
+
+	contents, meta, err := MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal(ContentTypeHTML, meta.Header.Get(HeaderContentType))
+	assert.Equal("<h1>&lt;script&gt;</h1>", string(contents))
+
+	contents, meta, err = MockGet(app, "/broken").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, meta.StatusCode)
+	assert.Equal("Internal Server Error\n", string(contents), "a failed render shouldn't write a partial page")
+	assert.NotNil(<-errors, "the render error should be logged")
+}
+
+func TestViewLoaderFSDefaultPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	vc := NewViewCache(OptViewCacheFS(fstest.MapFS{
+		"index.html": {Data: []byte(`{{ define "index" }}index{{ end }}`)},
+	}))
+	assert.Nil(vc.Initialize())
+	view, err := vc.Lookup("index")
+	assert.Nil(err)
+	assert.NotNil(view)
+}
@@ -15,6 +15,16 @@ func OptViewCacheLiterals(literals ...string) ViewCacheOption {
 	return func(vc *ViewCache) error { vc.Literals = append(vc.Literals, literals...); return nil }
 }
 
+// OptViewCacheLoaders adds view loaders to the view cache.
+func OptViewCacheLoaders(loaders ...ViewLoader) ViewCacheOption {
+	return func(vc *ViewCache) error { vc.Loaders = append(vc.Loaders, loaders...); return nil }
+}
+
+// OptViewCacheLayout sets the layout views are rendered within by default.
+func OptViewCacheLayout(layoutName string) ViewCacheOption {
+	return func(vc *ViewCache) error { vc.Layout = layoutName; return nil }
+}
+
 // OptViewCacheFuncMap sets the view cache func maps.
 func OptViewCacheFuncMap(funcMap template.FuncMap) ViewCacheOption {
 	return func(vc *ViewCache) error { vc.FuncMap = funcMap; return nil }
@@ -66,6 +76,7 @@ func OptViewCacheConfig(cfg *ViewCacheConfig) ViewCacheOption {
 	return func(vc *ViewCache) error {
 		vc.Paths = cfg.Paths
 		vc.LiveReload = cfg.LiveReload
+		vc.Layout = cfg.Layout
 		vc.InternalErrorTemplateName = cfg.InternalErrorTemplateNameOrDefault()
 		vc.BadRequestTemplateName = cfg.BadRequestTemplateNameOrDefault()
 		vc.NotFoundTemplateName = cfg.NotFoundTemplateNameOrDefault()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/blend/go-sdk/assert"
 	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
 )
 
 func TestViewCacheProperties(t *testing.T) {
@@ -305,3 +306,45 @@ func TestViewCacheFuncs(t *testing.T) {
 	assert.Nil(opt(vc))
 	assert.Empty(vc.FuncMap)
 }
+
+func TestViewCacheLayout(t *testing.T) {
+	assert := assert.New(t)
+
+	vc := NewViewCache(OptViewCacheLiterals(
+		`{{ define "layout" }}<title>{{ .Block "title" }}</title><main>{{ .Content }}</main>{{ end }}`,
+		`{{ define "nav" }}<nav>{{ .Status.Code }}</nav>{{ end }}`,
+		`{{ define "home.title" }}Home{{ end }}`,
+		`{{ define "home" }}<h1>{{ .ViewModel.Text }}</h1>{{ template "nav" . }}{{ end }}`,
+		`{{ define "plain" }}<p>plain</p>{{ end }}`,
+	))
+	assert.Nil(vc.Initialize())
+
+	buffer := new(bytes.Buffer)
+	rc := NewCtx(webutil.NewMockResponse(buffer), nil)
+	result := vc.ViewLayout("layout", "home", testViewModel{Text: "<b>hi</b>"})
+	assert.Nil(result.Render(rc))
+	assert.Equal("<title>Home</title><main><h1>&lt;b&gt;hi&lt;/b&gt;</h1><nav>200</nav></main>", buffer.String())
+
+	// views without blocks can still be rendered in the layout.
+	vc.Layout = "layout"
+	buffer.Reset()
+	rc = NewCtx(webutil.NewMockResponse(buffer), nil)
+	assert.Nil(vc.View("plain", nil).Render(rc))
+	assert.Equal("<title></title><main><p>plain</p></main>", buffer.String())
+
+	buffer.Reset()
+	rc = NewCtx(webutil.NewMockResponse(buffer), nil)
+	assert.Nil(vc.ViewLayout("missing", "home", nil).Render(rc))
+	assert.Equal(http.StatusInternalServerError, rc.Response.StatusCode())
+}
+
+func TestViewCacheUndefinedTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	vc := NewViewCache(OptViewCacheLiterals(
+		`{{ define "home" }}{{ if .ViewModel }}{{ template "missing" . }}{{ end }}{{ end }}`,
+	))
+	err := vc.Initialize()
+	assert.True(ex.Is(err, ErrViewTemplateUndefined), "undefined templates should be caught when views are loaded")
+	assert.Contains(ex.ErrMessage(err), "missing")
+}
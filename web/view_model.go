@@ -1,7 +1,11 @@
 package web
 
 import (
+	"bytes"
+	"html/template"
+
 	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/ex"
 )
 
 // ViewModel is a wrapping viewmodel.
@@ -10,6 +14,10 @@ type ViewModel struct {
 	Status    ViewStatus
 	Ctx       *Ctx
 	ViewModel interface{}
+	// Content is the rendered view when rendering a layout, e.g. `{{ .Content }}`.
+	Content template.HTML
+
+	view *template.Template
 }
 
 // Wrap returns a ViewModel that wraps a new object.
@@ -18,5 +26,25 @@ func (vm ViewModel) Wrap(other interface{}) ViewModel {
 		Env:       vm.Env,
 		Ctx:       vm.Ctx,
 		ViewModel: other,
+		Content:   vm.Content,
+		view:      vm.view,
+	}
+}
+
+// Block renders a named block of the view being rendered, i.e. the template named `<view>.<block>`,
+// which lets layouts include sections defined by each view, e.g. `{{ .Block "title" }}`.
+// It returns an empty string if the view doesn't define the block.
+func (vm ViewModel) Block(name string) (template.HTML, error) {
+	if vm.view == nil {
+		return "", nil
+	}
+	block := vm.view.Lookup(vm.view.Name() + "." + name)
+	if block == nil {
+		return "", nil
+	}
+	buffer := new(bytes.Buffer)
+	if err := block.Execute(buffer, vm); err != nil {
+		return "", ex.New(err)
 	}
+	return template.HTML(buffer.String()), nil
 }
@@ -2,7 +2,6 @@ package web
 
 import (
 	"bytes"
-	"fmt"
 	"html/template"
 	"net/http"
 
@@ -17,6 +16,13 @@ type ViewResult struct {
 	ViewModel  interface{}
 	Views      *ViewCache
 	Template   *template.Template
+	// Layout is an optional layout the template is rendered within; see `ViewCache.ViewLayoutStatus`.
+	Layout *template.Template
+	// Buffered, if set, sets the "Content-Length" header.
+	// Views are always rendered to a buffer first, so it doesn't change when the response is written.
+	Buffered bool
+	// Headers are optional headers set on the response before the status is written, e.g. "X-Total-Count".
+	Headers http.Header
 }
 
 // Render renders the result to the given response writer.
@@ -36,6 +42,7 @@ func (vr *ViewResult) Render(ctx *Ctx) (err error) {
 		}
 	}
 
+	writeResultHeaders(ctx, vr.Headers)
 	ctx.Response.Header().Set(HeaderContentType, ContentTypeHTML)
 
 	// use a pooled buffer if possible
@@ -47,7 +54,7 @@ func (vr *ViewResult) Render(ctx *Ctx) (err error) {
 		buffer = bytes.NewBuffer(nil)
 	}
 
-	err = vr.Template.Execute(buffer, &ViewModel{
+	viewModel := &ViewModel{
 		Env: env.Env(),
 		Ctx: ctx,
 		Status: ViewStatus{
@@ -55,16 +62,30 @@ func (vr *ViewResult) Render(ctx *Ctx) (err error) {
 			Code: vr.StatusCode,
 		},
 		ViewModel: vr.ViewModel,
-	})
+		view:      vr.Template,
+	}
+	err = vr.Template.Execute(buffer, viewModel)
+	if err == nil && vr.Layout != nil {
+		viewModel.Content = template.HTML(buffer.String())
+		buffer.Reset()
+		err = vr.Layout.Execute(buffer, viewModel)
+	}
 	if err != nil {
+		// the view is rendered to a buffer first so we don't write a partial page;
+		// the error is returned to be logged rather than shown to the client.
 		err = ex.New(err)
+		ctx.Response.Header().Set(HeaderContentType, ContentTypeText)
 		ctx.Response.WriteHeader(http.StatusInternalServerError)
-		ctx.Response.Write([]byte(fmt.Sprintf("%+v\n", err)))
+		ctx.Response.Write([]byte(http.StatusText(http.StatusInternalServerError) + "\n"))
 		return
 	}
 
-	ctx.Response.WriteHeader(vr.StatusCode)
-	_, err = ctx.Response.Write(buffer.Bytes())
+	if vr.Buffered {
+		err = WriteWithContentLength(ctx, vr.StatusCode, buffer.Bytes())
+	} else {
+		ctx.Response.WriteHeader(vr.StatusCode)
+		_, err = ctx.Response.Write(buffer.Bytes())
+	}
 	if err != nil {
 		err = ex.New(err)
 	}
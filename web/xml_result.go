@@ -1,14 +1,35 @@
 package web
 
-import "github.com/blend/go-sdk/webutil"
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
 
 // XMLResult is a json result.
 type XMLResult struct {
 	StatusCode int
 	Response   interface{}
+	// Buffered, if set, serializes the response before it's written so the "Content-Length" header can be set.
+	// It's off by default so large responses are streamed.
+	Buffered bool
+	// Headers are optional headers set on the response before the status is written, e.g. "X-Total-Count".
+	Headers http.Header
 }
 
 // Render renders the result
 func (ar *XMLResult) Render(ctx *Ctx) error {
-	return webutil.WriteXML(ctx.Response, ar.StatusCode, ar.Response)
+	writeResultHeaders(ctx, ar.Headers)
+	if !ar.Buffered {
+		return webutil.WriteXML(ctx.Response, ar.StatusCode, ar.Response)
+	}
+	buffer := new(bytes.Buffer)
+	if err := xml.NewEncoder(buffer).Encode(ar.Response); err != nil {
+		return ex.New(err)
+	}
+	ctx.Response.Header().Set(HeaderContentType, ContentTypeXML)
+	return WriteWithContentLength(ctx, ar.StatusCode, buffer.Bytes())
 }
@@ -3,6 +3,7 @@ package web
 import (
 	"bytes"
 	"net/http"
+	"strconv"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
@@ -46,3 +47,16 @@ func TestXMLResultRenderStatusCode(t *testing.T) {
 	assert.Equal(http.StatusBadRequest, w.StatusCode())
 	assert.Equal("<xmltest><foo>bar</foo></xmltest>", buf.String())
 }
+
+func TestXMLResultRenderBuffered(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	w := webutil.NewMockResponse(buf)
+	r := NewCtx(w, webutil.NewMockRequest("GET", "/"))
+
+	xr := &XMLResult{StatusCode: http.StatusOK, Response: xmltest{Foo: "bar"}, Buffered: true}
+	assert.Nil(xr.Render(r))
+	assert.Equal(http.StatusOK, w.StatusCode())
+	assert.Equal(strconv.Itoa(buf.Len()), w.Header().Get(HeaderContentLength))
+}
@@ -0,0 +1,77 @@
+package webutil
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// defaultPorts are the ports that are dropped from canonical urls for each scheme.
+var defaultPorts = map[string]string{
+	SchemeHTTP:  "80",
+	SchemeHTTPS: "443",
+	"ws":        "80",
+	"wss":       "443",
+}
+
+// CanonicalizeURLOption mutates a canonicalize url config.
+type CanonicalizeURLOption func(*CanonicalizeURLConfig)
+
+// OptCanonicalizeURLKeepTrailingSlash sets if trailing slashes on the path should be kept.
+func OptCanonicalizeURLKeepTrailingSlash(keep bool) CanonicalizeURLOption {
+	return func(cfg *CanonicalizeURLConfig) { cfg.KeepTrailingSlash = keep }
+}
+
+// CanonicalizeURLConfig determines how urls are canonicalized by `CanonicalizeURL`.
+type CanonicalizeURLConfig struct {
+	// KeepTrailingSlash keeps trailing slashes on the path, so `/foo/` and `/foo` are different urls.
+	KeepTrailingSlash bool
+}
+
+// CanonicalizeURL returns the canonical form of a url, so urls that only differ by formatting compare as equal,
+// e.g. for cache keys or allowlists.
+func CanonicalizeURL(rawURL string, options ...CanonicalizeURLOption) (string, error) {
+	var cfg CanonicalizeURLConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); port != "" && defaultPorts[u.Scheme] == port {
+		u.Host = strings.TrimSuffix(u.Host, ":"+port)
+	}
+
+	if cfg.KeepTrailingSlash {
+		if u.Path == "" {
+			u.Path = "/"
+		}
+	} else if u.Path != "" && strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+	}
+
+	u.RawQuery = u.Query().Encode()
+	u.ForceQuery = false
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// URLEqual returns if two urls have the same canonical form (see `CanonicalizeURL`).
+// Urls that can't be parsed are never equal.
+func URLEqual(a, b string, options ...CanonicalizeURLOption) bool {
+	canonicalA, err := CanonicalizeURL(a, options...)
+	if err != nil {
+		return false
+	}
+	canonicalB, err := CanonicalizeURL(b, options...)
+	if err != nil {
+		return false
+	}
+	return canonicalA == canonicalB
+}
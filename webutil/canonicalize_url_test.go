@@ -0,0 +1,51 @@
+package webutil
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCanonicalizeURL(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "HTTPS://Example.COM:443/foo/?b=2&a=1#top", Expected: "https://example.com/foo?a=1&b=2"},
+		{Input: "http://example.com:80/", Expected: "http://example.com"},
+		{Input: "http://example.com:8080/foo", Expected: "http://example.com:8080/foo"},
+		{Input: "https://example.com:80/foo", Expected: "https://example.com:80/foo"},
+		{Input: "http://[::1]:80/foo", Expected: "http://[::1]/foo"},
+		{Input: "https://example.com/foo?tag=b&tag=a&id=1", Expected: "https://example.com/foo?id=1&tag=b&tag=a"},
+		{Input: "https://example.com/foo?", Expected: "https://example.com/foo"},
+		{Input: "https://example.com/Foo%2Fbar/", Expected: "https://example.com/Foo%2Fbar"},
+	}
+	for _, tc := range testCases {
+		canonical, err := CanonicalizeURL(tc.Input)
+		assert.Nil(err, tc.Input)
+		assert.Equal(tc.Expected, canonical, tc.Input)
+	}
+
+	canonical, err := CanonicalizeURL("https://example.com/foo/", OptCanonicalizeURLKeepTrailingSlash(true))
+	assert.Nil(err)
+	assert.Equal("https://example.com/foo/", canonical)
+	canonical, err = CanonicalizeURL("https://example.com", OptCanonicalizeURLKeepTrailingSlash(true))
+	assert.Nil(err)
+	assert.Equal("https://example.com/", canonical)
+
+	_, err = CanonicalizeURL("http://[::1")
+	assert.NotNil(err)
+}
+
+func TestURLEqual(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(URLEqual("https://example.com:443/foo?b=2&a=1", "HTTPS://example.com/foo/?a=1&b=2"))
+	assert.True(URLEqual("http://example.com", "http://example.com:80/"))
+	assert.False(URLEqual("http://example.com/foo", "http://example.com/foo/", OptCanonicalizeURLKeepTrailingSlash(true)))
+	assert.False(URLEqual("http://example.com/foo", "https://example.com/foo"))
+	assert.False(URLEqual("http://example.com/foo?a=1", "http://example.com/foo?a=2"))
+	assert.False(URLEqual("http://[::1", "http://[::1"), "invalid urls should not be equal")
+}
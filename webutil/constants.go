@@ -34,6 +34,12 @@ var (
 	HeaderXXSSProtection          = http.CanonicalHeaderKey("X-Xss-Protection")
 	HeaderXContentTypeOptions     = http.CanonicalHeaderKey("X-Content-Type-Options")
 	HeaderStrictTransportSecurity = http.CanonicalHeaderKey("Strict-Transport-Security")
+	HeaderAuthorization           = http.CanonicalHeaderKey("Authorization")
+	HeaderWWWAuthenticate         = http.CanonicalHeaderKey("WWW-Authenticate")
+	HeaderRetryAfter              = http.CanonicalHeaderKey("Retry-After")
+	HeaderTraceParent             = http.CanonicalHeaderKey("traceparent")
+	HeaderTraceState              = http.CanonicalHeaderKey("tracestate")
+	HeaderXRequestID              = http.CanonicalHeaderKey("X-Request-Id")
 )
 
 /*
@@ -57,6 +63,9 @@ var (
 	protoRegex = regexp.MustCompile(`(?i)(?:proto=)(https|http)`)
 )
 
+// MaxRequestIDLength is the longest request id accepted from an inbound `X-Request-Id` header.
+const MaxRequestIDLength = 128
+
 // Well known schemes
 const (
 	SchemeHTTP  = "http"
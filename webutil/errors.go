@@ -4,5 +4,7 @@ import "github.com/blend/go-sdk/ex"
 
 // Errors
 const (
-	ErrInvalidSameSite ex.Class = "invalid cookie same site string value"
+	ErrInvalidSameSite    ex.Class = "invalid cookie same site string value"
+	ErrInvalidCIDR        ex.Class = "invalid cidr"
+	ErrInvalidTraceParent ex.Class = "invalid traceparent"
 )
@@ -0,0 +1,52 @@
+package webutil
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// GetClientIP returns the client ip for a request, only trusting forwarding headers set by trusted proxies.
+// It returns nil if the client ip can't be determined.
+func GetClientIP(r *http.Request, trustedProxies *IPAllowlist) net.IP {
+	if r == nil {
+		return nil
+	}
+	remoteIP := ParseIP(r.RemoteAddr)
+	if remoteIP == nil || !trustedProxies.Contains(remoteIP) {
+		return remoteIP
+	}
+
+	if forwardedFor := forwardedForValues(r.Header); len(forwardedFor) > 0 {
+		clientIP := remoteIP
+		for index := len(forwardedFor) - 1; index >= 0; index-- {
+			ip := ParseIP(forwardedFor[index])
+			if ip == nil {
+				// a malformed hop means we can't trust anything further out.
+				return clientIP
+			}
+			clientIP = ip
+			if !trustedProxies.Contains(ip) {
+				return clientIP
+			}
+		}
+		return clientIP
+	}
+	if realIP := ParseIP(r.Header.Get(HeaderXRealIP)); realIP != nil {
+		return realIP
+	}
+	return remoteIP
+}
+
+// forwardedForValues returns the addresses in the `X-Forwarded-For` headers,
+// in order, combining multiple headers.
+func forwardedForValues(header http.Header) (output []string) {
+	for _, value := range header[HeaderXForwardedFor] {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				output = append(output, part)
+			}
+		}
+	}
+	return
+}
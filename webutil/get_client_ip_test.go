@@ -0,0 +1,51 @@
+package webutil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestGetClientIP(t *testing.T) {
+	assert := assert.New(t)
+
+	trusted := MustIPAllowlist([]string{"10.0.0.0/8"})
+
+	assert.Nil(GetClientIP(nil, trusted))
+
+	// untrusted peers are returned as is.
+	r := &http.Request{RemoteAddr: "1.2.3.4:5678", Header: http.Header{}}
+	r.Header.Set(HeaderXForwardedFor, "5.6.7.8")
+	assert.Equal("1.2.3.4", GetClientIP(r, trusted).String())
+	assert.Equal("1.2.3.4", GetClientIP(r, nil).String())
+
+	// trusted peers use the nearest untrusted forwarded address.
+	r = &http.Request{RemoteAddr: "10.0.0.1:5678", Header: http.Header{}}
+	r.Header.Set(HeaderXForwardedFor, "9.9.9.9, 5.6.7.8, 10.0.0.2")
+	assert.Equal("5.6.7.8", GetClientIP(r, trusted).String())
+
+	// multiple headers are combined.
+	r = &http.Request{RemoteAddr: "10.0.0.1:5678", Header: http.Header{}}
+	r.Header.Add(HeaderXForwardedFor, "9.9.9.9")
+	r.Header.Add(HeaderXForwardedFor, "10.0.0.3")
+	assert.Equal("9.9.9.9", GetClientIP(r, trusted).String())
+
+	// all trusted returns the furthest hop.
+	r = &http.Request{RemoteAddr: "10.0.0.1:5678", Header: http.Header{}}
+	r.Header.Set(HeaderXForwardedFor, "10.0.0.4, 10.0.0.3")
+	assert.Equal("10.0.0.4", GetClientIP(r, trusted).String())
+
+	// malformed hops stop the walk.
+	r = &http.Request{RemoteAddr: "10.0.0.1:5678", Header: http.Header{}}
+	r.Header.Set(HeaderXForwardedFor, "9.9.9.9, garbage, 10.0.0.3")
+	assert.Equal("10.0.0.3", GetClientIP(r, trusted).String())
+
+	// x-real-ip is used if there is no x-forwarded-for.
+	r = &http.Request{RemoteAddr: "10.0.0.1:5678", Header: http.Header{}}
+	r.Header.Set(HeaderXRealIP, "5.6.7.8")
+	assert.Equal("5.6.7.8", GetClientIP(r, trusted).String())
+
+	r = &http.Request{RemoteAddr: "[::1]:5678", Header: http.Header{}}
+	assert.Equal("::1", GetClientIP(r, trusted).String())
+}
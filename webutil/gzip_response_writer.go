@@ -51,8 +51,10 @@ func (crw *GZipResponseWriter) Header() http.Header {
 }
 
 // WriteHeader writes a status code.
+// Any "Content-Length" header is removed, as it's the length of the uncompressed body.
 func (crw *GZipResponseWriter) WriteHeader(code int) {
 	crw.statusCode = code
+	crw.innerResponse.Header().Del(HeaderContentLength)
 	crw.innerResponse.WriteHeader(code)
 }
 
@@ -18,3 +18,13 @@ func TestBufferedCompressedWriter(t *testing.T) {
 	assert.Nil(err)
 	assert.NotZero(written)
 }
+
+func TestGZipResponseWriterRemovesContentLength(t *testing.T) {
+	assert := assert.New(t)
+
+	mockedWriter := NewMockResponse(bytes.NewBuffer(nil))
+	gzipWriter := NewGZipResponseWriter(mockedWriter)
+	gzipWriter.Header().Set(HeaderContentLength, "2")
+	gzipWriter.WriteHeader(200)
+	assert.Empty(mockedWriter.Header().Get(HeaderContentLength))
+}
@@ -0,0 +1,91 @@
+package webutil
+
+import (
+	"net"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// NewIPAllowlist returns a new ip allowlist from a given set of CIDRs, e.g. `10.0.0.0/8` or `fd00::/8`.
+// Bare addresses, e.g. `192.168.1.1` or `::1`, are treated as ranges containing only that address.
+// It returns an error if any of the CIDRs are malformed.
+func NewIPAllowlist(cidrs []string) (*IPAllowlist, error) {
+	allowlist := &IPAllowlist{}
+	for _, cidr := range cidrs {
+		network, err := parseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+		allowlist.Networks = append(allowlist.Networks, network)
+	}
+	return allowlist, nil
+}
+
+// MustIPAllowlist returns a new ip allowlist and panics on error.
+func MustIPAllowlist(cidrs []string) *IPAllowlist {
+	allowlist, err := NewIPAllowlist(cidrs)
+	if err != nil {
+		panic(err)
+	}
+	return allowlist
+}
+
+// IPAllowlist is a set of ip ranges, both IPv4 and IPv6.
+type IPAllowlist struct {
+	Networks []*net.IPNet
+}
+
+// IsEmpty returns if the allowlist has no ranges.
+func (ipa *IPAllowlist) IsEmpty() bool {
+	return ipa == nil || len(ipa.Networks) == 0
+}
+
+// Contains returns if an ip is in any of the ranges.
+func (ipa *IPAllowlist) Contains(ip net.IP) bool {
+	if ipa == nil || ip == nil {
+		return false
+	}
+	for _, network := range ipa.Networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAddr returns if an address, either a bare ip or a `host:port` (e.g. `http.Request.RemoteAddr`), is in any of the ranges.
+// It returns false if the address can't be parsed.
+func (ipa *IPAllowlist) ContainsAddr(remoteAddr string) bool {
+	return ipa.Contains(ParseIP(remoteAddr))
+}
+
+// ParseIP parses an ip from either a bare ip or a `host:port` address.
+// It returns nil if the address can't be parsed.
+func ParseIP(addr string) net.IP {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	// bracketed ipv6 addresses without a port, i.e. `[::1]`
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	return net.ParseIP(addr)
+}
+
+func parseCIDR(cidr string) (*net.IPNet, error) {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, ex.New(ErrInvalidCIDR, ex.OptMessagef("cidr: %q", cidr))
+		}
+		if ipv4 := ip.To4(); ipv4 != nil {
+			return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, ex.New(ErrInvalidCIDR, ex.OptMessagef("cidr: %q", cidr), ex.OptInner(err))
+	}
+	return network, nil
+}
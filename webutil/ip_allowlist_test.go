@@ -0,0 +1,81 @@
+package webutil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestIPAllowlistIPv4(t *testing.T) {
+	assert := assert.New(t)
+
+	allowlist, err := NewIPAllowlist([]string{"10.0.0.0/8", "192.168.1.0/24", "172.16.0.1"})
+	assert.Nil(err)
+	assert.Len(allowlist.Networks, 3)
+	assert.False(allowlist.IsEmpty())
+
+	assert.True(allowlist.Contains(net.ParseIP("10.1.2.3")))
+	assert.True(allowlist.Contains(net.ParseIP("192.168.1.0")))
+	assert.True(allowlist.Contains(net.ParseIP("192.168.1.255")))
+	assert.False(allowlist.Contains(net.ParseIP("192.168.0.255")))
+	assert.False(allowlist.Contains(net.ParseIP("192.168.2.0")))
+	assert.True(allowlist.Contains(net.ParseIP("172.16.0.1")))
+	assert.False(allowlist.Contains(net.ParseIP("172.16.0.2")))
+	assert.False(allowlist.Contains(nil))
+
+	// ipv4 mapped ipv6 addresses
+	assert.True(allowlist.Contains(net.ParseIP("::ffff:10.0.0.1")))
+}
+
+func TestIPAllowlistIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	allowlist, err := NewIPAllowlist([]string{"fd00::/8", "2001:db8::/32", "::1"})
+	assert.Nil(err)
+
+	assert.True(allowlist.Contains(net.ParseIP("fd12:3456::1")))
+	assert.True(allowlist.Contains(net.ParseIP("2001:db8::")))
+	assert.True(allowlist.Contains(net.ParseIP("2001:db8:ffff:ffff:ffff:ffff:ffff:ffff")))
+	assert.False(allowlist.Contains(net.ParseIP("2001:db9::")))
+	assert.True(allowlist.Contains(net.ParseIP("::1")))
+	assert.False(allowlist.Contains(net.ParseIP("::2")))
+	assert.False(allowlist.Contains(net.ParseIP("10.0.0.1")))
+}
+
+func TestIPAllowlistContainsAddr(t *testing.T) {
+	assert := assert.New(t)
+
+	allowlist := MustIPAllowlist([]string{"10.0.0.0/8", "::1/128"})
+	assert.True(allowlist.ContainsAddr("10.0.0.1"))
+	assert.True(allowlist.ContainsAddr("10.0.0.1:8080"))
+	assert.True(allowlist.ContainsAddr("[::1]:8080"))
+	assert.True(allowlist.ContainsAddr("[::1]"))
+	assert.False(allowlist.ContainsAddr("11.0.0.1:8080"))
+	assert.False(allowlist.ContainsAddr("not an ip"))
+	assert.False(allowlist.ContainsAddr(""))
+}
+
+func TestIPAllowlistInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, cidr := range []string{"10.0.0.0/33", "10.0.0/8", "fd00::/129", "not a cidr", ""} {
+		allowlist, err := NewIPAllowlist([]string{"10.0.0.0/8", cidr})
+		assert.Nil(allowlist)
+		assert.True(ex.Is(err, ErrInvalidCIDR), cidr)
+	}
+}
+
+func TestIPAllowlistEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	var allowlist *IPAllowlist
+	assert.True(allowlist.IsEmpty())
+	assert.False(allowlist.Contains(net.ParseIP("10.0.0.1")))
+
+	allowlist, err := NewIPAllowlist(nil)
+	assert.Nil(err)
+	assert.True(allowlist.IsEmpty())
+	assert.False(allowlist.ContainsAddr("10.0.0.1"))
+}
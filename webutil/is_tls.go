@@ -0,0 +1,51 @@
+package webutil
+
+import "net/http"
+
+// IsTLSOption mutates an is tls config.
+type IsTLSOption func(*IsTLSConfig)
+
+// OptIsTLSTrustForwardedProto sets if the forwarded protocol headers should be trusted from any peer.
+func OptIsTLSTrustForwardedProto(trust bool) IsTLSOption {
+	return func(cfg *IsTLSConfig) { cfg.TrustForwardedProto = trust }
+}
+
+// OptIsTLSTrustedProxies sets the proxies the forwarded protocol headers are trusted from.
+func OptIsTLSTrustedProxies(trustedProxies *IPAllowlist) IsTLSOption {
+	return func(cfg *IsTLSConfig) { cfg.TrustedProxies = trustedProxies }
+}
+
+// IsTLSConfig determines which forwarded protocol headers are trusted by `IsTLS`.
+type IsTLSConfig struct {
+	// TrustForwardedProto trusts the forwarded protocol headers regardless of the peer.
+	TrustForwardedProto bool
+	// TrustedProxies trusts the forwarded protocol headers only if the peer is in the allowlist.
+	TrustedProxies *IPAllowlist
+}
+
+// TrustsForwardedProto returns if the forwarded protocol headers should be trusted for a given request.
+func (cfg IsTLSConfig) TrustsForwardedProto(r *http.Request) bool {
+	if cfg.TrustForwardedProto {
+		return true
+	}
+	return cfg.TrustedProxies.ContainsAddr(r.RemoteAddr)
+}
+
+// IsTLS returns if the original request was made over https.
+// Forwarded protocol headers are ignored unless they're trusted with an option.
+func IsTLS(r *http.Request, options ...IsTLSOption) bool {
+	if r == nil {
+		return false
+	}
+	if r.TLS != nil {
+		return true
+	}
+	var cfg IsTLSConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+	if !cfg.TrustsForwardedProto(r) {
+		return false
+	}
+	return GetProto(r) == SchemeHTTPS
+}
@@ -0,0 +1,40 @@
+package webutil
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestIsTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(IsTLS(nil))
+
+	direct := &http.Request{RemoteAddr: "10.0.0.1:443", Header: http.Header{}, TLS: &tls.ConnectionState{}}
+	assert.True(IsTLS(direct))
+
+	plain := &http.Request{RemoteAddr: "10.0.0.1:80", Header: http.Header{}}
+	assert.False(IsTLS(plain))
+	assert.False(IsTLS(plain, OptIsTLSTrustForwardedProto(true)))
+}
+
+func TestIsTLSForwarded(t *testing.T) {
+	assert := assert.New(t)
+
+	headers := http.Header{}
+	headers.Set(HeaderXForwardedProto, SchemeHTTPS)
+	proxied := &http.Request{RemoteAddr: "10.0.0.1:80", Header: headers}
+
+	assert.False(IsTLS(proxied), "the forwarded header should not be trusted by default")
+	assert.True(IsTLS(proxied, OptIsTLSTrustForwardedProto(true)))
+	assert.True(IsTLS(proxied, OptIsTLSTrustedProxies(MustIPAllowlist([]string{"10.0.0.0/8"}))))
+	assert.False(IsTLS(proxied, OptIsTLSTrustedProxies(MustIPAllowlist([]string{"192.168.0.0/16"}))))
+
+	headers = http.Header{}
+	headers.Set(HeaderXForwardedProto, SchemeHTTP)
+	downgraded := &http.Request{RemoteAddr: "10.0.0.1:80", Header: headers}
+	assert.False(IsTLS(downgraded, OptIsTLSTrustForwardedProto(true)))
+}
@@ -0,0 +1,143 @@
+package webutil
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// TraceFlagSampled is the trace flag set if the trace is sampled, i.e. recorded by the caller.
+const TraceFlagSampled byte = 0x01
+
+// TraceContext is the W3C trace context (https://www.w3.org/TR/trace-context/) of the active span,
+// as sent in the `traceparent` and `tracestate` headers.
+type TraceContext struct {
+	// TraceID is the 32 character lowercase hex id of the trace.
+	TraceID string
+	// SpanID is the 16 character lowercase hex id of the active span,
+	// which is the parent id of outbound requests.
+	SpanID string
+	// Flags are the trace flags, e.g. `TraceFlagSampled`.
+	Flags byte
+	// State is the vendor specific `tracestate`, which is passed through as is.
+	State string
+}
+
+// ParseTraceContext parses the `traceparent` and `tracestate` header values.
+// Versions after `00` are parsed by their `00` prefix, as the spec requires.
+func ParseTraceContext(traceParent, traceState string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || !isTraceHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return TraceContext{}, ex.New(ErrInvalidTraceParent, ex.OptMessagef("traceparent: %q", traceParent))
+	}
+	tc := TraceContext{
+		TraceID: parts[1],
+		SpanID:  parts[2],
+		State:   strings.TrimSpace(traceState),
+	}
+	if !isTraceHex(parts[3], 2) || !tc.IsValid() {
+		return TraceContext{}, ex.New(ErrInvalidTraceParent, ex.OptMessagef("traceparent: %q", traceParent))
+	}
+	flags, _ := hex.DecodeString(parts[3])
+	tc.Flags = flags[0]
+	return tc, nil
+}
+
+// IsValid returns if the trace and span ids are well formed and not all zeros.
+func (tc TraceContext) IsValid() bool {
+	return isTraceHex(tc.TraceID, 32) && strings.Trim(tc.TraceID, "0") != "" &&
+		isTraceHex(tc.SpanID, 16) && strings.Trim(tc.SpanID, "0") != ""
+}
+
+// Sampled returns if the sampled flag is set.
+func (tc TraceContext) Sampled() bool {
+	return tc.Flags&TraceFlagSampled != 0
+}
+
+// TraceParent returns the `traceparent` header value, e.g. `00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`.
+func (tc TraceContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", tc.TraceID, tc.SpanID, tc.Flags)
+}
+
+// isTraceHex returns if a value is lowercase hex of a given length.
+func isTraceHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, c := range value {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+type traceContextKey struct{}
+
+// WithTraceContext adds the trace context of the active span to a context.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// GetTraceContext gets the trace context of the active span off a context.
+func GetTraceContext(ctx context.Context) (tc TraceContext, ok bool) {
+	tc, ok = ctx.Value(traceContextKey{}).(TraceContext)
+	return
+}
+
+type requestIDKey struct{}
+
+// WithRequestID adds a request id to a context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// IsValidRequestID returns if a request id, e.g. from an inbound `X-Request-Id` header, is safe to log and propagate,
+// i.e. it's at most `MaxRequestIDLength` letters, digits, or any of `-_.:+/=`.
+func IsValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > MaxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && !strings.ContainsRune("-_.:+/=", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetRequestID gets a request id off a context.
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// OptTraceContext sets the trace context headers of a request from a context, so the request
+// is part of the same trace, and has the same request id, as the request that's being handled.
+func OptTraceContext(ctx context.Context) RequestOption {
+	return func(r *http.Request) error {
+		if ctx == nil {
+			return nil
+		}
+		if tc, ok := GetTraceContext(ctx); ok && tc.IsValid() {
+			if r.Header == nil {
+				r.Header = make(http.Header)
+			}
+			r.Header.Set(HeaderTraceParent, tc.TraceParent())
+			if tc.State != "" {
+				r.Header.Set(HeaderTraceState, tc.State)
+			}
+		}
+		if requestID := GetRequestID(ctx); requestID != "" {
+			if r.Header == nil {
+				r.Header = make(http.Header)
+			}
+			r.Header.Set(HeaderXRequestID, requestID)
+		}
+		return nil
+	}
+}
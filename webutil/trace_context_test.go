@@ -0,0 +1,73 @@
+package webutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestParseTraceContext(t *testing.T) {
+	assert := assert.New(t)
+
+	tc, err := ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", " congo=t61rcWkgMzE ")
+	assert.Nil(err)
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Equal("00f067aa0ba902b7", tc.SpanID)
+	assert.True(tc.Sampled())
+	assert.Equal("congo=t61rcWkgMzE", tc.State)
+	assert.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tc.TraceParent())
+
+	// future versions can have more fields.
+	tc, err = ParseTraceContext("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra", "")
+	assert.Nil(err)
+	assert.False(tc.Sampled())
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	} {
+		_, err = ParseTraceContext(invalid, "")
+		assert.True(ex.Is(err, ErrInvalidTraceParent), invalid)
+	}
+}
+
+func TestOptTraceContext(t *testing.T) {
+	assert := assert.New(t)
+
+	tc := TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	ctx := WithTraceContext(context.Background(), tc)
+	got, ok := GetTraceContext(ctx)
+	assert.True(ok)
+	assert.Equal(tc, got)
+
+	req := NewMockRequest("GET", "/")
+	assert.Nil(OptTraceContext(ctx)(req))
+	assert.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", req.Header.Get(HeaderTraceParent))
+	assert.Empty(req.Header.Get(HeaderTraceState))
+	assert.Empty(req.Header.Get(HeaderXRequestID))
+
+	req = NewMockRequest("GET", "/")
+	assert.Nil(OptTraceContext(WithTraceContext(context.Background(), TraceContext{}))(req))
+	assert.Empty(req.Header.Get(HeaderTraceParent), "invalid trace contexts should not be propagated")
+}
+
+func TestIsValidRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsValidRequestID("request-1234"))
+	assert.True(IsValidRequestID("9f1c2b3a-0d4e-4f5a-8b6c-7d8e9f0a1b2c"))
+	assert.True(IsValidRequestID("Root=1-5759e988-bd862e3fe1be46a994272793"))
+	assert.False(IsValidRequestID(""))
+	assert.False(IsValidRequestID("request 1234"))
+	assert.False(IsValidRequestID("request-1234\nfake=log"))
+	assert.False(IsValidRequestID(strings.Repeat("a", MaxRequestIDLength+1)))
+}